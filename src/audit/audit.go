@@ -0,0 +1,141 @@
+// Package audit records the Changesets the generated models hand to a
+// changes.Tx as rows of the audit_log table.
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"changes"
+	"models"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// Table is the table Sink writes to, created by the migrations.
+const Table = "audit_log"
+
+// Sink records the Changesets of a changes.Tx as rows of audit_log.
+type Sink struct {
+	d *dialect
+}
+
+var _ changes.Sink = &Sink{}
+
+// NewSink returns a Sink writing to a database of the given database/sql
+// driver: mysql, postgres or mssql.
+func NewSink(driver string) (*Sink, error) {
+	d, err := dialectOf(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sink{d: d}, nil
+}
+
+// Write implements changes.Sink, with a row of audit_log per Changeset.
+func (s *Sink) Write(ctx context.Context, tx *sql.Tx, chs []*models.Changeset) error {
+	for _, ch := range chs {
+		if err := s.write(ctx, tx, ch); err != nil {
+			return errors.Wrap(err, "audit: unable to write audit_log")
+		}
+	}
+
+	return nil
+}
+
+func (s *Sink) write(ctx context.Context, tx *sql.Tx, ch *models.Changeset) error {
+	before, after := values(ch)
+
+	beforeJSON, err := marshal(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshal(after)
+	if err != nil {
+		return err
+	}
+
+	pk, err := primaryKey(ch)
+	if err != nil {
+		return err
+	}
+
+	changedAt := ch.ChangedAt
+	if changedAt.IsZero() {
+		changedAt = time.Now().In(boil.GetLocation())
+	}
+
+	args := []interface{}{ch.Table, pk, ch.Operation, beforeJSON, afterJSON, nullString(ch.Actor), nullString(ch.RequestID), changedAt}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, s.d.insert)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+
+	_, err = tx.ExecContext(ctx, s.d.insert, args...)
+	return err
+}
+
+// values splits the changed columns into their before and after values.
+// An INSERT has no before and a DELETE no after.
+func values(ch *models.Changeset) (before, after map[string]interface{}) {
+	if ch.Operation != "INSERT" {
+		before = make(map[string]interface{}, len(ch.Changes))
+	}
+	if ch.Operation != "DELETE" {
+		after = make(map[string]interface{}, len(ch.Changes))
+	}
+
+	for _, item := range ch.Changes {
+		if before != nil {
+			before[item.Name] = item.Before
+		}
+		if after != nil {
+			after[item.Name] = item.After
+		}
+	}
+
+	return before, after
+}
+
+// primaryKey formats the primary key of the changed row: the bare value for
+// a single column key, a JSON object for a composite one.
+func primaryKey(ch *models.Changeset) (sql.NullString, error) {
+	switch len(ch.PrimaryKey) {
+	case 0:
+		return sql.NullString{}, nil
+	case 1:
+		for _, v := range ch.PrimaryKey {
+			return sql.NullString{String: fmt.Sprint(v), Valid: true}, nil
+		}
+	}
+
+	b, err := json.Marshal(ch.PrimaryKey)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func marshal(v map[string]interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
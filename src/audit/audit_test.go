@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"changes"
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		driver string
+		insert string
+	}{
+		{"mysql", "INSERT INTO `audit_log` (`table_name`, `primary_key`, `operation`, `before_data`, `after_data`, `actor`, `request_id`, `created_at`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"},
+		{"postgres", `INSERT INTO "audit_log" ("table_name", "primary_key", "operation", "before_data", "after_data", "actor", "request_id", "created_at") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`},
+		{"mssql", "INSERT INTO [audit_log] ([table_name], [primary_key], [operation], [before_data], [after_data], [actor], [request_id], [created_at]) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"},
+	}
+
+	for _, test := range tests {
+		s, err := NewSink(test.driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(test.insert)).
+			WithArgs("book", "1", "UPDATE", `{"name":"a"}`, `{"name":"b"}`, "alice", "req-1", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(test.insert)).
+			WithArgs("book", `{"id":2,"shelf_id":3}`, "INSERT", nil, `{"name":"c"}`, nil, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		tx, err := changes.Begin(db, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tx.AddChange(
+			&models.Changeset{
+				Table:      "book",
+				PrimaryKey: map[string]interface{}{"id": 1},
+				Operation:  "UPDATE",
+				Changes:    []*models.ChangeItem{{Name: "name", Before: "a", After: "b"}},
+				Actor:      "alice",
+				RequestID:  "req-1",
+			},
+			&models.Changeset{
+				Table:      "book",
+				PrimaryKey: map[string]interface{}{"id": 2, "shelf_id": 3},
+				Operation:  "INSERT",
+				Changes:    []*models.ChangeItem{{Name: "name", After: "c"}},
+			},
+		)
+		if err := tx.Commit(); err != nil {
+			t.Errorf("%s: %v", test.driver, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", test.driver, err)
+		}
+		db.Close()
+	}
+}
+
+func TestSinkFails(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewSink("mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `audit_log`").WillReturnError(errors.New("table full"))
+	mock.ExpectRollback()
+
+	tx, err := changes.BeginContext(context.Background(), db, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx.AddChange(&models.Changeset{
+		Table:     "book",
+		Operation: "DELETE",
+		Changes:   []*models.ChangeItem{{Name: "name", Before: "a"}},
+	})
+	if err := tx.Commit(); err == nil {
+		t.Error("want an error when audit_log can't be written")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewSinkUnsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSink("sqlite3"); err == nil {
+		t.Error("want an error for a driver without a dialect")
+	}
+}
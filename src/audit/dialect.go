@@ -0,0 +1,32 @@
+package audit
+
+import "fmt"
+
+// dialect holds the statements that differ between databases.
+type dialect struct {
+	insert string
+}
+
+var dialects = map[string]*dialect{
+	"mysql": {
+		insert: "INSERT INTO `" + Table + "` (`table_name`, `primary_key`, `operation`, `before_data`, `after_data`, `actor`, `request_id`, `created_at`) " +
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+	},
+	"postgres": {
+		insert: `INSERT INTO "` + Table + `" ("table_name", "primary_key", "operation", "before_data", "after_data", "actor", "request_id", "created_at") ` +
+			`VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+	},
+	"mssql": {
+		insert: "INSERT INTO [" + Table + "] ([table_name], [primary_key], [operation], [before_data], [after_data], [actor], [request_id], [created_at]) " +
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+	},
+}
+
+func dialectOf(driver string) (*dialect, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("audit: unsupported driver %q", driver)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,102 @@
+// Package changes provides Tx, a transaction that collects the Changesets
+// the generated models hand to a Changeable executor, and records them with
+// the sinks it was begun with as it commits, such as audit and outbox.
+package changes
+
+import (
+	"context"
+	"database/sql"
+
+	"models"
+)
+
+// Sink records the Changesets of a transaction in it, before it commits.
+type Sink interface {
+	Write(ctx context.Context, tx *sql.Tx, chs []*models.Changeset) error
+}
+
+// Beginner starts transactions, as *sql.DB and *sqlx.DB do.
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Tx is a transaction that collects the Changesets of every model written
+// through it, and hands them to its sinks when it commits. Pass the Tx
+// itself, not the *sql.Tx it wraps, as the executor of model methods.
+//
+// Tx is a models.ContextExecutor: the actor and request ID of its context
+// are recorded with every change.
+type Tx struct {
+	*sql.Tx
+
+	ctx     context.Context
+	sinks   []Sink
+	changes []*models.Changeset
+}
+
+var (
+	_ models.Changeable      = &Tx{}
+	_ models.ContextExecutor = &Tx{}
+)
+
+// Begin starts a Tx on db recording its changes with sinks.
+func Begin(db Beginner, sinks ...Sink) (*Tx, error) {
+	return BeginContext(context.Background(), db, sinks...)
+}
+
+// BeginContext starts a Tx on db recording its changes with sinks, and
+// attributing them to the actor and request ID of ctx. The transaction is
+// rolled back when ctx is done.
+func BeginContext(ctx context.Context, db Beginner, sinks ...Sink) (*Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx, ctx: ctx, sinks: sinks}, nil
+}
+
+// Context implements models.ContextExecutor.
+func (tx *Tx) Context() context.Context {
+	return tx.ctx
+}
+
+// AddChange implements models.Changeable. Changesets without changes are
+// dropped.
+func (tx *Tx) AddChange(chs ...*models.Changeset) {
+	for _, ch := range chs {
+		if ch == nil || len(ch.Changes) == 0 {
+			continue
+		}
+		tx.changes = append(tx.changes, ch)
+	}
+}
+
+// Changes returns the Changesets collected so far.
+func (tx *Tx) Changes() []*models.Changeset {
+	return tx.changes
+}
+
+// Commit hands the collected Changesets to each sink in turn, then commits.
+// If a sink fails the transaction is rolled back, so that no change goes
+// unrecorded.
+func (tx *Tx) Commit() error {
+	if len(tx.changes) != 0 {
+		for _, s := range tx.sinks {
+			if err := s.Write(tx.ctx, tx.Tx, tx.changes); err != nil {
+				tx.Tx.Rollback()
+				tx.changes = nil
+				return err
+			}
+		}
+	}
+
+	tx.changes = nil
+	return tx.Tx.Commit()
+}
+
+// Rollback discards the collected Changesets along with the transaction.
+func (tx *Tx) Rollback() error {
+	tx.changes = nil
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,160 @@
+package changes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// recorder is a Sink keeping the Changesets it was handed.
+type recorder struct {
+	chs [][]*models.Changeset
+	err error
+}
+
+func (r *recorder) Write(ctx context.Context, tx *sql.Tx, chs []*models.Changeset) error {
+	r.chs = append(r.chs, chs)
+	return r.err
+}
+
+func change(table string) *models.Changeset {
+	return &models.Changeset{
+		Table:     table,
+		Operation: "INSERT",
+		Changes:   []*models.ChangeItem{{Name: "name", After: "a"}},
+	}
+}
+
+func begin(t *testing.T, sinks ...Sink) (*Tx, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectBegin()
+	tx, err := Begin(db, sinks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tx, mock
+}
+
+func TestTxCommit(t *testing.T) {
+	t.Parallel()
+
+	first, second := &recorder{}, &recorder{}
+	tx, mock := begin(t, first, second)
+	mock.ExpectCommit()
+
+	tx.AddChange(change("book"), nil, &models.Changeset{Table: "shelf"}, change("shelf"))
+	if len(tx.Changes()) != 2 {
+		t.Fatalf("want the 2 Changesets with changes collected, got %d", len(tx.Changes()))
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []*recorder{first, second} {
+		if len(r.chs) != 1 || len(r.chs[0]) != 2 {
+			t.Fatalf("want each sink handed the 2 Changesets once, got %v", r.chs)
+		}
+	}
+	if len(tx.Changes()) != 0 {
+		t.Error("want no Changesets left after Commit")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTxCommitWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	tx, mock := begin(t, r)
+	mock.ExpectCommit()
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.chs) != 0 {
+		t.Errorf("want the sink left alone, got %v", r.chs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTxCommitSinkFails(t *testing.T) {
+	t.Parallel()
+
+	failing := &recorder{err: errors.New("disk full")}
+	next := &recorder{}
+	tx, mock := begin(t, failing, next)
+	mock.ExpectRollback()
+
+	tx.AddChange(change("book"))
+	if err := tx.Commit(); err != failing.err {
+		t.Fatalf("want the error of the sink, got %v", err)
+	}
+
+	if len(next.chs) != 0 {
+		t.Errorf("want the sinks after the failing one skipped, got %v", next.chs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{}
+	tx, mock := begin(t, r)
+	mock.ExpectRollback()
+
+	tx.AddChange(change("book"))
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.chs) != 0 || len(tx.Changes()) != 0 {
+		t.Errorf("want the Changesets discarded, got %v in the sink and %v left", r.chs, tx.Changes())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTxContext(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	ctx := models.WithRequestID(models.WithActor(context.Background(), "alice"), "req-1")
+	tx, err := BeginContext(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := models.ActorFrom(tx.Context()); got != "alice" {
+		t.Errorf("want actor alice, got %q", got)
+	}
+	if got := models.RequestIDFrom(tx.Context()); got != "req-1" {
+		t.Errorf("want request ID req-1, got %q", got)
+	}
+}
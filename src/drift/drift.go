@@ -0,0 +1,192 @@
+// Package drift compares the live schema of a database with the schema the
+// models were generated from, so that a service can refuse to run against a
+// database its models no longer match.
+package drift
+
+import (
+	"fmt"
+	"sort"
+
+	"models"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/bdb"
+)
+
+// Kind is the kind of a Change.
+type Kind string
+
+// The kinds of Changes.
+const (
+	TableMissing       Kind = "table missing"
+	ColumnAdded        Kind = "column added"
+	ColumnRemoved      Kind = "column removed"
+	ColumnRetyped      Kind = "column retyped"
+	NullabilityChanged Kind = "nullability changed"
+	ForeignKeyMissing  Kind = "foreign key missing"
+)
+
+// Change is a difference between the database and the generated models.
+// Want describes what the models expect and Got what the database has.
+type Change struct {
+	Kind   Kind
+	Table  string
+	Column string
+	Want   string
+	Got    string
+}
+
+func (c Change) String() string {
+	name := c.Table
+	if c.Column != "" {
+		name += "." + c.Column
+	}
+
+	switch {
+	case c.Want != "" && c.Got != "":
+		return fmt.Sprintf("%s: %s, want %s, got %s", name, c.Kind, c.Want, c.Got)
+	case c.Want != "":
+		return fmt.Sprintf("%s: %s, want %s", name, c.Kind, c.Want)
+	case c.Got != "":
+		return fmt.Sprintf("%s: %s, got %s", name, c.Kind, c.Got)
+	}
+
+	return fmt.Sprintf("%s: %s", name, c.Kind)
+}
+
+// Error is returned by Guard when the schema drifted.
+type Error []Change
+
+func (e Error) Error() string {
+	msg := fmt.Sprintf("drift: the database schema differs from the models in %d ways", len(e))
+	for _, c := range e {
+		msg += "\n\t" + c.String()
+	}
+
+	return msg
+}
+
+// Check introspects the tables of models.SchemaTables in schema through db,
+// which it opens and closes, and returns how they differ from the models.
+// The schema is the database name for MySQL, and usually public for
+// Postgres and dbo for MSSQL.
+func Check(db bdb.Interface, schema string) ([]Change, error) {
+	names := make([]string, 0, len(models.SchemaTables))
+	for name := range models.SchemaTables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if err := db.Open(); err != nil {
+		return nil, errors.Wrap(err, "drift: unable to connect")
+	}
+	defer db.Close()
+
+	tables, err := bdb.Tables(db, schema, names, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "drift: unable to read schema")
+	}
+
+	live := map[string]bdb.Table{}
+	for _, t := range tables {
+		live[t.Name] = t
+	}
+
+	var changes []Change
+	for _, name := range names {
+		t, ok := live[name]
+		if !ok {
+			changes = append(changes, Change{Kind: TableMissing, Table: name})
+			continue
+		}
+
+		changes = append(changes, Compare(models.SchemaTables[name], t)...)
+	}
+
+	return changes, nil
+}
+
+// Guard is Check for startup: it fails with an Error when the schema
+// drifted.
+func Guard(db bdb.Interface, schema string) error {
+	changes, err := Check(db, schema)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return Error(changes)
+	}
+
+	return nil
+}
+
+// Compare returns how the live table t differs from the generated want.
+func Compare(want models.SchemaTable, t bdb.Table) []Change {
+	var changes []Change
+
+	got := map[string]bdb.Column{}
+	for _, c := range t.Columns {
+		got[c.Name] = c
+	}
+
+	for _, c := range want.Columns {
+		g, ok := got[c.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: ColumnRemoved, Table: want.Name, Column: c.Name, Want: c.DBType})
+			continue
+		}
+		delete(got, c.Name)
+
+		// The Go type of a column follows its nullability, so a column
+		// that only changed nullability is not reported as retyped.
+		if g.DBType != c.DBType || (g.Nullable == c.Nullable && g.Type != c.Type) {
+			changes = append(changes, Change{
+				Kind: ColumnRetyped, Table: want.Name, Column: c.Name,
+				Want: c.DBType + " (" + c.Type + ")",
+				Got:  g.DBType + " (" + g.Type + ")",
+			})
+		}
+		if g.Nullable != c.Nullable {
+			changes = append(changes, Change{
+				Kind: NullabilityChanged, Table: want.Name, Column: c.Name,
+				Want: nullability(c.Nullable), Got: nullability(g.Nullable),
+			})
+		}
+	}
+
+	for _, c := range t.Columns {
+		if _, ok := got[c.Name]; ok {
+			changes = append(changes, Change{Kind: ColumnAdded, Table: want.Name, Column: c.Name, Got: c.DBType})
+		}
+	}
+
+	for _, fk := range want.FKeys {
+		if !hasForeignKey(t.FKeys, fk) {
+			changes = append(changes, Change{
+				Kind: ForeignKeyMissing, Table: want.Name, Column: fk.Column,
+				Want: fk.ForeignTable + "." + fk.ForeignColumn,
+			})
+		}
+	}
+
+	return changes
+}
+
+// hasForeignKey tells whether fks has a key like fk, whatever its name.
+func hasForeignKey(fks []bdb.ForeignKey, fk models.SchemaForeignKey) bool {
+	for _, k := range fks {
+		if k.Column == fk.Column && k.ForeignTable == fk.ForeignTable && k.ForeignColumn == fk.ForeignColumn {
+			return true
+		}
+	}
+
+	return false
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+
+	return "NOT NULL"
+}
@@ -0,0 +1,183 @@
+package drift
+
+import (
+	"reflect"
+	"testing"
+
+	"models"
+
+	"github.com/vattle/sqlboiler/bdb"
+)
+
+var book = models.SchemaTable{
+	Name: "book",
+	Columns: []models.SchemaColumn{
+		{Name: "id", Type: "int64", DBType: "bigint", Nullable: false},
+		{Name: "name", Type: "string", DBType: "varchar", Nullable: false},
+		{Name: "shelf_id", Type: "null.Int64", DBType: "bigint", Nullable: true},
+	},
+	PKey: []string{"id"},
+	FKeys: []models.SchemaForeignKey{
+		{Name: "book_shelf_id_fk", Column: "shelf_id", ForeignTable: "shelf", ForeignColumn: "id"},
+	},
+}
+
+// live returns the table the models of book were generated from, changed
+// by fn.
+func live(fn func(t *bdb.Table)) bdb.Table {
+	t := bdb.Table{
+		Name: "book",
+		Columns: []bdb.Column{
+			{Name: "id", Type: "int64", DBType: "bigint", Nullable: false},
+			{Name: "name", Type: "string", DBType: "varchar", Nullable: false},
+			{Name: "shelf_id", Type: "null.Int64", DBType: "bigint", Nullable: true},
+		},
+		FKeys: []bdb.ForeignKey{
+			{Name: "fk_1", Column: "shelf_id", ForeignTable: "shelf", ForeignColumn: "id"},
+		},
+	}
+	if fn != nil {
+		fn(&t)
+	}
+
+	return t
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		live bdb.Table
+		want []Change
+	}{
+		// A foreign key is matched whatever its name
+		{"same", live(nil), nil},
+		{"column added", live(func(t *bdb.Table) {
+			t.Columns = append(t.Columns, bdb.Column{Name: "isbn", Type: "string", DBType: "char"})
+		}), []Change{
+			{Kind: ColumnAdded, Table: "book", Column: "isbn", Got: "char"},
+		}},
+		{"column removed", live(func(t *bdb.Table) {
+			t.Columns = t.Columns[:2]
+			t.FKeys = nil
+		}), []Change{
+			{Kind: ColumnRemoved, Table: "book", Column: "shelf_id", Want: "bigint"},
+			{Kind: ForeignKeyMissing, Table: "book", Column: "shelf_id", Want: "shelf.id"},
+		}},
+		{"column retyped", live(func(t *bdb.Table) {
+			t.Columns[1] = bdb.Column{Name: "name", Type: "string", DBType: "text"}
+		}), []Change{
+			{Kind: ColumnRetyped, Table: "book", Column: "name", Want: "varchar (string)", Got: "text (string)"},
+		}},
+		{"column retyped in Go", live(func(t *bdb.Table) {
+			t.Columns[0] = bdb.Column{Name: "id", Type: "uint64", DBType: "bigint"}
+		}), []Change{
+			{Kind: ColumnRetyped, Table: "book", Column: "id", Want: "bigint (int64)", Got: "bigint (uint64)"},
+		}},
+		// The Go type following the nullability does not retype the column
+		{"column made nullable", live(func(t *bdb.Table) {
+			t.Columns[1] = bdb.Column{Name: "name", Type: "null.String", DBType: "varchar", Nullable: true}
+		}), []Change{
+			{Kind: NullabilityChanged, Table: "book", Column: "name", Want: "NOT NULL", Got: "NULL"},
+		}},
+		{"column made not nullable", live(func(t *bdb.Table) {
+			t.Columns[2] = bdb.Column{Name: "shelf_id", Type: "int64", DBType: "bigint"}
+		}), []Change{
+			{Kind: NullabilityChanged, Table: "book", Column: "shelf_id", Want: "NULL", Got: "NOT NULL"},
+		}},
+		{"column retyped and made nullable", live(func(t *bdb.Table) {
+			t.Columns[1] = bdb.Column{Name: "name", Type: "null.String", DBType: "text", Nullable: true}
+		}), []Change{
+			{Kind: ColumnRetyped, Table: "book", Column: "name", Want: "varchar (string)", Got: "text (null.String)"},
+			{Kind: NullabilityChanged, Table: "book", Column: "name", Want: "NOT NULL", Got: "NULL"},
+		}},
+		{"foreign key retargeted", live(func(t *bdb.Table) {
+			t.FKeys[0].ForeignTable = "case"
+		}), []Change{
+			{Kind: ForeignKeyMissing, Table: "book", Column: "shelf_id", Want: "shelf.id"},
+		}},
+	}
+
+	for _, test := range tests {
+		if got := Compare(book, test.live); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: want %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+// fakeDB is a database holding tables, as their models expect them.
+type fakeDB struct {
+	bdb.Interface
+	tables map[string]models.SchemaTable
+	closed bool
+}
+
+func (db *fakeDB) TableNames(schema string, whitelist, blacklist []string) ([]string, error) {
+	var names []string
+	for _, name := range whitelist {
+		if _, ok := db.tables[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func (db *fakeDB) Columns(schema, table string) ([]bdb.Column, error) {
+	var cols []bdb.Column
+	for _, c := range db.tables[table].Columns {
+		cols = append(cols, bdb.Column{Name: c.Name, Type: c.Type, DBType: c.DBType, Nullable: c.Nullable})
+	}
+
+	return cols, nil
+}
+
+func (db *fakeDB) ForeignKeyInfo(schema, table string) ([]bdb.ForeignKey, error) {
+	var fks []bdb.ForeignKey
+	for _, fk := range db.tables[table].FKeys {
+		fks = append(fks, bdb.ForeignKey{Name: fk.Name, Column: fk.Column, ForeignTable: fk.ForeignTable, ForeignColumn: fk.ForeignColumn})
+	}
+
+	return fks, nil
+}
+
+func (db *fakeDB) PrimaryKeyInfo(schema, table string) (*bdb.PrimaryKey, error) { return nil, nil }
+func (db *fakeDB) TranslateColumnType(c bdb.Column) bdb.Column                  { return c }
+func (db *fakeDB) Open() error                                                  { return nil }
+func (db *fakeDB) Close()                                                       { db.closed = true }
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	db := &fakeDB{tables: map[string]models.SchemaTable{}}
+	for name, table := range models.SchemaTables {
+		db.tables[name] = table
+	}
+	if err := Guard(db, "hello"); err != nil {
+		t.Errorf("want no drift, got %v", err)
+	}
+
+	// A missing table is reported, and not compared
+	missing := ""
+	for name := range db.tables {
+		missing = name
+		break
+	}
+	delete(db.tables, missing)
+
+	changes, err := Check(db, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{{Kind: TableMissing, Table: missing}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("want %v, got %v", want, changes)
+	}
+	if err, ok := Guard(db, "hello").(Error); !ok || !reflect.DeepEqual([]Change(err), want) {
+		t.Errorf("want an Error of %v, got %v", want, err)
+	}
+	if !db.closed {
+		t.Error("want the database closed")
+	}
+}
@@ -1,7 +1,60 @@
 package api
 
-import "github.com/julienschmidt/httprouter"
+import (
+	"net/http"
+
+	"hello/errors"
+
+	"github.com/julienschmidt/httprouter"
+)
 
 type API interface {
-	Bind(r *httprouter.Router) error
+	Bind(r Router) error
+}
+
+// Router is what an API binds its routes to. *httprouter.Router is one;
+// Routes is another, which lists the routes instead of serving them.
+type Router interface {
+	GET(path string, handle httprouter.Handle)
+	POST(path string, handle httprouter.Handle)
+	PUT(path string, handle httprouter.Handle)
+	PATCH(path string, handle httprouter.Handle)
+	DELETE(path string, handle httprouter.Handle)
+}
+
+var _ Router = &httprouter.Router{}
+
+// Route is a method and path bound by an API.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Routes is a Router recording the routes bound to it.
+type Routes []Route
+
+var _ Router = &Routes{}
+
+func (rs *Routes) GET(path string, _ httprouter.Handle)    { rs.add("GET", path) }
+func (rs *Routes) POST(path string, _ httprouter.Handle)   { rs.add("POST", path) }
+func (rs *Routes) PUT(path string, _ httprouter.Handle)    { rs.add("PUT", path) }
+func (rs *Routes) PATCH(path string, _ httprouter.Handle)  { rs.add("PATCH", path) }
+func (rs *Routes) DELETE(path string, _ httprouter.Handle) { rs.add("DELETE", path) }
+
+func (rs *Routes) add(method, path string) {
+	*rs = append(*rs, Route{Method: method, Path: path})
+}
+
+// Handle is an httprouter.Handle that returns the error to respond with
+// instead of writing it itself.
+type Handle func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error
+
+// Wrap adapts h to an httprouter.Handle, writing any error it returns as a
+// JSON error envelope.
+func Wrap(h Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if err := h(w, r, ps); err != nil {
+			errors.Write(w, err)
+		}
+	}
 }
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Check tells whether a dependency of the service is ready, failing with
+// the reason it isn't.
+type Check func(ctx context.Context) error
+
+// Checker is implemented by the APIs that have readiness checks of their
+// own. Health.Add collects them.
+type Checker interface {
+	Checks() map[string]Check
+}
+
+// Health is the API a load balancer or orchestrator watches:
+//
+//	/healthz answers as long as the process serves requests,
+//	/readyz runs the readiness checks, answering 503 if one fails,
+//	/version serves Version.
+type Health struct {
+	// Checks are run by /readyz, concurrently, by name.
+	Checks map[string]Check
+
+	// Version is served as JSON by /version.
+	Version interface{}
+
+	// Timeout bounds the checks of a /readyz request, 5 seconds by
+	// default.
+	Timeout time.Duration
+}
+
+var _ = API(&Health{})
+
+// Add adds the checks of every API implementing Checker.
+func (h *Health) Add(apis ...API) {
+	if h.Checks == nil {
+		h.Checks = map[string]Check{}
+	}
+
+	for _, a := range apis {
+		if c, ok := a.(Checker); ok {
+			for name, check := range c.Checks() {
+				h.Checks[name] = check
+			}
+		}
+	}
+}
+
+func (h *Health) Bind(r Router) error {
+	r.GET("/healthz", h.Healthz)
+	r.GET("/readyz", h.Readyz)
+	r.GET("/version", h.ServeVersion)
+
+	return nil
+}
+
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readiness is the body of a /readyz response. Checks maps the name of
+// every check to ok or the reason it failed.
+type readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Readyz waits for the checks until Timeout. A check still running then
+// fails as timed out.
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(h.Checks))
+	for name, check := range h.Checks {
+		go func(name string, check Check) {
+			results <- result{name: name, err: check(ctx)}
+		}(name, check)
+	}
+
+	res := readiness{Status: "ready", Checks: map[string]string{}}
+	for range h.Checks {
+		select {
+		case rs := <-results:
+			res.Checks[rs.name] = "ok"
+			if rs.err != nil {
+				res.Checks[rs.name] = rs.err.Error()
+			}
+		case <-ctx.Done():
+		}
+	}
+
+	status := http.StatusOK
+	for name := range h.Checks {
+		msg, ok := res.Checks[name]
+		if !ok {
+			msg = "timed out"
+			res.Checks[name] = msg
+		}
+		if msg != "ok" {
+			res.Status, status = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+
+	writeJSON(w, status, res)
+}
+
+func (h *Health) ServeVersion(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	writeJSON(w, http.StatusOK, h.Version)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("hello: unable to write response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(b, '\n'))
+}
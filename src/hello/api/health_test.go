@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type checker map[string]Check
+
+func (c checker) Bind(Router) error        { return nil }
+func (c checker) Checks() map[string]Check { return c }
+
+func readyz(t *testing.T, h *Health) (int, readiness) {
+	w := httptest.NewRecorder()
+	h.Readyz(w, httptest.NewRequest("GET", "/readyz", nil), nil)
+
+	var res readiness
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	return w.Code, res
+}
+
+func TestReadyz(t *testing.T) {
+	t.Parallel()
+
+	ok := func(context.Context) error { return nil }
+	h := &Health{}
+	h.Add(checker{"database": ok}, &Health{}, checker{"cache": ok})
+
+	code, res := readyz(t, h)
+	if code != http.StatusOK || res.Status != "ready" || len(res.Checks) != 2 {
+		t.Errorf("want ready with 2 checks, got %d %+v", code, res)
+	}
+}
+
+func TestReadyzFailing(t *testing.T) {
+	t.Parallel()
+
+	h := &Health{
+		Timeout: 10 * time.Millisecond,
+		Checks: map[string]Check{
+			"database": func(context.Context) error { return nil },
+			"broken":   func(context.Context) error { return errors.New("no route to host") },
+			"slow": func(ctx context.Context) error {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			},
+		},
+	}
+
+	code, res := readyz(t, h)
+	if code != http.StatusServiceUnavailable || res.Status != "unavailable" {
+		t.Errorf("want unavailable, got %d %s", code, res.Status)
+	}
+
+	want := map[string]string{"database": "ok", "broken": "no route to host", "slow": "timed out"}
+	for name, msg := range want {
+		if res.Checks[name] != msg {
+			t.Errorf("%s: want %q, got %q", name, msg, res.Checks[name])
+		}
+	}
+}
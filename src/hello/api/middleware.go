@@ -0,0 +1,367 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"hello/errors"
+
+	"models"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/satori/go.uuid"
+)
+
+// Middleware wraps a handle with behavior shared by many routes.
+type Middleware func(httprouter.Handle) httprouter.Handle
+
+// Chain wraps h in mws. The first of mws is the outermost: it sees the
+// request first and the response last.
+func Chain(h httprouter.Handle, mws ...Middleware) httprouter.Handle {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+
+	return h
+}
+
+// With returns a Router binding every route to r wrapped in mws.
+func With(r Router, mws ...Middleware) Router {
+	return &chain{r: r, mws: mws}
+}
+
+type chain struct {
+	r   Router
+	mws []Middleware
+}
+
+func (c *chain) GET(path string, h httprouter.Handle)    { c.r.GET(path, Chain(h, c.mws...)) }
+func (c *chain) POST(path string, h httprouter.Handle)   { c.r.POST(path, Chain(h, c.mws...)) }
+func (c *chain) PUT(path string, h httprouter.Handle)    { c.r.PUT(path, Chain(h, c.mws...)) }
+func (c *chain) PATCH(path string, h httprouter.Handle)  { c.r.PATCH(path, Chain(h, c.mws...)) }
+func (c *chain) DELETE(path string, h httprouter.Handle) { c.r.DELETE(path, Chain(h, c.mws...)) }
+
+// Use returns a with middleware of its own, wrapping its routes inside any
+// global middleware.
+func Use(a API, mws ...Middleware) API {
+	return using{API: a, mws: mws}
+}
+
+type using struct {
+	API
+	mws []Middleware
+}
+
+func (u using) Bind(r Router) error {
+	return u.API.Bind(With(r, u.mws...))
+}
+
+// Handler wraps h, usually the router, in mws. Global middleware goes here
+// rather than in With, so that it also sees the requests no route matches,
+// such as CORS preflight requests.
+func Handler(h http.Handler, mws ...Middleware) http.Handler {
+	handle := Chain(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		h.ServeHTTP(w, r)
+	}, mws...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handle(w, r, nil)
+	})
+}
+
+// RequestIDHeader carries the ID of a request, see RequestID.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID gives every request an ID: the one in its X-Request-ID header
+// or, if it has none, a new UUID. The ID is echoed in the response header
+// and stored in the request context with models.WithRequestID, so that a
+// changes.Tx begun with that context records it with the changes it makes.
+func RequestID(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = uuid.NewV4().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		h(w, r.WithContext(models.WithRequestID(r.Context(), id)), ps)
+	}
+}
+
+// accessEntry is a line of the access log.
+type accessEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	Bytes     int       `json:"bytes"`
+	Duration  float64   `json:"duration_ms"`
+	Remote    string    `json:"remote"`
+}
+
+// AccessLog writes a line of JSON to out for every request once it has
+// been served. Behind RequestID, the line holds the ID of the request. A
+// request whose handle panicked before responding is logged with status
+// 500, whether or not a Recover around AccessLog answers it so.
+func AccessLog(out io.Writer) Middleware {
+	return func(h httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			start := time.Now()
+			rec := &recorder{ResponseWriter: w}
+			served := false
+			defer func() {
+				status := rec.status()
+				if !served && !rec.wroteHeader() {
+					status = http.StatusInternalServerError
+				}
+
+				b, err := json.Marshal(accessEntry{
+					Time:      start,
+					RequestID: models.RequestIDFrom(r.Context()),
+					Method:    r.Method,
+					Path:      r.URL.RequestURI(),
+					Status:    status,
+					Bytes:     rec.bytes,
+					Duration:  float64(time.Since(start)) / float64(time.Millisecond),
+					Remote:    r.RemoteAddr,
+				})
+				if err != nil {
+					log.Printf("hello: unable to log access: %v", err)
+					return
+				}
+
+				out.Write(append(b, '\n'))
+			}()
+
+			h(rec, r, ps)
+			served = true
+		}
+	}
+}
+
+// recorder remembers the status and size of a response.
+type recorder struct {
+	http.ResponseWriter
+	code  int
+	bytes int
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher, when the ResponseWriter rec wraps does.
+func (rec *recorder) Flush() {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// wroteHeader tells whether the status of the response was sent.
+func (rec *recorder) wroteHeader() bool {
+	return rec.code != 0
+}
+
+func (rec *recorder) status() int {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+
+	return rec.code
+}
+
+// Recover turns a panicking handle into an INTERNAL_PROCESSOR_ERROR
+// response, logging the panic and its stack. When the handle had already
+// started its response, the connection is aborted instead, so that the
+// client doesn't take the partial response for a whole one.
+func Recover(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		rec := &recorder{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			log.Printf("hello: panic serving %s %s: %v\n%s", r.Method, r.URL.Path, v, debug.Stack())
+			if rec.wroteHeader() {
+				panic(http.ErrAbortHandler)
+			}
+			errors.Write(w, errors.New(errors.INTERNAL_PROCESSOR_ERROR, "", "internal error"))
+		}()
+
+		h(rec, r, ps)
+	}
+}
+
+// Gzip compresses the responses of clients that accept it.
+func Gzip(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			h(w, r, ps)
+			return
+		}
+
+		gw := &gzipWriter{ResponseWriter: w}
+		defer gw.close()
+
+		h(gw, r, ps)
+	}
+}
+
+// gzipWriter compresses what is written to it, unless the status of the
+// response doesn't allow for a body.
+type gzipWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+	compress    bool
+}
+
+func (gw *gzipWriter) WriteHeader(code int) {
+	if gw.wroteHeader {
+		return
+	}
+	gw.wroteHeader = true
+
+	if code != http.StatusNoContent && code != http.StatusNotModified {
+		gw.compress = true
+		gw.Header().Set("Content-Encoding", "gzip")
+		gw.Header().Del("Content-Length")
+	}
+	gw.ResponseWriter.WriteHeader(code)
+}
+
+func (gw *gzipWriter) Write(b []byte) (int, error) {
+	if !gw.wroteHeader {
+		if gw.Header().Get("Content-Type") == "" {
+			gw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		gw.WriteHeader(http.StatusOK)
+	}
+	if !gw.compress {
+		return gw.ResponseWriter.Write(b)
+	}
+
+	if gw.gz == nil {
+		gw.gz = gzip.NewWriter(gw.ResponseWriter)
+	}
+	return gw.gz.Write(b)
+}
+
+// Flush implements http.Flusher, sending what was compressed so far, when
+// the ResponseWriter gw wraps does.
+func (gw *gzipWriter) Flush() {
+	if !gw.wroteHeader {
+		gw.WriteHeader(http.StatusOK)
+	}
+	if gw.gz != nil {
+		gw.gz.Flush()
+	}
+	if f, ok := gw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (gw *gzipWriter) close() {
+	if gw.gz != nil {
+		gw.gz.Close()
+	}
+}
+
+// CORSOptions tells which cross-origin requests CORS allows.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed, or * for any.
+	AllowedOrigins []string
+
+	// AllowedMethods and AllowedHeaders answer preflight requests. They
+	// default to the methods a Router binds and Content-Type.
+	AllowedMethods []string
+	AllowedHeaders []string
+
+	// MaxAge is how long a browser may cache a preflight response.
+	MaxAge time.Duration
+}
+
+// CORS allows the cross-origin requests opts does, and answers their
+// preflight requests itself. As the router answers OPTIONS requests before
+// any route's middleware, use it with Handler.
+func CORS(opts CORSOptions) Middleware {
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	if methods == "" {
+		methods = "GET, POST, PUT, PATCH, DELETE"
+	}
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	if headers == "" {
+		headers = "Content-Type"
+	}
+	headers += ", " + RequestIDHeader
+
+	return func(h httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				h(w, r, ps)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			if !opts.allows(origin) {
+				h(w, r, ps)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				if opts.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge/time.Second)))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			w.Header().Set("Access-Control-Expose-Headers", RequestIDHeader)
+			h(w, r, ps)
+		}
+	}
+}
+
+func (opts CORSOptions) allows(origin string) bool {
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"models"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	log.SetOutput(ioutil.Discard)
+}
+
+func serve(h httprouter.Handle, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h(w, r, nil)
+	return w
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	mw := func(name string) Middleware {
+		return func(h httprouter.Handle) httprouter.Handle {
+			return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+				order = append(order, name)
+				h(w, r, ps)
+			}
+		}
+	}
+
+	h := Chain(func(http.ResponseWriter, *http.Request, httprouter.Params) {
+		order = append(order, "handle")
+	}, mw("first"), mw("second"))
+	serve(h, httptest.NewRequest("GET", "/", nil))
+
+	if got := strings.Join(order, ","); got != "first,second,handle" {
+		t.Errorf("want first,second,handle, got %s", got)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	h := RequestID(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		got = models.RequestIDFrom(r.Context())
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(RequestIDHeader, "req-1")
+	w := serve(h, r)
+	if got != "req-1" || w.Header().Get(RequestIDHeader) != "req-1" {
+		t.Errorf("want the ID of the request kept, got %q in the context and %q in the response", got, w.Header().Get(RequestIDHeader))
+	}
+
+	for _, id := range []string{"", strings.Repeat("a", 65)} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(RequestIDHeader, id)
+		w := serve(h, r)
+		if got == "" || got == id || w.Header().Get(RequestIDHeader) != got {
+			t.Errorf("want a new ID in place of %q, got %q in the context and %q in the response", id, got, w.Header().Get(RequestIDHeader))
+		}
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	h := Chain(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+	}, RequestID, AccessLog(out))
+
+	r := httptest.NewRequest("POST", "/shelves?x=1", nil)
+	r.Header.Set(RequestIDHeader, "req-1")
+	w := serve(h, r)
+
+	if !w.Flushed {
+		t.Error("want Flush forwarded to the ResponseWriter")
+	}
+
+	var e accessEntry
+	if err := json.Unmarshal(out.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.RequestID != "req-1" || e.Method != "POST" || e.Path != "/shelves?x=1" || e.Status != http.StatusCreated || e.Bytes != 5 {
+		t.Errorf("unexpected access log entry: %+v", e)
+	}
+}
+
+func TestAccessLogPanic(t *testing.T) {
+	t.Parallel()
+
+	out := &bytes.Buffer{}
+	h := Chain(func(http.ResponseWriter, *http.Request, httprouter.Params) {
+		panic("boom")
+	}, Recover, AccessLog(out))
+	w := serve(h, httptest.NewRequest("GET", "/shelves", nil))
+
+	// The panic unwinds through AccessLog to the Recover around it, and is
+	// logged on its way
+	var e accessEntry
+	if err := json.Unmarshal(out.Bytes(), &e); err != nil {
+		t.Fatalf("want an access log entry, got %q: %v", out.String(), err)
+	}
+	if e.Method != "GET" || e.Path != "/shelves" || e.Status != http.StatusInternalServerError || e.Status != w.Code {
+		t.Errorf("want GET /shelves logged with the status 500 it got, got %+v and %d", e, w.Code)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	h := Recover(func(http.ResponseWriter, *http.Request, httprouter.Params) {
+		panic("boom")
+	})
+	w := serve(h, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("want status 500, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "INTERNAL_PROCESSOR_ERROR") {
+		t.Errorf("want an INTERNAL_PROCESSOR_ERROR, got %s", w.Body.String())
+	}
+}
+
+func TestRecoverAfterWrite(t *testing.T) {
+	t.Parallel()
+
+	h := Recover(func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.Write([]byte(`{"id":`))
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("want the response aborted, got %v", v)
+		}
+		if w.Code != http.StatusOK || w.Body.String() != `{"id":` {
+			t.Errorf("want the partial response left alone, got %d %s", w.Code, w.Body.String())
+		}
+	}()
+
+	h(w, httptest.NewRequest("GET", "/", nil), nil)
+}
+
+func TestGzip(t *testing.T) {
+	t.Parallel()
+
+	h := Gzip(func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(" world"))
+	})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := serve(h, r)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("want a gzip response, got headers %v", w.Header())
+	}
+	if !w.Flushed {
+		t.Error("want Flush forwarded to the ResponseWriter")
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello world" {
+		t.Errorf("want hello world, got %q", b)
+	}
+}
+
+func TestGzipSkipped(t *testing.T) {
+	t.Parallel()
+
+	h := Gzip(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte("hello"))
+	})
+
+	w := serve(h, httptest.NewRequest("GET", "/", nil))
+	if w.Header().Get("Content-Encoding") != "" || w.Body.String() != "hello" {
+		t.Errorf("want a plain response without Accept-Encoding, got %v %q", w.Header(), w.Body.String())
+	}
+
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = serve(h, r)
+	if w.Code != http.StatusNoContent || w.Header().Get("Content-Encoding") != "" || w.Body.Len() != 0 {
+		t.Errorf("want a bare 204, got %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+}
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	h := CORS(CORSOptions{AllowedOrigins: []string{"https://example.com"}})(
+		func(http.ResponseWriter, *http.Request, httprouter.Params) { called = true },
+	)
+
+	r := httptest.NewRequest("OPTIONS", "/shelves", nil)
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	w := serve(h, r)
+	if called || w.Code != http.StatusNoContent {
+		t.Errorf("want the preflight answered by CORS, got %d, handle called: %v", w.Code, called)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://example.com" ||
+		w.Header().Get("Access-Control-Allow-Methods") != "GET, POST, PUT, PATCH, DELETE" ||
+		w.Header().Get("Access-Control-Allow-Headers") != "Content-Type, "+RequestIDHeader {
+		t.Errorf("unexpected preflight headers: %v", w.Header())
+	}
+
+	r = httptest.NewRequest("GET", "/shelves", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	w = serve(h, r)
+	if !called || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("want a request of another origin served without CORS headers, got %v", w.Header())
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"hello/errors"
+
+	"github.com/julienschmidt/httprouter"
+	"gopkg.in/nullbio/null.v6"
+)
+
+// body is a decoded request body that can check itself. partial is set for
+// PATCH requests, where absent fields are left alone.
+type body interface {
+	validate(partial bool) *errors.Error
+}
+
+// readBody decodes and validates the request body into b.
+func readBody(r *http.Request, b body, partial bool) *errors.Error {
+	if err := json.NewDecoder(r.Body).Decode(b); err != nil {
+		return errors.New(errors.DATA_JSON_PARSE_FAIL, "", err.Error())
+	}
+
+	return b.validate(partial)
+}
+
+// optString is a nullable string field of a request body. Set tells a
+// field left out of the body apart from one set to null.
+type optString struct {
+	Value null.String
+	Set   bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler, which is only called for a
+// field present in the body.
+func (o *optString) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return o.Value.UnmarshalJSON(data)
+}
+
+// optInt64 is a nullable integer field of a request body. Set tells a field
+// left out of the body apart from one set to null.
+type optInt64 struct {
+	Value null.Int64
+	Set   bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler, which is only called for a
+// field present in the body.
+func (o *optInt64) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return o.Value.UnmarshalJSON(data)
+}
+
+// paramID parses the named path parameter as a primary key.
+func paramID(ps httprouter.Params, name string) (int64, *errors.Error) {
+	id, err := strconv.ParseInt(ps.ByName(name), 10, 64)
+	if err != nil {
+		return 0, errors.New(errors.REQUEST_INVALID_PARAM, name, "must be an integer id")
+	}
+
+	return id, nil
+}
+
+// writeJSON encodes v before writing anything, so that an encoding failure
+// can still be reported as an error response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) *errors.Error {
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return errors.New(errors.INTERNAL_PROCESSOR_ERROR, "", err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+
+	return nil
+}
+
+// relationBody lists the related records a relation endpoint operates on,
+// e.g. {"items": [{"id": 1}, {"id": 2}]}.
+type relationBody struct {
+	Items []relationItem `json:"items"`
+}
+
+type relationItem struct {
+	ID int64 `json:"id"`
+}
+
+func (rb *relationBody) validate(partial bool) *errors.Error {
+	for _, item := range rb.Items {
+		if item.ID <= 0 {
+			return errors.New(errors.DATA_VALIDATION_FAIL, "items", fmt.Sprintf("invalid id %d", item.ID))
+		}
+	}
+
+	return nil
+}
+
+func (rb *relationBody) ids() []int64 {
+	ids := make([]int64, 0, len(rb.Items))
+	for _, item := range rb.Items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
+
+// missingIDs returns a DATA_VALIDATION_FAIL naming the entries of want that
+// aren't in got, or nil when there are none.
+func missingIDs(want, got []int64) error {
+	found := make(map[int64]bool, len(got))
+	for _, id := range got {
+		found[id] = true
+	}
+
+	var missing []int64
+	for _, id := range want {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return errors.New(errors.DATA_VALIDATION_FAIL, "items", fmt.Sprintf("unknown ids %v", missing))
+}
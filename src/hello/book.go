@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"hello/api"
+	"hello/errors"
+
+	"models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/julienschmidt/httprouter"
+	"github.com/vattle/sqlboiler/boil"
+	"gopkg.in/nullbio/null.v6"
+)
+
+type Book struct{ *sqlx.DB }
+
+var (
+	_ = api.API(Book{})
+	_ = api.Checker(Book{})
+)
+
+func (b Book) Bind(r api.Router) error {
+	r.GET("/books", api.Wrap(b.GetAll))
+	r.POST("/books", api.Wrap(b.Create))
+	r.GET("/books/:id", api.Wrap(b.Get))
+	r.PUT("/books/:id", api.Wrap(b.Replace))
+	r.PATCH("/books/:id", api.Wrap(b.Modify))
+	r.DELETE("/books/:id", api.Wrap(b.Delete))
+
+	r.GET("/books/:id/shelf", api.Wrap(b.GetShelf))
+	r.PUT("/books/:id/shelf", api.Wrap(b.SetShelf))
+	r.DELETE("/books/:id/shelf", api.Wrap(b.RemoveShelf))
+
+	return nil
+}
+
+// Checks implements api.Checker: the book table has to be readable.
+func (b Book) Checks() map[string]api.Check {
+	return map[string]api.Check{
+		"book": func(ctx context.Context) error {
+			_, err := models.Books(b.DB).ExistsContext(ctx)
+			return err
+		},
+	}
+}
+
+// bookBody is the request body accepted by Create, Replace and Modify.
+// Fields left out of the body are unset, which tells them apart from ones
+// cleared with null.
+type bookBody struct {
+	Name    optString `json:"name"`
+	Author  optString `json:"author"`
+	ShelfID optInt64  `json:"shelf_id"`
+}
+
+const maxBookColumnLen = 255
+
+// validate checks the body before it reaches the model. A name is required
+// unless partial is set, and cannot be cleared.
+func (bb *bookBody) validate(partial bool) *errors.Error {
+	if !bb.Name.Set && !partial {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "name", "is required")
+	}
+
+	if bb.Name.Set && !bb.Name.Value.Valid {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "name", "must not be null")
+	}
+
+	if len(bb.Name.Value.String) > maxBookColumnLen {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "name", fmt.Sprintf("must be at most %d characters", maxBookColumnLen))
+	}
+
+	if len(bb.Author.Value.String) > maxBookColumnLen {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "author", fmt.Sprintf("must be at most %d characters", maxBookColumnLen))
+	}
+
+	return nil
+}
+
+// apply copies the body onto o. Fields absent from the body are cleared
+// unless partial is set, in which case they are left alone.
+func (bb *bookBody) apply(o *models.Book, partial bool) {
+	if bb.Name.Set || !partial {
+		o.Name = bb.Name.Value
+	}
+
+	if bb.Author.Set || !partial {
+		o.Author = bb.Author.Value
+	}
+
+	if bb.ShelfID.Set || !partial {
+		o.ShelfID = bb.ShelfID.Value
+	}
+}
+
+// shelfRef names the shelf a book is placed on by SetShelf.
+type shelfRef struct {
+	ID *int64 `json:"id"`
+}
+
+func (sr *shelfRef) validate(partial bool) *errors.Error {
+	if sr.ID == nil {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "id", "is required")
+	}
+
+	return nil
+}
+
+// GetAll lists one page of books, sorted and filtered as the query asks.
+func (b Book) GetAll(w http.ResponseWriter, r *http.Request, _ httprouter.Params) *errors.Error {
+	p, err := parseList(r, b.DB.DriverName(), models.BookFieldMapping)
+	if err != nil {
+		return err
+	}
+
+	total, e1 := models.Books(b.DB, p.filterMods()...).CountContext(r.Context())
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	q := models.Books(b.DB, p.pageMods()...).SortBy(p.sort...).PageSize(int(p.PerPage))
+	if p.Before != "" {
+		q = q.Before(p.Before)
+	} else {
+		q = q.After(p.After)
+	}
+
+	o, cursors, e1 := q.PageContext(r.Context())
+	if e1 != nil {
+		return errors.From(e1)
+	}
+	if o == nil {
+		o = models.BookSlice{}
+	}
+
+	return writeJSON(w, http.StatusOK, p.page(r.URL, o, total, cursors))
+}
+
+func (b Book) Get(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := b.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(w, http.StatusOK, o)
+}
+
+func (b Book) Create(w http.ResponseWriter, r *http.Request, _ httprouter.Params) *errors.Error {
+	bb := &bookBody{}
+	if err := readBody(r, bb, false); err != nil {
+		return err
+	}
+
+	o := &models.Book{}
+	bb.apply(o, false)
+
+	if err := b.checkShelf(r.Context(), o.ShelfID); err != nil {
+		return err
+	}
+
+	e1 := withTx(r.Context(), b.DB, func(exec boil.Executor) error {
+		return o.InsertContext(r.Context(), exec)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/books/%d", o.ID))
+	return writeJSON(w, http.StatusCreated, o)
+}
+
+// Replace overwrites every column of the book with the request body.
+func (b Book) Replace(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	return b.update(w, r, ps, false)
+}
+
+// Modify only changes the columns present in the request body.
+func (b Book) Modify(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	return b.update(w, r, ps, true)
+}
+
+func (b Book) update(w http.ResponseWriter, r *http.Request, ps httprouter.Params, partial bool) *errors.Error {
+	o, err := b.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	bb := &bookBody{}
+	if err := readBody(r, bb, partial); err != nil {
+		return err
+	}
+	bb.apply(o, partial)
+
+	if err := b.checkShelf(r.Context(), o.ShelfID); err != nil {
+		return err
+	}
+
+	e1 := withTx(r.Context(), b.DB, func(exec boil.Executor) error {
+		return o.UpdateContext(r.Context(), exec)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	return writeJSON(w, http.StatusOK, o)
+}
+
+func (b Book) Delete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := b.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	e1 := withTx(r.Context(), b.DB, func(exec boil.Executor) error {
+		return o.DeleteContext(r.Context(), exec)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GetShelf returns the shelf the book is placed on.
+func (b Book) GetShelf(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := b.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	if !o.ShelfID.Valid {
+		return errors.New(errors.DATA_ENTITY_NOT_FOUND, "shelf", "Book is not on a shelf")
+	}
+
+	s, e1 := o.ShelfF(b.DB).OneContext(r.Context())
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	return writeJSON(w, http.StatusOK, s)
+}
+
+// SetShelf places the book on the shelf named in the request body.
+func (b Book) SetShelf(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := b.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	ref := &shelfRef{}
+	if err := readBody(r, ref, false); err != nil {
+		return err
+	}
+
+	s, e1 := models.FindShelfContext(r.Context(), b.DB, *ref.ID)
+	if e1 != nil {
+		if e := errors.From(e1); e.Code != errors.DATA_ENTITY_NOT_FOUND {
+			return e
+		}
+		return errors.New(errors.DATA_VALIDATION_FAIL, "id", "Shelf does not exist")
+	}
+
+	e1 = withTx(r.Context(), b.DB, func(exec boil.Executor) error {
+		return o.SetShelfContext(r.Context(), exec, false, s)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	return writeJSON(w, http.StatusOK, s)
+}
+
+// RemoveShelf takes the book off its shelf.
+func (b Book) RemoveShelf(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := b.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	if o.ShelfID.Valid {
+		// RemoveShelf clears o.R.Shelf, so the relationship has to be loaded first.
+		if err := o.L.LoadShelfContext(r.Context(), b.DB, true, o); err != nil {
+			return errors.From(err)
+		}
+
+		e1 := withTx(r.Context(), b.DB, func(exec boil.Executor) error {
+			return o.RemoveShelfContext(r.Context(), exec, o.R.Shelf)
+		})
+		if e1 != nil {
+			return errors.From(e1)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// find loads the book named by the :id parameter.
+func (b Book) find(ctx context.Context, ps httprouter.Params) (*models.Book, *errors.Error) {
+	id, err := paramID(ps, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	o, e1 := models.FindBookContext(ctx, b.DB, id)
+	if e1 != nil {
+		if e := errors.From(e1); e.Code != errors.DATA_ENTITY_NOT_FOUND {
+			return nil, e
+		}
+		return nil, errors.New(errors.DATA_ENTITY_NOT_FOUND, "id", "Book not found")
+	}
+
+	return o, nil
+}
+
+// checkShelf fails when shelfID points at a shelf that doesn't exist.
+func (b Book) checkShelf(ctx context.Context, shelfID null.Int64) *errors.Error {
+	if !shelfID.Valid {
+		return nil
+	}
+
+	exists, err := models.ShelfExistsContext(ctx, b.DB, shelfID.Int64)
+	if err != nil {
+		return errors.From(err)
+	}
+
+	if !exists {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "shelf_id", "Shelf does not exist")
+	}
+
+	return nil
+}
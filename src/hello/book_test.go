@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"hello/errors"
+
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var bookColumns = []string{"id", "name", "author", "shelf_id"}
+
+// expectBook expects book id to be found, holding name, author and shelfID.
+func expectBook(mock sqlmock.Sqlmock, id int64, name, author, shelfID interface{}) {
+	mock.ExpectQuery(regexp.QuoteMeta("select * from `book` where `id`=?")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(id, name, author, shelfID))
+}
+
+// expectShelfExists expects the existence of shelf id to be checked.
+func expectShelfExists(mock sqlmock.Sqlmock, id int64, exists bool) {
+	mock.ExpectQuery(regexp.QuoteMeta("select exists(select 1 from `shelf` where `id`=?")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+func TestBookCreate(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelfExists(mock, 2, true)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `book`").WillReturnResult(sqlmock.NewResult(5, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Book{db}, "POST", "/books", `{"name":"Dune","author":null,"shelf_id":2}`)
+
+	var o models.Book
+	if err := json.Unmarshal(w.Body.Bytes(), &o); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated || w.Header().Get("Location") != "/books/5" {
+		t.Errorf("want book 5 created, got %d at %q", w.Code, w.Header().Get("Location"))
+	}
+	if o.Name.String != "Dune" || o.Author.Valid || o.ShelfID.Int64 != 2 {
+		t.Errorf("want Dune by nobody on shelf 2, got %s", w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookCreateInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body  string
+		field string
+	}{
+		{`{"author":"Herbert"}`, "name"},
+		{`{"name":null}`, "name"},
+		{`{"name":"Dune","author":"` + strings.Repeat("a", maxBookColumnLen+1) + `"}`, "author"},
+	}
+
+	for _, test := range tests {
+		db, mock := mockDB(t)
+		w := call(t, Book{db}, "POST", "/books", test.body)
+		if e := errorOf(t, w); w.Code != http.StatusUnprocessableEntity || e.Code != errors.DATA_VALIDATION_FAIL || e.Field != test.field {
+			t.Errorf("%s: want a DATA_VALIDATION_FAIL of %s, got %d %+v", test.body, test.field, w.Code, e)
+		}
+		expectationsMet(t, mock)
+		db.Close()
+	}
+}
+
+func TestBookCreateUnknownShelf(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelfExists(mock, 2, false)
+
+	w := call(t, Book{db}, "POST", "/books", `{"name":"Dune","shelf_id":2}`)
+	if e := errorOf(t, w); w.Code != http.StatusUnprocessableEntity || e.Field != "shelf_id" {
+		t.Errorf("want a DATA_VALIDATION_FAIL of shelf_id, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookReplace(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// The columns left out of the body are cleared
+	expectBook(mock, 1, "Dune", "Herbert", 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `book` SET `author`=?,`shelf_id`=? WHERE `id`=? AND `author`=? AND `shelf_id`=?")).
+		WithArgs(nil, nil, 1, "Herbert", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Book{db}, "PUT", "/books/1", `{"name":"Dune"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("want 200, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookModify(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// A null author is cleared, the absent shelf left alone
+	expectBook(mock, 1, "Dune", "Herbert", 2)
+	expectShelfExists(mock, 2, true)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `book` SET `author`=? WHERE `id`=? AND `author`=?")).
+		WithArgs(nil, 1, "Herbert").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Book{db}, "PATCH", "/books/1", `{"author":null}`)
+
+	var o models.Book
+	if err := json.Unmarshal(w.Body.Bytes(), &o); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || o.Author.Valid || o.ShelfID.Int64 != 2 {
+		t.Errorf("want the author cleared and the shelf kept, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookModifyClearShelf(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// Without a shelf there is none to check
+	expectBook(mock, 1, "Dune", "Herbert", 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `book` SET `shelf_id`=? WHERE `id`=? AND `shelf_id`=?")).
+		WithArgs(nil, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Book{db}, "PATCH", "/books/1", `{"shelf_id":null}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("want 200, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookDelete(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectBook(mock, 1, "Dune", nil, nil)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `book` WHERE `id`=?")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Book{db}, "DELETE", "/books/1", "")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("want 204, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookGetShelfNone(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectBook(mock, 1, "Dune", nil, nil)
+
+	w := call(t, Book{db}, "GET", "/books/1/shelf", "")
+	if e := errorOf(t, w); w.Code != http.StatusNotFound || e.Field != "shelf" {
+		t.Errorf("want a DATA_ENTITY_NOT_FOUND of shelf, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
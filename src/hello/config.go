@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/vattle/sqlboiler/bdb"
+	"github.com/vattle/sqlboiler/bdb/drivers"
+)
+
+// config is the configuration of the hello service, see loadConfig.
+type config struct {
+	Listen string
+	HTTP   httpConfig
+	DB     dbConfig
+
+	// Migrations is the directory holding a directory of migrations per
+	// database driver.
+	Migrations string
+
+	// Drift is what serve does when the schema of the database drifted from
+	// the models: fail, warn or nothing, when off.
+	Drift string
+
+	// Sensitive is how change tracking treats the values of columns, by
+	// table and column: redact or exclude. It is only read from the config
+	// file, as [sensitive.<table>] sections.
+	Sensitive map[string]map[string]string
+}
+
+// httpConfig bounds how long the server spends on a request.
+type httpConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// ShutdownTimeout is how long in-flight requests are given to finish
+	// once the server was asked to stop.
+	ShutdownTimeout time.Duration
+
+	AccessLog   bool
+	Gzip        bool
+	CORSOrigins []string
+}
+
+// dbConfig names the database like the driver sections of sqlboiler.toml
+// do, and sizes the connection pool.
+type dbConfig struct {
+	Driver  string
+	Host    string
+	Port    int
+	User    string
+	Pass    string
+	DBName  string
+	SSLMode string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// configFlags declares a flag for every config key, named after it. The
+// flag defaults are the defaults of the keys.
+func configFlags(flags *pflag.FlagSet) {
+	flags.String("config", "", "config file (default hello.toml in the working directory or $HOME/.config/hello)")
+	flags.String("listen", "localhost:8083", "address to serve HTTP on")
+	flags.Duration("http.read_timeout", 10*time.Second, "maximum duration to read a request, body included")
+	flags.Duration("http.read_header_timeout", 5*time.Second, "maximum duration to read the headers of a request")
+	flags.Duration("http.write_timeout", 30*time.Second, "maximum duration to write a response")
+	flags.Duration("http.idle_timeout", 2*time.Minute, "maximum duration to keep an idle connection open")
+	flags.Duration("http.shutdown_timeout", 30*time.Second, "maximum duration to wait for in-flight requests on shutdown")
+	flags.Bool("http.access_log", true, "log every request to standard error")
+	flags.Bool("http.gzip", true, "compress responses for clients that accept it")
+	flags.StringSlice("http.cors_origins", nil, "origins allowed to make cross-origin requests, * for any")
+	flags.String("migrations", "migrations", "directory of the migrations of each database driver")
+	flags.String("drift", "fail", "what to do on startup when the schema drifted from the models: fail, warn or off")
+	flags.String("db.driver", "mysql", "database driver: mysql, postgres or mssql")
+	flags.String("db.host", "localhost", "database host")
+	flags.Int("db.port", 0, "database port (default the port of the driver)")
+	flags.String("db.user", "root", "database user")
+	flags.String("db.pass", "", "database password")
+	flags.String("db.dbname", "library", "database name")
+	flags.String("db.sslmode", "", "database TLS mode, as sqlboiler.toml spells it for the driver")
+	flags.Int("db.max_open_conns", 0, "maximum open database connections (default unlimited)")
+	flags.Int("db.max_idle_conns", 2, "maximum idle database connections")
+	flags.Duration("db.conn_max_lifetime", 0, "maximum lifetime of a database connection (default unlimited)")
+}
+
+// loadConfig reads the config from, by increasing precedence, the TOML
+// config file, HELLO_ environment variables such as HELLO_DB_HOST, and the
+// flags declared by configFlags.
+func loadConfig(flags *pflag.FlagSet) (*config, error) {
+	v := viper.New()
+
+	if path, _ := flags.GetString("config"); path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("hello")
+		v.AddConfigPath(".")
+		v.AddConfigPath("$HOME/.config/hello")
+	}
+
+	v.SetEnvPrefix("hello")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	if err := v.BindPFlags(flags); err != nil {
+		return nil, err
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, errors.Wrap(err, "unable to read config")
+		}
+	}
+
+	c := &config{
+		Listen: v.GetString("listen"),
+		HTTP: httpConfig{
+			ReadTimeout:       v.GetDuration("http.read_timeout"),
+			ReadHeaderTimeout: v.GetDuration("http.read_header_timeout"),
+			WriteTimeout:      v.GetDuration("http.write_timeout"),
+			IdleTimeout:       v.GetDuration("http.idle_timeout"),
+			ShutdownTimeout:   v.GetDuration("http.shutdown_timeout"),
+			AccessLog:         v.GetBool("http.access_log"),
+			Gzip:              v.GetBool("http.gzip"),
+			CORSOrigins:       v.GetStringSlice("http.cors_origins"),
+		},
+		DB: dbConfig{
+			Driver:          v.GetString("db.driver"),
+			Host:            v.GetString("db.host"),
+			Port:            v.GetInt("db.port"),
+			User:            v.GetString("db.user"),
+			Pass:            v.GetString("db.pass"),
+			DBName:          v.GetString("db.dbname"),
+			SSLMode:         v.GetString("db.sslmode"),
+			MaxOpenConns:    v.GetInt("db.max_open_conns"),
+			MaxIdleConns:    v.GetInt("db.max_idle_conns"),
+			ConnMaxLifetime: v.GetDuration("db.conn_max_lifetime"),
+		},
+		Migrations: v.GetString("migrations"),
+		Drift:      v.GetString("drift"),
+		Sensitive:  map[string]map[string]string{},
+	}
+	for table := range v.GetStringMap("sensitive") {
+		c.Sensitive[table] = v.GetStringMapString("sensitive." + table)
+	}
+
+	return c, nil
+}
+
+// setSensitive sets the columns of c.Sensitive sensitive in the change
+// tracking of the models.
+func (c *config) setSensitive() error {
+	for table, columns := range c.Sensitive {
+		for column, mode := range columns {
+			var s models.Sensitivity
+			switch mode {
+			case "redact":
+				s = models.Redact
+			case "exclude":
+				s = models.Exclude
+			default:
+				return fmt.Errorf("invalid sensitivity %q of %s.%s, want redact or exclude", mode, table, column)
+			}
+
+			models.SetSensitive(table, column, s)
+		}
+	}
+
+	return nil
+}
+
+// dsn builds the data source name of the database the way sqlboiler does.
+// MySQL connections report the rows an update matched rather than the rows
+// it changed, which the generated Update relies on to detect stale objects.
+func (c dbConfig) dsn() (string, error) {
+	switch c.Driver {
+	case "mysql":
+		dsn := drivers.MySQLBuildQueryString(c.User, c.Pass, c.DBName, c.Host, c.Port, c.sslMode("false"))
+		if strings.Contains(dsn, "?") {
+			return dsn + "&clientFoundRows=true", nil
+		}
+		return dsn + "?clientFoundRows=true", nil
+	case "postgres":
+		return drivers.PostgresBuildQueryString(c.User, c.Pass, c.DBName, c.Host, c.port(5432), c.sslMode("require")), nil
+	case "mssql":
+		return drivers.MSSQLBuildQueryString(c.User, c.Pass, c.DBName, c.Host, c.port(1433), c.sslMode("true")), nil
+	}
+
+	return "", fmt.Errorf("unknown database driver %q", c.Driver)
+}
+
+// introspect returns the sqlboiler driver of the database, to read its
+// schema with, and the name of that schema.
+func (c dbConfig) introspect() (bdb.Interface, string, error) {
+	switch c.Driver {
+	case "mysql":
+		return drivers.NewMySQLDriver(c.User, c.Pass, c.DBName, c.Host, c.Port, c.sslMode("false")), c.DBName, nil
+	case "postgres":
+		return drivers.NewPostgresDriver(c.User, c.Pass, c.DBName, c.Host, c.port(5432), c.sslMode("require")), "public", nil
+	case "mssql":
+		return drivers.NewMSSQLDriver(c.User, c.Pass, c.DBName, c.Host, c.port(1433), c.sslMode("true")), "dbo", nil
+	}
+
+	return nil, "", fmt.Errorf("unknown database driver %q", c.Driver)
+}
+
+func (c dbConfig) port(def int) int {
+	if c.Port != 0 {
+		return c.Port
+	}
+
+	return def
+}
+
+func (c dbConfig) sslMode(def string) string {
+	if c.SSLMode != "" {
+		return c.SSLMode
+	}
+
+	return def
+}
+
+// open connects to the database and sizes its connection pool.
+func (c dbConfig) open() (*sqlx.DB, error) {
+	dsn, err := c.dsn()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open(c.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+
+	return db, nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// load loads the config from the flags args, with $HOME and the working
+// directory holding no config file.
+func load(t *testing.T, args ...string) *config {
+	dir, err := ioutil.TempDir("", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("HOME", dir)
+
+	flags := pflag.NewFlagSet("hello", pflag.ContinueOnError)
+	configFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := loadConfig(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// configFile writes a config file holding content, and returns its path.
+func configFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "hello*.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+
+	return f.Name()
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	c := load(t)
+
+	if c.Listen != "localhost:8083" || c.DB.Driver != "mysql" || c.DB.DBName != "library" || c.Drift != "fail" {
+		t.Errorf("want the flag defaults, got %+v", c)
+	}
+	if c.HTTP.ReadTimeout != 10*time.Second || !c.HTTP.AccessLog || c.DB.MaxIdleConns != 2 {
+		t.Errorf("want the flag defaults, got %+v %+v", c.HTTP, c.DB)
+	}
+	if len(c.Sensitive) != 0 {
+		t.Errorf("want no sensitive columns, got %v", c.Sensitive)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := configFile(t, `
+listen="file:1"
+drift="warn"
+
+[db]
+host="file-host"
+user="file-user"
+port=3307
+
+[http]
+gzip=false
+
+[sensitive.user]
+password_hash="redact"
+reset_token="exclude"
+`)
+	defer os.Remove(path)
+
+	t.Setenv("HELLO_LISTEN", "env:2")
+	t.Setenv("HELLO_DB_HOST", "env-host")
+	t.Setenv("HELLO_DB_DBNAME", "env-db")
+
+	c := load(t, "--config", path, "--listen", "flag:3", "--db.port", "3308")
+
+	// Flags win over the environment, which wins over the file, which wins
+	// over the flag defaults
+	if c.Listen != "flag:3" || c.DB.Port != 3308 {
+		t.Errorf("want the flags, got listen %q, port %d", c.Listen, c.DB.Port)
+	}
+	if c.DB.Host != "env-host" || c.DB.DBName != "env-db" {
+		t.Errorf("want the environment, got host %q, dbname %q", c.DB.Host, c.DB.DBName)
+	}
+	if c.DB.User != "file-user" || c.Drift != "warn" || c.HTTP.Gzip {
+		t.Errorf("want the file, got user %q, drift %q, gzip %t", c.DB.User, c.Drift, c.HTTP.Gzip)
+	}
+	if c.DB.Driver != "mysql" || c.Migrations != "migrations" {
+		t.Errorf("want the flag defaults, got driver %q, migrations %q", c.DB.Driver, c.Migrations)
+	}
+
+	want := map[string]string{"password_hash": "redact", "reset_token": "exclude"}
+	if got := c.Sensitive["user"]; len(got) != len(want) || got["password_hash"] != "redact" || got["reset_token"] != "exclude" {
+		t.Errorf("want sensitive columns %v, got %v", want, c.Sensitive)
+	}
+}
+
+func TestLoadConfigInvalidFile(t *testing.T) {
+	path := configFile(t, "listen=")
+	defer os.Remove(path)
+
+	flags := pflag.NewFlagSet("hello", pflag.ContinueOnError)
+	configFlags(flags)
+	if err := flags.Parse([]string{"--config", path}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadConfig(flags); err == nil {
+		t.Error("want an error reading an invalid config file")
+	}
+}
+
+func TestDSN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		c    dbConfig
+		want []string
+	}{
+		// MySQL has dates parsed and reports the rows an update matched
+		{dbConfig{Driver: "mysql", User: "u", Pass: "p", Host: "h", DBName: "d"}, []string{"u:p@tcp(h:3306)/d?", "parseTime=true", "clientFoundRows=true"}},
+		{dbConfig{Driver: "mysql", User: "u", Host: "h", Port: 3307, DBName: "d", SSLMode: "true"}, []string{"u@tcp(h:3307)/d?", "parseTime=true", "tls=true", "clientFoundRows=true"}},
+		{dbConfig{Driver: "postgres", User: "u", Pass: "p", Host: "h", DBName: "d"}, []string{"user=u", "password=p", "host=h", "port=5432", "dbname=d", "sslmode=require"}},
+		{dbConfig{Driver: "postgres", User: "u", Host: "h", Port: 5433, DBName: "d", SSLMode: "disable"}, []string{"port=5433", "sslmode=disable"}},
+		{dbConfig{Driver: "mssql", User: "u", Pass: "p", Host: "h", DBName: "d"}, []string{"1433", "encrypt=true"}},
+	}
+
+	for _, test := range tests {
+		dsn, err := test.c.dsn()
+		if err != nil {
+			t.Errorf("%s: %v", test.c.Driver, err)
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(dsn, want) {
+				t.Errorf("%s: want %q in %q", test.c.Driver, want, dsn)
+			}
+		}
+	}
+
+	if _, err := (dbConfig{Driver: "sqlite"}).dsn(); err == nil {
+		t.Error("want an error building the dsn of an unknown driver")
+	}
+}
+
+func TestDSNClientFoundRowsOnce(t *testing.T) {
+	t.Parallel()
+
+	dsn, err := dbConfig{Driver: "mysql", User: "u", Host: "h", DBName: "d"}.dsn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(dsn, "?"); n != 1 {
+		t.Errorf("want 1 query string in %q, got %d", dsn, n)
+	}
+	if n := strings.Count(dsn, "clientFoundRows=true"); n != 1 {
+		t.Errorf("want clientFoundRows once in %q, got %d", dsn, n)
+	}
+}
+
+func TestSetSensitive(t *testing.T) {
+	t.Parallel()
+
+	c := &config{Sensitive: map[string]map[string]string{"user": {"password_hash": "hide"}}}
+	if err := c.setSensitive(); err == nil || !strings.Contains(err.Error(), "user.password_hash") {
+		t.Errorf("want an error naming the invalid column, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+
+	"audit"
+	"changes"
+	"outbox"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// withTx runs fn inside a changes.Tx, committing when it returns nil and
+// rolling back otherwise. The changes fn makes are recorded in audit_log
+// and the outbox as it commits, with the actor and request ID of ctx. The
+// transaction is rolled back when ctx is done.
+func withTx(ctx context.Context, db *sqlx.DB, fn func(exec boil.Executor) error) error {
+	sinks, err := changeSinks(db.DriverName())
+	if err != nil {
+		return err
+	}
+
+	tx, err := changes.BeginContext(ctx, db, sinks...)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// changeSinks returns the sinks recording the changes made in a database
+// of driver: audit_log, and the outbox the relay command publishes.
+func changeSinks(driver string) ([]changes.Sink, error) {
+	a, err := audit.NewSink(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := outbox.NewSink(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return []changes.Sink{a, o}, nil
+}
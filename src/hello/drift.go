@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"drift"
+
+	"github.com/spf13/cobra"
+)
+
+func driftCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "drift",
+		Short: "Compare the database schema with the models",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := loadConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			db, schema, err := c.DB.introspect()
+			if err != nil {
+				return err
+			}
+
+			changes, err := drift.Check(db, schema)
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				fmt.Println("the database schema matches the models")
+				return nil
+			}
+
+			for _, ch := range changes {
+				fmt.Println(ch)
+			}
+			return fmt.Errorf("the database schema differs from the models in %d ways", len(changes))
+		},
+	}
+}
+
+// guardDrift checks the schema before serving, as c.Drift asks, and
+// returns how it drifted when c.Drift only warns of it. The readiness
+// check reports that result rather than introspecting the schema again,
+// as it only changes with a migration, which comes with a restart.
+func guardDrift(c *config) (drift.Error, error) {
+	if c.Drift == "off" {
+		return nil, nil
+	}
+	if c.Drift != "fail" && c.Drift != "warn" {
+		return nil, fmt.Errorf("invalid drift %q, want fail, warn or off", c.Drift)
+	}
+
+	db, schema, err := c.DB.introspect()
+	if err != nil {
+		return nil, err
+	}
+
+	err = drift.Guard(db, schema)
+	if drifted, ok := err.(drift.Error); ok && c.Drift == "warn" {
+		log.Printf("hello: %v", err)
+		return drifted, nil
+	}
+
+	return nil, err
+}
@@ -0,0 +1,117 @@
+// Package errors defines the errors hello handlers return and the JSON
+// envelope they are written in.
+package errors
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"models"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Code identifies the kind of an Error. Clients switch on it, so existing
+// values must not change.
+type Code string
+
+const (
+	REQUEST_INVALID_PARAM    Code = "REQUEST_INVALID_PARAM"
+	DATA_JSON_PARSE_FAIL     Code = "DATA_JSON_PARSE_FAIL"
+	DATA_VALIDATION_FAIL     Code = "DATA_VALIDATION_FAIL"
+	DATA_ENTITY_NOT_FOUND    Code = "DATA_ENTITY_NOT_FOUND"
+	DATA_STALE_OBJECT        Code = "DATA_STALE_OBJECT"
+	INTERNAL_DATABASE_ERROR  Code = "INTERNAL_DATABASE_ERROR"
+	INTERNAL_PROCESSOR_ERROR Code = "INTERNAL_PROCESSOR_ERROR"
+)
+
+var statuses = map[Code]int{
+	REQUEST_INVALID_PARAM:    http.StatusBadRequest,
+	DATA_JSON_PARSE_FAIL:     http.StatusBadRequest,
+	DATA_VALIDATION_FAIL:     http.StatusUnprocessableEntity,
+	DATA_ENTITY_NOT_FOUND:    http.StatusNotFound,
+	DATA_STALE_OBJECT:        http.StatusConflict,
+	INTERNAL_DATABASE_ERROR:  http.StatusInternalServerError,
+	INTERNAL_PROCESSOR_ERROR: http.StatusInternalServerError,
+}
+
+// Error is a failure that can be reported to the client. Field names the
+// offending parameter or body field, if any.
+type Error struct {
+	Code    Code   `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// New returns an Error with the given code.
+func New(code Code, field, message string) *Error {
+	return &Error{Code: code, Field: field, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return string(e.Code) + ": " + e.Message
+	}
+
+	return string(e.Code) + ": " + e.Field + ": " + e.Message
+}
+
+// Status is the HTTP status code the error is written with.
+func (e *Error) Status() int {
+	if s, ok := statuses[e.Code]; ok {
+		return s
+	}
+
+	return http.StatusInternalServerError
+}
+
+// From converts err into an Error. An *Error is returned as is, a missing
+// row becomes DATA_ENTITY_NOT_FOUND, a row changed by someone else in the
+// meantime DATA_STALE_OBJECT, and anything else is treated as a
+// database failure, whose details are logged rather than sent to the
+// client.
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+
+	if pkgerrors.Cause(err) == sql.ErrNoRows {
+		return New(DATA_ENTITY_NOT_FOUND, "", "entity not found")
+	}
+
+	if pkgerrors.Cause(err) == models.ErrStaleObject {
+		return New(DATA_STALE_OBJECT, "", "entity was changed concurrently, reload it and retry")
+	}
+
+	if pkgerrors.Cause(err) == models.ErrInvalidCursor {
+		return New(REQUEST_INVALID_PARAM, "", "invalid page cursor, list the first page again")
+	}
+
+	log.Printf("hello: %v", err)
+	return New(INTERNAL_DATABASE_ERROR, "", "database error")
+}
+
+// envelope is the body every error response is written in.
+type envelope struct {
+	Errors []*Error `json:"errors"`
+}
+
+// Write writes errs as a JSON envelope, using the status of the first one.
+func Write(w http.ResponseWriter, errs ...*Error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(errs[0].Status())
+
+	if err := json.NewEncoder(w).Encode(envelope{Errors: errs}); err != nil {
+		log.Printf("hello: unable to write error response: %v", err)
+	}
+}
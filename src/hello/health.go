@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+
+	"hello/api"
+
+	"drift"
+	"migrate"
+	"models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Build information, set when building a release with
+//
+//	go build -ldflags "-X main.version=1.2.0 -X main.commit=$(git rev-parse HEAD)"
+var (
+	version = "dev"
+	commit  = "unknown"
+)
+
+// buildInfo is the body of /version. Models fingerprints the schema the
+// models were generated from.
+type buildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Go      string `json:"go"`
+	Models  string `json:"models"`
+}
+
+// startup is what serve finds out before serving, for the readiness checks
+// to report without asking the database again.
+type startup struct {
+	// migrations has the migrations loaded when serving started
+	migrations *migrate.Migrator
+
+	// drifted is how the schema drifted from the models, when c.Drift only
+	// warns of it
+	drifted drift.Error
+}
+
+// health builds the health API, checking the database, its migrations and,
+// unless c.Drift is off, that its schema had not drifted from the models
+// when serving started, along with the checks of as.
+func health(c *config, db *sqlx.DB, s startup, as []api.API) *api.Health {
+	h := &api.Health{
+		Version: buildInfo{
+			Version: version,
+			Commit:  commit,
+			Go:      runtime.Version(),
+			Models:  models.SchemaVersion(),
+		},
+		Checks: map[string]api.Check{
+			"database": func(ctx context.Context) error {
+				return db.PingContext(ctx)
+			},
+			"migrations": func(ctx context.Context) error {
+				return checkMigrations(ctx, s.migrations)
+			},
+		},
+	}
+
+	// Serving does not start with a drifted schema, unless c.Drift only
+	// warns of it, which leaves the service ready
+	if c.Drift != "off" {
+		h.Checks["drift"] = func(context.Context) error {
+			if len(s.drifted) > 0 {
+				log.Printf("hello: schema differs from the models in %d ways, see hello drift", len(s.drifted))
+			}
+			return nil
+		}
+	}
+
+	h.Add(as...)
+	return h
+}
+
+// checkMigrations fails while some of the migrations of m are not applied.
+func checkMigrations(ctx context.Context, m *migrate.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d migrations pending", pending)
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"hello/errors"
+	"models"
+
+	"github.com/vattle/sqlboiler/bdb"
+	"github.com/vattle/sqlboiler/bdb/drivers"
+	"github.com/vattle/sqlboiler/queries/qm"
+	"github.com/vattle/sqlboiler/strmangle"
+)
+
+const (
+	defaultPerPage = 10
+	maxPerPage     = 100
+)
+
+// listParams is the parsed ?page=&per_page=&sort=&filter[col]= query of a
+// list endpoint. Sort and filter columns are checked against the model's
+// generated FieldMapping, so only real columns reach the SQL.
+//
+// ?after= or ?before=, with a next_cursor or prev_cursor of a listed page,
+// select the page by keyset instead of by page number, which is as fast on
+// any page and does not skip rows changed in between.
+type listParams struct {
+	Page    int64
+	PerPage int64
+	After   string
+	Before  string
+
+	sort    []string
+	filters []qm.QueryMod
+}
+
+// parseList reads the list query of r for a model whose columns are the
+// keys of fields, stored in a database of the given driver. A column
+// filtered on more than once matches any of the values given.
+func parseList(r *http.Request, driver string, fields map[string]string) (*listParams, *errors.Error) {
+	q := r.URL.Query()
+	p := &listParams{Page: 1, PerPage: defaultPerPage}
+
+	var err *errors.Error
+	if p.Page, err = queryInt(q, "page", p.Page); err != nil {
+		return nil, err
+	}
+	if p.PerPage, err = queryInt(q, "per_page", p.PerPage); err != nil {
+		return nil, err
+	}
+	if p.PerPage > maxPerPage {
+		return nil, errors.New(errors.REQUEST_INVALID_PARAM, "per_page", fmt.Sprintf("must be at most %d", maxPerPage))
+	}
+	// The offset of the page has to fit an int, even a 32-bit one
+	if maxPage := math.MaxInt32/p.PerPage + 1; p.Page > maxPage {
+		return nil, errors.New(errors.REQUEST_INVALID_PARAM, "page", fmt.Sprintf("must be at most %d", maxPage))
+	}
+
+	p.After, p.Before = q.Get("after"), q.Get("before")
+	if p.After != "" && p.Before != "" {
+		return nil, errors.New(errors.REQUEST_INVALID_PARAM, "before", "cannot be used with after")
+	}
+
+	if sort := q.Get("sort"); sort != "" {
+		for _, col := range strings.Split(sort, ",") {
+			name := strings.TrimPrefix(col, "-")
+			if _, ok := fields[name]; !ok {
+				return nil, errors.New(errors.REQUEST_INVALID_PARAM, "sort", fmt.Sprintf("unknown column %q", name))
+			}
+			p.sort = append(p.sort, col)
+		}
+	}
+
+	for key, vals := range q {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+
+		col := key[len("filter[") : len(key)-1]
+		if _, ok := fields[col]; !ok {
+			return nil, errors.New(errors.REQUEST_INVALID_PARAM, key, fmt.Sprintf("unknown column %q", col))
+		}
+
+		quoted := quoteColumn(driver, col)
+		if len(vals) == 1 {
+			p.filters = append(p.filters, qm.Where(quoted+" = ?", vals[0]))
+			continue
+		}
+
+		args := make([]interface{}, len(vals))
+		for i, v := range vals {
+			args[i] = v
+		}
+		p.filters = append(p.filters, qm.WhereIn(quoted+" IN ?", args...))
+	}
+
+	return p, nil
+}
+
+// quoteColumn quotes col with the identifier quotes of the database driver.
+func quoteColumn(driver, col string) string {
+	var d bdb.Interface = &drivers.MySQLDriver{}
+	switch driver {
+	case "postgres":
+		d = &drivers.PostgresDriver{}
+	case "mssql":
+		d = &drivers.MSSQLDriver{}
+	}
+
+	return strmangle.IdentQuote(d.LeftQuote(), d.RightQuote(), col)
+}
+
+func queryInt(q url.Values, name string, def int64) (int64, *errors.Error) {
+	s := q.Get(name)
+	if s == "" {
+		return def, nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 1 {
+		return 0, errors.New(errors.REQUEST_INVALID_PARAM, name, "must be a positive integer")
+	}
+
+	return n, nil
+}
+
+// filterMods returns the query mods selecting the filtered rows, for
+// counting them.
+func (p *listParams) filterMods() []qm.QueryMod {
+	return p.filters
+}
+
+// cursor tells whether the page is selected by keyset, with ?after= or
+// ?before=, rather than by page number.
+func (p *listParams) cursor() bool {
+	return p.After != "" || p.Before != ""
+}
+
+// pageMods returns the query mods selecting the requested page, on top of
+// the sort, page size and cursor given to the model's Page query.
+func (p *listParams) pageMods() []qm.QueryMod {
+	mods := append([]qm.QueryMod{}, p.filters...)
+	if p.cursor() {
+		return mods
+	}
+
+	return append(mods, qm.Offset(int((p.Page-1)*p.PerPage)))
+}
+
+// listPage is the body of a list response.
+type listPage struct {
+	Items      interface{} `json:"items"`
+	Page       int64       `json:"page,omitempty"`
+	PerPage    int64       `json:"per_page"`
+	TotalCount int64       `json:"total_count"`
+	TotalPage  int64       `json:"total_page"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+	Links      []*linkItem `json:"links"`
+}
+
+type linkItem struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+// page wraps items, one page of the totalCount matching rows, with the
+// pagination details, the cursors of the pages around it and links for u.
+// A page selected by cursor has no page number, and links by cursor.
+func (p *listParams) page(u *url.URL, items interface{}, totalCount int64, cursors *models.Cursors) *listPage {
+	lp := &listPage{
+		Items:      items,
+		PerPage:    p.PerPage,
+		TotalCount: totalCount,
+		NextCursor: cursors.Next,
+		PrevCursor: cursors.Prev,
+	}
+
+	lp.paginate()
+	if p.cursor() {
+		lp.buildCursorLinks(u)
+		return lp
+	}
+
+	lp.Page = p.Page
+	lp.buildLinks(u)
+
+	return lp
+}
+
+func (lp *listPage) paginate() {
+	if c := lp.TotalCount / lp.PerPage; (c*lp.PerPage) < lp.TotalCount && lp.TotalCount > 0 {
+		lp.TotalPage = lp.TotalCount/lp.PerPage + 1
+	} else {
+		lp.TotalPage = lp.TotalCount / lp.PerPage
+	}
+}
+
+func (lp *listPage) buildLinks(u *url.URL) {
+	lp.Links = append(lp.Links, &linkItem{Rel: "self", Href: pageURL(u, lp.Page, lp.PerPage)})
+
+	if lp.Page > 1 {
+		lp.Links = append(lp.Links, &linkItem{Rel: "prev", Href: pageURL(u, lp.Page-1, lp.PerPage)})
+	}
+
+	if lp.Page < lp.TotalPage {
+		lp.Links = append(lp.Links, &linkItem{Rel: "next", Href: pageURL(u, lp.Page+1, lp.PerPage)})
+		lp.Links = append(lp.Links, &linkItem{Rel: "last", Href: pageURL(u, lp.TotalPage, lp.PerPage)})
+	}
+}
+
+func (lp *listPage) buildCursorLinks(u *url.URL) {
+	lp.Links = append(lp.Links, &linkItem{Rel: "self", Href: u.Path + "?" + u.RawQuery})
+
+	if lp.PrevCursor != "" {
+		lp.Links = append(lp.Links, &linkItem{Rel: "prev", Href: cursorURL(u, "before", lp.PrevCursor)})
+	}
+
+	if lp.NextCursor != "" {
+		lp.Links = append(lp.Links, &linkItem{Rel: "next", Href: cursorURL(u, "after", lp.NextCursor)})
+	}
+}
+
+// pageURL returns u pointing at the given page, keeping its sort and
+// filter parameters.
+func pageURL(u *url.URL, page, perPage int64) string {
+	q := u.Query()
+	q.Del("after")
+	q.Del("before")
+	q.Set("page", strconv.FormatInt(page, 10))
+	q.Set("per_page", strconv.FormatInt(perPage, 10))
+
+	return u.Path + "?" + q.Encode()
+}
+
+// cursorURL returns u pointing at the page after or before cursor, keeping
+// its sort, filter and per_page parameters.
+func cursorURL(u *url.URL, param, cursor string) string {
+	q := u.Query()
+	q.Del("page")
+	q.Del("after")
+	q.Del("before")
+	q.Set(param, cursor)
+
+	return u.Path + "?" + q.Encode()
+}
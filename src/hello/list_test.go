@@ -0,0 +1,243 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"hello/errors"
+
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func parse(target string) (*listParams, *errors.Error) {
+	return parseList(httptest.NewRequest("GET", target, nil), "mysql", models.ShelfFieldMapping)
+}
+
+func TestParseList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		target  string
+		page    int64
+		perPage int64
+		after   string
+		before  string
+		sort    []string
+	}{
+		{"/shelves", 1, defaultPerPage, "", "", nil},
+		{"/shelves?page=3&per_page=25", 3, 25, "", "", nil},
+		{"/shelves?per_page=" + strconv.Itoa(maxPerPage), 1, maxPerPage, "", "", nil},
+		{"/shelves?sort=area,-id", 1, defaultPerPage, "", "", []string{"area", "-id"}},
+		{"/shelves?after=abc", 1, defaultPerPage, "abc", "", nil},
+		{"/shelves?before=abc&per_page=5", 1, 5, "", "abc", nil},
+	}
+
+	for _, test := range tests {
+		p, err := parse(test.target)
+		if err != nil {
+			t.Errorf("%s: %v", test.target, err)
+			continue
+		}
+		if p.Page != test.page || p.PerPage != test.perPage || p.After != test.after || p.Before != test.before || !reflect.DeepEqual(p.sort, test.sort) {
+			t.Errorf("%s: want page %d of %d, after %q, before %q, sorted by %v, got %+v", test.target, test.page, test.perPage, test.after, test.before, test.sort, p)
+		}
+	}
+}
+
+func TestParseListInvalid(t *testing.T) {
+	t.Parallel()
+
+	maxPage := math.MaxInt32/defaultPerPage + 1
+	tests := []struct {
+		target string
+		field  string
+	}{
+		{"/shelves?page=0", "page"},
+		{"/shelves?page=-1", "page"},
+		{"/shelves?page=x", "page"},
+		{"/shelves?page=" + strconv.Itoa(maxPage+1), "page"},
+		{"/shelves?page=9223372036854775807", "page"},
+		{"/shelves?page=99999999999999999999", "page"},
+		{"/shelves?per_page=0", "per_page"},
+		{"/shelves?per_page=" + strconv.Itoa(maxPerPage+1), "per_page"},
+		{"/shelves?after=a&before=b", "before"},
+		{"/shelves?sort=-name", "sort"},
+		{"/shelves?filter[name]=x", "filter[name]"},
+	}
+
+	for _, test := range tests {
+		_, err := parse(test.target)
+		if err == nil || err.Code != errors.REQUEST_INVALID_PARAM || err.Field != test.field {
+			t.Errorf("%s: want a REQUEST_INVALID_PARAM of %s, got %v", test.target, test.field, err)
+		}
+	}
+
+	// The last page whose offset fits is allowed
+	p, err := parse("/shelves?page=" + strconv.Itoa(maxPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mods := p.pageMods(); len(mods) != 1 {
+		t.Errorf("want an offset, got %d mods", len(mods))
+	}
+}
+
+func TestParseListFilters(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("(`area` = ?)")).
+		WithArgs("north").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("`area` IN (?,?)")).
+		WithArgs("north", "south").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	for _, target := range []string{"/shelves?filter[area]=north", "/shelves?filter[area]=north&filter[area]=south"} {
+		p, err := parse(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := models.Shelves(db, p.filterMods()...).Count(); err != nil {
+			t.Errorf("%s: %v", target, err)
+		}
+	}
+	expectationsMet(t, mock)
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		total, perPage, pages int64
+	}{
+		{0, 10, 0},
+		{1, 10, 1},
+		{10, 10, 1},
+		{11, 10, 2},
+		{100, 10, 10},
+		{101, 100, 2},
+	}
+
+	for _, test := range tests {
+		lp := &listPage{TotalCount: test.total, PerPage: test.perPage}
+		lp.paginate()
+		if lp.TotalPage != test.pages {
+			t.Errorf("%d rows of %d a page: want %d pages, got %d", test.total, test.perPage, test.pages, lp.TotalPage)
+		}
+	}
+}
+
+// links maps the rel of every link of lp to its href.
+func links(lp *listPage) map[string]string {
+	m := map[string]string{}
+	for _, l := range lp.Links {
+		m[l.Rel] = l.Href
+	}
+
+	return m
+}
+
+func TestBuildLinks(t *testing.T) {
+	t.Parallel()
+
+	u, _ := url.Parse("/shelves?sort=area&filter[area]=north&after=x&page=2&per_page=10")
+	tests := []struct {
+		page int64
+		want map[string]string
+	}{
+		{1, map[string]string{
+			"self": "/shelves?filter%5Barea%5D=north&page=1&per_page=10&sort=area",
+			"next": "/shelves?filter%5Barea%5D=north&page=2&per_page=10&sort=area",
+			"last": "/shelves?filter%5Barea%5D=north&page=3&per_page=10&sort=area",
+		}},
+		{2, map[string]string{
+			"self": "/shelves?filter%5Barea%5D=north&page=2&per_page=10&sort=area",
+			"prev": "/shelves?filter%5Barea%5D=north&page=1&per_page=10&sort=area",
+			"next": "/shelves?filter%5Barea%5D=north&page=3&per_page=10&sort=area",
+			"last": "/shelves?filter%5Barea%5D=north&page=3&per_page=10&sort=area",
+		}},
+		{3, map[string]string{
+			"self": "/shelves?filter%5Barea%5D=north&page=3&per_page=10&sort=area",
+			"prev": "/shelves?filter%5Barea%5D=north&page=2&per_page=10&sort=area",
+		}},
+	}
+
+	for _, test := range tests {
+		lp := &listPage{Page: test.page, PerPage: 10, TotalCount: 25}
+		lp.paginate()
+		lp.buildLinks(u)
+		if got := links(lp); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("page %d: want links %v, got %v", test.page, test.want, got)
+		}
+	}
+}
+
+func TestBuildCursorLinks(t *testing.T) {
+	t.Parallel()
+
+	u, _ := url.Parse("/shelves?sort=area&per_page=5&after=x&page=2")
+	lp := &listPage{PerPage: 5, NextCursor: "n", PrevCursor: "p"}
+	lp.buildCursorLinks(u)
+
+	want := map[string]string{
+		"self": "/shelves?sort=area&per_page=5&after=x&page=2",
+		"prev": "/shelves?before=p&per_page=5&sort=area",
+		"next": "/shelves?after=n&per_page=5&sort=area",
+	}
+	if got := links(lp); !reflect.DeepEqual(got, want) {
+		t.Errorf("want links %v, got %v", want, got)
+	}
+
+	// The last page has no next link
+	lp = &listPage{PerPage: 5, PrevCursor: "p"}
+	lp.buildCursorLinks(u)
+	if got := links(lp); len(got) != 2 || got["next"] != "" {
+		t.Errorf("want self and prev links, got %v", got)
+	}
+}
+
+func TestListPage(t *testing.T) {
+	t.Parallel()
+
+	u, _ := url.Parse("/shelves?page=2&per_page=10")
+	p := &listParams{Page: 2, PerPage: 10}
+	lp := p.page(u, models.ShelfSlice{}, 25, &models.Cursors{Next: "n"})
+	if lp.Page != 2 || lp.TotalPage != 3 || lp.NextCursor != "n" || len(lp.Links) != 4 {
+		t.Errorf("want page 2 of 3 with 4 links, got %+v", lp)
+	}
+
+	// A page selected by cursor has no page number
+	p = &listParams{Page: 1, PerPage: 10, After: "x"}
+	lp = p.page(u, models.ShelfSlice{}, 25, &models.Cursors{})
+	if lp.Page != 0 || len(lp.Links) != 1 {
+		t.Errorf("want a page without number and only a self link, got %+v", lp)
+	}
+	if mods := p.pageMods(); len(mods) != 0 {
+		t.Errorf("want no offset on a page selected by cursor, got %d mods", len(mods))
+	}
+}
+
+func TestShelfGetAllPageTooLarge(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// An offset past an int is refused before any query is made
+	w := call(t, Shelf{db}, "GET", "/shelves?page=9223372036854775807", "")
+	if e := errorOf(t, w); w.Code != http.StatusBadRequest || e.Code != errors.REQUEST_INVALID_PARAM || e.Field != "page" {
+		t.Errorf("want a REQUEST_INVALID_PARAM of page, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
@@ -1,64 +1,188 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"io"
+	"log"
 	"net/http"
-	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"hello/api"
 
-	"models"
-
-	"github.com/vattle/sqlboiler/queries/qm"
-	"github.com/julienschmidt/httprouter"
 	"github.com/jmoiron/sqlx"
+	"github.com/julienschmidt/httprouter"
+	"github.com/spf13/cobra"
 
+	// The database/sql drivers of the databases hello supports, see
+	// dbConfig.Driver
+	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 )
 
 func main() {
-	db, err := sqlx.Open("mysql", "root:root@tcp(localhost:3306)/library")
-	if err != nil { panic(err.Error()) }
+	if err := rootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// rootCmd builds the hello command line. Run without a subcommand, hello
+// serves, as it always did.
+func rootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "hello",
+		Short:        "hello serves the library API",
+		RunE:         serve,
+		SilenceUsage: true,
+	}
+	configFlags(root.PersistentFlags())
+
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve",
+			Short: "Serve the library API",
+			RunE:  serve,
+		},
+		migrateCmd(),
+		driftCmd(),
+		seedCmd(),
+		relayCmd(),
+		routesCmd(),
+	)
+
+	return root
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	c, db, err := openDB(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := c.setSensitive(); err != nil {
+		db.Close()
+		return err
+	}
+
+	m, err := migrator(c, db)
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	drifted, err := guardDrift(c)
+	if err != nil {
+		db.Close()
+		return err
+	}
 
 	r := httprouter.New()
-	Shelf{db}.Bind(r)
+	if err := bind(r, c, db, startup{migrations: m, drifted: drifted}); err != nil {
+		db.Close()
+		return err
+	}
 
-	http.ListenAndServe("localhost:8083", r)
+	srv := &http.Server{
+		Addr:              c.Listen,
+		Handler:           api.Handler(r, middleware(c.HTTP)...),
+		ReadTimeout:       c.HTTP.ReadTimeout,
+		ReadHeaderTimeout: c.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      c.HTTP.WriteTimeout,
+		IdleTimeout:       c.HTTP.IdleTimeout,
+	}
 
-	select{}
+	return run(srv, db, c.HTTP.ShutdownTimeout)
 }
 
-type Shelf struct { *sqlx.DB }
+// run serves srv until it fails or the process is asked to stop with SIGINT
+// or SIGTERM. It then stops accepting connections, waits up to timeout for
+// in-flight requests to finish and, once they have, closes db.
+func run(srv *http.Server, db io.Closer, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("hello: listening on %s", srv.Addr)
+		errs <- srv.ListenAndServe()
+	}()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	select {
+	case err := <-errs:
+		db.Close()
+		return err
+	case sig := <-sigs:
+		log.Printf("hello: %s, shutting down", sig)
+	}
 
-var _ = api.API(Shelf{})
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-func (s Shelf) Bind(r *httprouter.Router) error {
-	r.GET("/shelves", s.GetAll)
-	r.GET("/shelf/:id", s.Get)
+	err := srv.Shutdown(ctx)
+	if err != nil {
+		log.Printf("hello: unable to drain requests: %v", err)
+		srv.Close()
+	}
 
-	return nil
+	if cerr := db.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+
+	return err
 }
 
-func (s Shelf) GetAll(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-        o, e1 := models.Shelves(s.DB).All()
-	if e1 != nil {
-		fmt.Println("error", e1)
-	}	
+// apis lists the APIs hello serves, the health API last as it gathers the
+// readiness checks of the others and those of s.
+func apis(c *config, db *sqlx.DB, s startup) []api.API {
+	as := []api.API{Shelf{db}, Book{db}}
+	return append(as, health(c, db, s, as))
+}
 
-	if err := json.NewEncoder(w).Encode(o); err != nil {
-		panic(err.Error())
+// middleware lists the middleware wrapping every request, as c asks. It
+// wraps the router rather than the routes of each API, so that it also sees
+// requests no route matches.
+func middleware(c httpConfig) []api.Middleware {
+	mws := []api.Middleware{api.RequestID}
+	if c.AccessLog {
+		mws = append(mws, api.AccessLog(os.Stderr))
+	}
+	mws = append(mws, api.Recover)
+	if len(c.CORSOrigins) > 0 {
+		mws = append(mws, api.CORS(api.CORSOptions{AllowedOrigins: c.CORSOrigins, MaxAge: time.Hour}))
+	}
+	if c.Gzip {
+		mws = append(mws, api.Gzip)
 	}
+
+	return mws
+}
+
+// bind binds the routes of every API to r.
+func bind(r api.Router, c *config, db *sqlx.DB, s startup) error {
+	for _, a := range apis(c, db, s) {
+		if err := a.Bind(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s Shelf) Get(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	id := ps.ByName("id")
-	qms := []qm.QueryMod{qm.Where("id = ?", id)}
-        o, e1 := models.Shelves(s.DB, qms...).One()
-	if e1 != nil {
-		fmt.Println("error", e1)
-	}	
+// openDB loads the config for cmd and opens its database.
+func openDB(cmd *cobra.Command) (*config, *sqlx.DB, error) {
+	c, err := loadConfig(cmd.Flags())
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if err := json.NewEncoder(w).Encode(o); err != nil {
-		panic(err.Error())
+	db, err := c.DB.open()
+	if err != nil {
+		return nil, nil, err
 	}
+
+	return c, db, nil
 }
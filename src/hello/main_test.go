@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hello/api"
+	"hello/errors"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	log.SetOutput(ioutil.Discard)
+}
+
+// mockDB returns a MySQL database whose statements are mocked.
+func mockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sqlx.NewDb(db, "mysql"), mock
+}
+
+// expectationsMet fails t unless every expected statement was run.
+func expectationsMet(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// expectCommit expects a transaction recording n changesets in audit_log
+// and the outbox to be committed.
+func expectCommit(mock sqlmock.Sqlmock, n int) {
+	for i := 0; i < n; i++ {
+		mock.ExpectExec("INSERT INTO `audit_log`").WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	for i := 0; i < n; i++ {
+		mock.ExpectExec("INSERT INTO `outbox`").WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+}
+
+// call serves a request with body, if any, to the routes of a.
+func call(t *testing.T, a api.API, method, target, body string) *httptest.ResponseRecorder {
+	r := httprouter.New()
+	if err := a.Bind(r); err != nil {
+		t.Fatal(err)
+	}
+
+	var rb io.Reader
+	if body != "" {
+		rb = strings.NewReader(body)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, rb))
+	return w
+}
+
+// errorOf decodes the first error of the error response w, failing t if
+// there is none.
+func errorOf(t *testing.T, w *httptest.ResponseRecorder) *errors.Error {
+	var env struct {
+		Errors []*errors.Error `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil || len(env.Errors) == 0 {
+		t.Fatalf("want an error response, got %d %s", w.Code, w.Body.String())
+	}
+
+	return env.Errors[0]
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"migrate"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+)
+
+func migrateCmd() *cobra.Command {
+	m := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the database schema",
+	}
+
+	m.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply the migrations that haven't been",
+			RunE:  withMigrator(migrateUp),
+		},
+		&cobra.Command{
+			Use:   "down [n]",
+			Short: "Revert the last n applied migrations, 1 by default",
+			RunE:  withMigrator(migrateDown),
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Print which migrations have been applied",
+			RunE:  withMigrator(migrateStatus),
+		},
+	)
+
+	return m
+}
+
+// withMigrator adapts fn to a cobra command run with a Migrator of the
+// configured database and its migrations.
+func withMigrator(fn func(m *migrate.Migrator, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, db, err := openDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		m, err := migrator(c, db)
+		if err != nil {
+			return err
+		}
+
+		return fn(m, args)
+	}
+}
+
+// migrator returns a Migrator of db with the migrations of its driver.
+func migrator(c *config, db *sqlx.DB) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(filepath.Join(c.Migrations, c.DB.Driver))
+	if err != nil {
+		return nil, err
+	}
+
+	return &migrate.Migrator{DB: db.DB, Driver: c.DB.Driver, Migrations: migrations}, nil
+}
+
+func migrateUp(m *migrate.Migrator, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("up takes no arguments")
+	}
+
+	done, err := m.Up(context.Background())
+	for _, mig := range done {
+		fmt.Printf("applied  %04d_%s\n", mig.Version, mig.Name)
+	}
+
+	return err
+}
+
+func migrateDown(m *migrate.Migrator, args []string) error {
+	n := 1
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return fmt.Errorf("invalid number of migrations %q", args[0])
+		}
+	default:
+		return fmt.Errorf("down takes at most one argument")
+	}
+
+	done, err := m.Down(context.Background(), n)
+	for _, mig := range done {
+		fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+	}
+
+	return err
+}
+
+func migrateStatus(m *migrate.Migrator, args []string) error {
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		status := "pending"
+		if s.Applied {
+			status = "applied at " + s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%-24s %s\n", s.Version, s.Name, status)
+	}
+
+	return nil
+}
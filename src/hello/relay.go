@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"outbox"
+
+	"github.com/spf13/cobra"
+)
+
+func relayCmd() *cobra.Command {
+	relay := &cobra.Command{
+		Use:   "relay",
+		Short: "Publish the events of the outbox until stopped",
+		Long: "Publish the events the API writes to the outbox table, as lines of JSON.\n" +
+			"Run a single relay per database, next to serve.",
+	}
+	relay.Flags().String("to", "-", "file to append the events to, - for standard output")
+	relay.Flags().Int("batch_size", 100, "number of events read at a time")
+	relay.Flags().Duration("interval", time.Second, "how long to wait for new events when idle")
+
+	relay.RunE = func(cmd *cobra.Command, args []string) error {
+		c, db, err := openDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		to, _ := cmd.Flags().GetString("to")
+		p := outbox.NewStdoutPublisher()
+		if to != "-" {
+			if p, err = outbox.NewFilePublisher(to); err != nil {
+				return err
+			}
+			defer p.Close()
+		}
+
+		r := &outbox.Relay{DB: db.DB, Publisher: p, Driver: c.DB.Driver}
+		r.BatchSize, _ = cmd.Flags().GetInt("batch_size")
+		r.Interval, _ = cmd.Flags().GetDuration("interval")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigs)
+		go func() {
+			select {
+			case sig := <-sigs:
+				log.Printf("hello: %s, stopping the relay", sig)
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		log.Printf("hello: relaying the outbox")
+		if err := r.Run(ctx); err != context.Canceled {
+			return err
+		}
+
+		return nil
+	}
+
+	return relay
+}
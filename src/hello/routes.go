@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"hello/api"
+
+	"github.com/spf13/cobra"
+)
+
+func routesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "routes",
+		Short: "Print every route the APIs bind",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var routes api.Routes
+			if err := bind(&routes, &config{}, nil, startup{}); err != nil {
+				return err
+			}
+
+			sort.SliceStable(routes, func(i, j int) bool {
+				return routes[i].Path < routes[j].Path
+			})
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			for _, r := range routes {
+				fmt.Fprintf(w, "%s\t%s\n", r.Method, r.Path)
+			}
+
+			return w.Flush()
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+	"github.com/vattle/sqlboiler/boil"
+	"gopkg.in/nullbio/null.v6"
+)
+
+// fixtures is the content of a seed file: shelves with the books on them,
+// and books on no shelf.
+type fixtures struct {
+	Shelves []struct {
+		Area  string        `json:"area"`
+		Books []bookFixture `json:"books"`
+	} `json:"shelves"`
+	Books []bookFixture `json:"books"`
+}
+
+type bookFixture struct {
+	Name   string `json:"name"`
+	Author string `json:"author"`
+}
+
+func (f bookFixture) book() *models.Book {
+	return &models.Book{Name: null.StringFrom(f.Name), Author: null.StringFrom(f.Author)}
+}
+
+func seedCmd() *cobra.Command {
+	seed := &cobra.Command{
+		Use:   "seed",
+		Short: "Load fixture data into the database",
+	}
+	seed.Flags().String("file", "fixtures/library.json", "fixture file to load")
+
+	seed.RunE = func(cmd *cobra.Command, args []string) error {
+		_, db, err := openDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		path, _ := cmd.Flags().GetString("file")
+		return seedFile(db, path)
+	}
+
+	return seed
+}
+
+// seedFile inserts the fixtures at path in a single transaction.
+func seedFile(db *sqlx.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var fx fixtures
+	if err := json.NewDecoder(f).Decode(&fx); err != nil {
+		return fmt.Errorf("unable to read %s: %v", path, err)
+	}
+
+	books := len(fx.Books)
+	err = withTx(context.Background(), db, func(exec boil.Executor) error {
+		for _, s := range fx.Shelves {
+			o := &models.Shelf{Area: null.StringFrom(s.Area)}
+			if err := o.Insert(exec); err != nil {
+				return err
+			}
+
+			related := make([]*models.Book, len(s.Books))
+			for i, b := range s.Books {
+				related[i] = b.book()
+			}
+			if err := o.AddBooks(exec, true, related...); err != nil {
+				return err
+			}
+			books += len(related)
+		}
+
+		for _, b := range fx.Books {
+			if err := b.book().Insert(exec); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("seeded %d shelves and %d books\n", len(fx.Shelves), books)
+	return nil
+}
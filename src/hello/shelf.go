@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"hello/api"
+	"hello/errors"
+
+	"models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/julienschmidt/httprouter"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+type Shelf struct{ *sqlx.DB }
+
+var (
+	_ = api.API(Shelf{})
+	_ = api.Checker(Shelf{})
+)
+
+func (s Shelf) Bind(r api.Router) error {
+	r.GET("/shelves", api.Wrap(s.GetAll))
+	r.POST("/shelves", api.Wrap(s.Create))
+	r.GET("/shelves/:id", api.Wrap(s.Get))
+	r.PUT("/shelves/:id", api.Wrap(s.Replace))
+	r.PATCH("/shelves/:id", api.Wrap(s.Modify))
+	r.DELETE("/shelves/:id", api.Wrap(s.Delete))
+
+	r.GET("/shelves/:id/books", api.Wrap(s.Relations))
+	r.POST("/shelves/:id/books", api.Wrap(s.Relations))
+	r.PUT("/shelves/:id/books", api.Wrap(s.Relations))
+	r.DELETE("/shelves/:id/books", api.Wrap(s.Relations))
+	r.GET("/shelves/:id/books/:bookID", api.Wrap(s.OneRelation))
+	r.POST("/shelves/:id/books/:bookID", api.Wrap(s.OneRelation))
+	r.PUT("/shelves/:id/books/:bookID", api.Wrap(s.OneRelation))
+	r.DELETE("/shelves/:id/books/:bookID", api.Wrap(s.OneRelation))
+
+	// Deprecated singular path, kept for existing clients.
+	r.GET("/shelf/:id", api.Wrap(s.Get))
+
+	return nil
+}
+
+// Checks implements api.Checker: the shelf table has to be readable.
+func (s Shelf) Checks() map[string]api.Check {
+	return map[string]api.Check{
+		"shelf": func(ctx context.Context) error {
+			_, err := models.Shelves(s.DB).ExistsContext(ctx)
+			return err
+		},
+	}
+}
+
+// shelfBody is the request body accepted by Create, Replace and Modify.
+// Fields left out of the body are unset, which lets Modify tell an absent
+// column apart from one being cleared with null.
+type shelfBody struct {
+	Area optString `json:"area"`
+}
+
+const maxAreaLen = 255
+
+// validate checks the body before it reaches the model. Unless partial is
+// set, every column has to be present, if only as null.
+func (b *shelfBody) validate(partial bool) *errors.Error {
+	if !b.Area.Set {
+		if partial {
+			return nil
+		}
+		return errors.New(errors.DATA_VALIDATION_FAIL, "area", "is required")
+	}
+
+	if len(b.Area.Value.String) > maxAreaLen {
+		return errors.New(errors.DATA_VALIDATION_FAIL, "area", fmt.Sprintf("must be at most %d characters", maxAreaLen))
+	}
+
+	return nil
+}
+
+// apply copies the fields present in the body onto o.
+func (b *shelfBody) apply(o *models.Shelf) {
+	if b.Area.Set {
+		o.Area = b.Area.Value
+	}
+}
+
+// GetAll lists one page of shelves, sorted and filtered as the query asks.
+func (s Shelf) GetAll(w http.ResponseWriter, r *http.Request, _ httprouter.Params) *errors.Error {
+	p, err := parseList(r, s.DB.DriverName(), models.ShelfFieldMapping)
+	if err != nil {
+		return err
+	}
+
+	total, e1 := models.Shelves(s.DB, p.filterMods()...).CountContext(r.Context())
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	q := models.Shelves(s.DB, p.pageMods()...).SortBy(p.sort...).PageSize(int(p.PerPage))
+	if p.Before != "" {
+		q = q.Before(p.Before)
+	} else {
+		q = q.After(p.After)
+	}
+
+	o, cursors, e1 := q.PageContext(r.Context())
+	if e1 != nil {
+		return errors.From(e1)
+	}
+	if o == nil {
+		o = models.ShelfSlice{}
+	}
+
+	return writeJSON(w, http.StatusOK, p.page(r.URL, o, total, cursors))
+}
+
+func (s Shelf) Get(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := s.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(w, http.StatusOK, o)
+}
+
+func (s Shelf) Create(w http.ResponseWriter, r *http.Request, _ httprouter.Params) *errors.Error {
+	b := &shelfBody{}
+	if err := readBody(r, b, false); err != nil {
+		return err
+	}
+
+	o := &models.Shelf{}
+	b.apply(o)
+
+	e1 := withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+		return o.InsertContext(r.Context(), exec)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/shelves/%d", o.ID))
+	return writeJSON(w, http.StatusCreated, o)
+}
+
+// Replace overwrites every column of the shelf with the request body.
+func (s Shelf) Replace(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	return s.update(w, r, ps, false)
+}
+
+// Modify only changes the columns present in the request body.
+func (s Shelf) Modify(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	return s.update(w, r, ps, true)
+}
+
+func (s Shelf) update(w http.ResponseWriter, r *http.Request, ps httprouter.Params, partial bool) *errors.Error {
+	o, err := s.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	b := &shelfBody{}
+	if err := readBody(r, b, partial); err != nil {
+		return err
+	}
+	b.apply(o)
+
+	e1 := withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+		return o.UpdateContext(r.Context(), exec)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	return writeJSON(w, http.StatusOK, o)
+}
+
+func (s Shelf) Delete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := s.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+
+	e1 := withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+		return o.DeleteContext(r.Context(), exec)
+	})
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// find loads the shelf named by the :id parameter.
+func (s Shelf) find(ctx context.Context, ps httprouter.Params) (*models.Shelf, *errors.Error) {
+	id, err := paramID(ps, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	o, e1 := models.FindShelfContext(ctx, s.DB, id)
+	if e1 != nil {
+		if e := errors.From(e1); e.Code != errors.DATA_ENTITY_NOT_FOUND {
+			return nil, e
+		}
+		return nil, errors.New(errors.DATA_ENTITY_NOT_FOUND, "id", "Shelf not found")
+	}
+
+	return o, nil
+}
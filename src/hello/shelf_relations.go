@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"hello/errors"
+
+	"models"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// Relations lists (GET), appends (POST), replaces (PUT) or removes (DELETE)
+// the records of the relation named by the path, e.g. /shelves/1/books. All
+// but GET take a relationBody.
+func (s Shelf) Relations(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := s.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+	name := relationName(r)
+
+	if r.Method != "GET" {
+		rb := &relationBody{}
+		if err := readBody(r, rb, false); err != nil {
+			return err
+		}
+
+		e1 := withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+			switch r.Method {
+			case "POST":
+				return s.addRelationByIDs(r.Context(), exec, o, name, rb.ids()...)
+			case "PUT":
+				return s.setRelationByIDs(r.Context(), exec, o, name, rb.ids()...)
+			case "DELETE":
+				return s.deleteRelationByIDs(r.Context(), exec, o, name, rb.ids()...)
+			}
+			return nil
+		})
+		if e1 != nil {
+			return errors.From(e1)
+		}
+	}
+
+	rels, e1 := s.getRelation(r.Context(), s.DB, o, name)
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	return writeJSON(w, http.StatusOK, rels)
+}
+
+// OneRelation reads (GET), appends (POST), replaces the relation with
+// (PUT) or removes (DELETE) the single record named by the path, e.g.
+// /shelves/1/books/2.
+func (s Shelf) OneRelation(w http.ResponseWriter, r *http.Request, ps httprouter.Params) *errors.Error {
+	o, err := s.find(r.Context(), ps)
+	if err != nil {
+		return err
+	}
+	name := relationName(r)
+
+	relID, err := paramID(ps, "bookID")
+	if err != nil {
+		return err
+	}
+
+	var e1 error
+	switch r.Method {
+	case "GET":
+		var rel interface{}
+		if rel, e1 = s.getOneRelation(r.Context(), s.DB, o, name, relID); e1 != nil {
+			return errors.From(e1)
+		}
+		return writeJSON(w, http.StatusOK, rel)
+	case "POST":
+		e1 = withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+			return s.addRelationByIDs(r.Context(), exec, o, name, relID)
+		})
+	case "PUT":
+		e1 = withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+			return s.setRelationByIDs(r.Context(), exec, o, name, relID)
+		})
+	case "DELETE":
+		e1 = withTx(r.Context(), s.DB, func(exec boil.Executor) error {
+			if _, err := s.getOneRelation(r.Context(), exec, o, name, relID); err != nil {
+				return err
+			}
+			return s.deleteRelationByIDs(r.Context(), exec, o, name, relID)
+		})
+	}
+
+	if e1 != nil {
+		return errors.From(e1)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// getRelation lists the records of the named relation.
+func (s Shelf) getRelation(ctx context.Context, exec boil.Executor, o *models.Shelf, name string) (interface{}, error) {
+	switch name {
+	case "books":
+		books, err := o.Books(exec).AllContext(ctx)
+		if books == nil {
+			books = models.BookSlice{}
+		}
+		return books, err
+	}
+
+	return nil, unknownRelation(name)
+}
+
+// getOneRelation returns the related record with the given ID, failing with
+// DATA_ENTITY_NOT_FOUND when it isn't part of the named relation.
+func (s Shelf) getOneRelation(ctx context.Context, exec boil.Executor, o *models.Shelf, name string, id int64) (interface{}, error) {
+	switch name {
+	case "books":
+		return o.Books(exec, models.BookWhere.ID.EQ(id)).OneContext(ctx)
+	}
+
+	return nil, unknownRelation(name)
+}
+
+// setRelationByIDs replaces the named relation with the given IDs; records
+// previously related are detached.
+func (s Shelf) setRelationByIDs(ctx context.Context, exec boil.Executor, o *models.Shelf, name string, ids ...int64) error {
+	switch name {
+	case "books":
+		books, err := findBooks(ctx, exec, ids...)
+		if err != nil {
+			return err
+		}
+		return o.SetBooksContext(ctx, exec, false, books...)
+	}
+
+	return unknownRelation(name)
+}
+
+// addRelationByIDs appends the given IDs to the named relation.
+func (s Shelf) addRelationByIDs(ctx context.Context, exec boil.Executor, o *models.Shelf, name string, ids ...int64) error {
+	switch name {
+	case "books":
+		books, err := findBooks(ctx, exec, ids...)
+		if err != nil {
+			return err
+		}
+		return o.AddBooksContext(ctx, exec, false, books...)
+	}
+
+	return unknownRelation(name)
+}
+
+// deleteRelationByIDs detaches the given IDs from the named relation. Every
+// ID has to be part of the relation.
+func (s Shelf) deleteRelationByIDs(ctx context.Context, exec boil.Executor, o *models.Shelf, name string, ids ...int64) error {
+	switch name {
+	case "books":
+		if len(ids) == 0 {
+			return nil
+		}
+
+		books, err := o.Books(exec, models.BookWhere.ID.IN(ids...)).AllContext(ctx)
+		if err != nil {
+			return err
+		}
+		if err := missingIDs(ids, bookIDs(books)); err != nil {
+			return err
+		}
+		return o.RemoveBooksContext(ctx, exec, books...)
+	}
+
+	return unknownRelation(name)
+}
+
+// findBooks loads the books with the given IDs, failing with
+// DATA_VALIDATION_FAIL when any of them doesn't exist.
+func findBooks(ctx context.Context, exec boil.Executor, ids ...int64) (models.BookSlice, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	books, err := models.Books(exec, models.BookWhere.ID.IN(ids...)).AllContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := missingIDs(ids, bookIDs(books)); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+func bookIDs(books models.BookSlice) []int64 {
+	ids := make([]int64, len(books))
+	for i, b := range books {
+		ids[i] = b.ID
+	}
+
+	return ids
+}
+
+func unknownRelation(name string) error {
+	return errors.New(errors.DATA_ENTITY_NOT_FOUND, "relation", fmt.Sprintf("unknown relation %q", name))
+}
+
+// relationName returns the relation segment of a path such as
+// /shelves/1/books or /shelves/1/books/2.
+func relationName(r *http.Request) string {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		return ""
+	}
+
+	return parts[2]
+}
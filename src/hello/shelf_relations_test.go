@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"hello/errors"
+
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestShelfRelations(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+	mock.ExpectQuery("FROM `book`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(2, "Dune", nil, 1).AddRow(3, "Emma", nil, 1))
+
+	w := call(t, Shelf{db}, "GET", "/shelves/1/books", "")
+
+	var books models.BookSlice
+	if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || len(books) != 2 || books[0].ID != 2 || books[1].ID != 3 {
+		t.Errorf("want books 2 and 3, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfRelationsUnknownBook(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `book`").
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(2, "Dune", nil, nil))
+	mock.ExpectRollback()
+
+	w := call(t, Shelf{db}, "POST", "/shelves/1/books", `{"items":[{"id":2},{"id":3}]}`)
+	if e := errorOf(t, w); w.Code != http.StatusUnprocessableEntity || e.Field != "items" || e.Message != "unknown ids [3]" {
+		t.Errorf("want a DATA_VALIDATION_FAIL of book 3, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfRelationsInvalidBody(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+
+	w := call(t, Shelf{db}, "PUT", "/shelves/1/books", `{"items":[{"id":0}]}`)
+	if e := errorOf(t, w); w.Code != http.StatusUnprocessableEntity || e.Code != errors.DATA_VALIDATION_FAIL {
+		t.Errorf("want a DATA_VALIDATION_FAIL, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfOneRelationNotRelated(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `book`").WillReturnRows(sqlmock.NewRows(bookColumns))
+	mock.ExpectRollback()
+
+	w := call(t, Shelf{db}, "DELETE", "/shelves/1/books/3", "")
+	if e := errorOf(t, w); w.Code != http.StatusNotFound || e.Code != errors.DATA_ENTITY_NOT_FOUND {
+		t.Errorf("want a DATA_ENTITY_NOT_FOUND, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
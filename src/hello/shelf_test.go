@@ -0,0 +1,214 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"hello/errors"
+
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var shelfColumns = []string{"id", "area", "deleted_at"}
+
+// expectShelf expects shelf id to be found, holding area.
+func expectShelf(mock sqlmock.Sqlmock, id int64, area interface{}) {
+	mock.ExpectQuery(regexp.QuoteMeta("select * from `shelf` where `id`=? and `deleted_at` is null")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(shelfColumns).AddRow(id, area, nil))
+}
+
+func TestShelfGet(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+
+	w := call(t, Shelf{db}, "GET", "/shelves/1", "")
+
+	var o models.Shelf
+	if err := json.Unmarshal(w.Body.Bytes(), &o); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || o.ID != 1 || o.Area.String != "north" {
+		t.Errorf("want shelf 1 in the north, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfGetNotFound(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	mock.ExpectQuery("select \\* from `shelf`").WillReturnRows(sqlmock.NewRows(shelfColumns))
+
+	w := call(t, Shelf{db}, "GET", "/shelves/1", "")
+	if e := errorOf(t, w); w.Code != http.StatusNotFound || e.Code != errors.DATA_ENTITY_NOT_FOUND {
+		t.Errorf("want a DATA_ENTITY_NOT_FOUND, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfGetInvalidID(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	w := call(t, Shelf{db}, "GET", "/shelves/one", "")
+	if e := errorOf(t, w); w.Code != http.StatusBadRequest || e.Code != errors.REQUEST_INVALID_PARAM || e.Field != "id" {
+		t.Errorf("want a REQUEST_INVALID_PARAM of id, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfCreate(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `shelf`").WillReturnResult(sqlmock.NewResult(7, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Shelf{db}, "POST", "/shelves", `{"area":"north"}`)
+	if w.Code != http.StatusCreated || w.Header().Get("Location") != "/shelves/7" {
+		t.Errorf("want shelf 7 created, got %d at %q", w.Code, w.Header().Get("Location"))
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfCreateInvalid(t *testing.T) {
+	t.Parallel()
+
+	long := make([]byte, maxAreaLen+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	tests := []struct {
+		body   string
+		status int
+		code   errors.Code
+	}{
+		{`{"area":`, http.StatusBadRequest, errors.DATA_JSON_PARSE_FAIL},
+		{`{}`, http.StatusUnprocessableEntity, errors.DATA_VALIDATION_FAIL},
+		{`{"area":"` + string(long) + `"}`, http.StatusUnprocessableEntity, errors.DATA_VALIDATION_FAIL},
+	}
+
+	for _, test := range tests {
+		db, mock := mockDB(t)
+		w := call(t, Shelf{db}, "POST", "/shelves", test.body)
+		if e := errorOf(t, w); w.Code != test.status || e.Code != test.code {
+			t.Errorf("%s: want %d %s, got %d %+v", test.body, test.status, test.code, w.Code, e)
+		}
+		expectationsMet(t, mock)
+		db.Close()
+	}
+}
+
+func TestShelfReplace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body string
+		area interface{}
+	}{
+		{`{"area":"south"}`, "south"},
+		// A null area clears it, and counts as present
+		{`{"area":null}`, nil},
+	}
+
+	for _, test := range tests {
+		db, mock := mockDB(t)
+		expectShelf(mock, 1, "north")
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `shelf` SET `area`=? WHERE `id`=? AND `area`=?")).
+			WithArgs(test.area, 1, "north").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectCommit(mock, 1)
+
+		w := call(t, Shelf{db}, "PUT", "/shelves/1", test.body)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: want 200, got %d %s", test.body, w.Code, w.Body.String())
+		}
+		expectationsMet(t, mock)
+		db.Close()
+	}
+}
+
+func TestShelfModify(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// An absent area is left alone, so there is nothing to update
+	expectShelf(mock, 1, "north")
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	w := call(t, Shelf{db}, "PATCH", "/shelves/1", `{}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("want 200, got %d %s", w.Code, w.Body.String())
+	}
+
+	// A null one is cleared
+	expectShelf(mock, 1, "north")
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `shelf` SET `area`=? WHERE `id`=? AND `area`=?")).
+		WithArgs(nil, 1, "north").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCommit(mock, 1)
+
+	w = call(t, Shelf{db}, "PATCH", "/shelves/1", `{"area":null}`)
+
+	var o models.Shelf
+	if err := json.Unmarshal(w.Body.Bytes(), &o); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || o.Area.Valid {
+		t.Errorf("want the area cleared, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfDelete(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `shelf` SET `deleted_at`").WillReturnResult(sqlmock.NewResult(0, 1))
+	expectCommit(mock, 1)
+
+	w := call(t, Shelf{db}, "DELETE", "/shelves/1", "")
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("want 204, got %d %s", w.Code, w.Body.String())
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfDeleteFailing(t *testing.T) {
+	t.Parallel()
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	expectShelf(mock, 1, "north")
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `shelf` SET `deleted_at`").WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	w := call(t, Shelf{db}, "DELETE", "/shelves/1", "")
+	if e := errorOf(t, w); w.Code != http.StatusInternalServerError || e.Code != errors.INTERNAL_DATABASE_ERROR {
+		t.Errorf("want an INTERNAL_DATABASE_ERROR, got %d %+v", w.Code, e)
+	}
+	expectationsMet(t, mock)
+}
@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// dialect holds the statements that differ between databases.
+type dialect struct {
+	create        string
+	exists        string
+	selectApplied string
+	insert        string
+	delete        string
+
+	// tryLock selects whether it took the lock named lockKey, without
+	// waiting for it. The lock belongs to the session, and unlock releases
+	// it.
+	tryLock string
+	unlock  string
+	lockKey interface{}
+}
+
+const selectApplied = "SELECT version, name, applied_at FROM " + Table + " ORDER BY version"
+
+// exists counts the tables named Table in the schema of the session.
+const exists = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = %s AND table_name = %s"
+
+var dialects = map[string]*dialect{
+	"mysql": {
+		create: "CREATE TABLE IF NOT EXISTS `" + Table + "` (" +
+			"`version` bigint(20) NOT NULL, " +
+			"`name` varchar(255) NOT NULL, " +
+			"`applied_at` datetime NOT NULL, " +
+			"PRIMARY KEY (`version`)" +
+			")",
+		exists:        fmt.Sprintf(exists, "DATABASE()", "'"+Table+"'"),
+		selectApplied: selectApplied,
+		insert:        "INSERT INTO " + Table + " (version, name, applied_at) VALUES (?, ?, ?)",
+		delete:        "DELETE FROM " + Table + " WHERE version = ?",
+		tryLock:       "SELECT COALESCE(GET_LOCK(?, 0), 0)",
+		unlock:        "SELECT RELEASE_LOCK(?)",
+		lockKey:       Table,
+	},
+	"postgres": {
+		create: `CREATE TABLE IF NOT EXISTS "` + Table + `" (` +
+			`"version" bigint NOT NULL PRIMARY KEY, ` +
+			`"name" varchar(255) NOT NULL, ` +
+			`"applied_at" timestamp NOT NULL` +
+			`)`,
+		exists:        fmt.Sprintf(exists, "current_schema()", "'"+Table+"'"),
+		selectApplied: selectApplied,
+		insert:        "INSERT INTO " + Table + " (version, name, applied_at) VALUES ($1, $2, $3)",
+		delete:        "DELETE FROM " + Table + " WHERE version = $1",
+		tryLock:       "SELECT pg_try_advisory_lock($1)",
+		unlock:        "SELECT pg_advisory_unlock($1)",
+		lockKey:       advisoryKey(Table),
+	},
+	"mssql": {
+		create: "IF OBJECT_ID(N'" + Table + "', N'U') IS NULL CREATE TABLE [" + Table + "] (" +
+			"[version] bigint NOT NULL PRIMARY KEY, " +
+			"[name] nvarchar(255) NOT NULL, " +
+			"[applied_at] datetime2 NOT NULL" +
+			")",
+		exists:        fmt.Sprintf(exists, "SCHEMA_NAME()", "'"+Table+"'"),
+		selectApplied: selectApplied,
+		insert:        "INSERT INTO " + Table + " (version, name, applied_at) VALUES (?, ?, ?)",
+		delete:        "DELETE FROM " + Table + " WHERE version = ?",
+		tryLock: "DECLARE @result int; " +
+			"EXEC @result = sp_getapplock @Resource = ?, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 0; " +
+			"SELECT CASE WHEN @result >= 0 THEN 1 ELSE 0 END",
+		unlock:  "EXEC sp_releaseapplock @Resource = ?, @LockOwner = 'Session'",
+		lockKey: Table,
+	},
+}
+
+func dialectOf(driver string) (*dialect, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("migrate: unsupported driver %q", driver)
+	}
+
+	return d, nil
+}
+
+// advisoryKey turns a lock name into the number Postgres locks by.
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
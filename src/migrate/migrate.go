@@ -0,0 +1,402 @@
+// Package migrate applies versioned SQL migrations to a database. The
+// versions applied are recorded in the schema_migrations table, and an
+// advisory lock held while migrating keeps concurrent deploys from applying
+// the same migration twice.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// Table records the migrations applied to a database.
+const Table = "schema_migrations"
+
+// ErrLocked is returned when another process held the migration lock for
+// longer than the LockTimeout of a Migrator.
+var ErrLocked = errors.New("migrate: database is locked by another migration")
+
+// Migration is a versioned change to the schema. Up applies it and Down
+// reverts it; either may hold several statements, each ending with a
+// semicolon at the end of a line.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status tells whether a migration has been applied. Migrations recorded
+// in the database but unknown to the Migrator are reported too, with the
+// name they were applied under.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in dir, from files named after their version,
+// name and direction, e.g. 0001_create_shelf.up.sql and
+// 0001_create_shelf.down.sql. The down file is optional; a migration
+// without one can't be reverted.
+func Load(dir string) ([]Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrate: unable to read migrations")
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, f := range files {
+		match := fileName.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "migrate: bad version in %s", f.Name())
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errors.Wrap(err, "migrate: unable to read migrations")
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is both %s and %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migrate: %d_%s has no up migration", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies Migrations to DB, a database of the given driver:
+// mysql, postgres or mssql.
+//
+// Each migration runs in a transaction along with its record in Table.
+// MySQL commits DDL statements as they run, so there a migration that fails
+// halfway has to be cleaned up by hand.
+type Migrator struct {
+	DB         *sql.DB
+	Driver     string
+	Migrations []Migration
+
+	// LockTimeout is how long to wait for another process to finish
+	// migrating, a minute by default.
+	LockTimeout time.Duration
+}
+
+// Up applies the migrations that haven't been, in order, and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, d *dialect) error {
+		applied, err := m.applied(ctx, conn, d)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.Migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+
+			if err := m.run(ctx, conn, d, mig, true); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the last n applied migrations, newest first, and returns
+// them.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, d *dialect) error {
+		applied, err := m.applied(ctx, conn, d)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0 && len(done) < n; i-- {
+			mig := m.Migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+
+			if strings.TrimSpace(mig.Down) == "" {
+				return fmt.Errorf("migrate: %d_%s can't be reverted", mig.Version, mig.Name)
+			}
+			if err := m.run(ctx, conn, d, mig, false); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status reports every migration, by version. It only reads the database,
+// which has none applied as long as Table does not exist.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	d, err := dialectOf(m.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+	if err := m.DB.QueryRowContext(ctx, d.exists).Scan(&n); err != nil {
+		return nil, errors.Wrap(err, "migrate: unable to find "+Table)
+	}
+
+	applied := map[int64]Status{}
+	if n > 0 {
+		if applied, err = m.applied(ctx, m.DB, d); err != nil {
+			return nil, err
+		}
+	}
+
+	var statuses []Status
+	for _, mig := range m.Migrations {
+		s := Status{Migration: mig}
+		if a, ok := applied[mig.Version]; ok {
+			s.Applied, s.AppliedAt = true, a.AppliedAt
+			delete(applied, mig.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for _, a := range applied {
+		statuses = append(statuses, a)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// locked runs fn on a connection holding the migration lock, once Table
+// exists.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, d *dialect) error) error {
+	d, err := dialectOf(m.Driver)
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.lock(ctx, conn, d); err != nil {
+		return err
+	}
+	defer exec(context.Background(), conn, d.unlock, d.lockKey)
+
+	if err := exec(ctx, conn, d.create); err != nil {
+		return errors.Wrap(err, "migrate: unable to create "+Table)
+	}
+
+	return fn(conn, d)
+}
+
+// lock polls for the migration lock until it is free or LockTimeout passed.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn, d *dialect) error {
+	timeout := m.LockTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var ok bool
+		if err := conn.QueryRowContext(ctx, d.tryLock, d.lockKey).Scan(&ok); err != nil {
+			return errors.Wrap(err, "migrate: unable to lock")
+		}
+		if ok {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return ErrLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// applied reads the migrations Table records.
+func (m *Migrator) applied(ctx context.Context, q queryer, d *dialect) (map[int64]Status, error) {
+	rows, err := q.QueryContext(ctx, d.selectApplied)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrate: unable to read "+Table)
+	}
+	defer rows.Close()
+
+	applied := map[int64]Status{}
+	for rows.Next() {
+		s := Status{Applied: true}
+		if err := rows.Scan(&s.Version, &s.Name, &s.AppliedAt); err != nil {
+			return nil, errors.Wrap(err, "migrate: unable to read "+Table)
+		}
+		applied[s.Version] = s
+	}
+
+	return applied, errors.Wrap(rows.Err(), "migrate: unable to read "+Table)
+}
+
+// run applies or reverts mig and records it.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, d *dialect, mig Migration, up bool) error {
+	script, record, args := mig.Down, d.delete, []interface{}{mig.Version}
+	if up {
+		script, record = mig.Up, d.insert
+		args = append(args, mig.Name, time.Now().In(boil.GetLocation()))
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range statements(script) {
+		if err := exec(ctx, tx, stmt); err != nil {
+			tx.Rollback()
+			return errors.Wrapf(err, "migrate: %d_%s failed", mig.Version, mig.Name)
+		}
+	}
+
+	if err := exec(ctx, tx, record, args...); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "migrate: unable to record "+mig.Name)
+	}
+
+	return tx.Commit()
+}
+
+// statements splits a script at the semicolons ending its lines. Those in
+// quotes or comments are left alone, and statements holding nothing but
+// comments are dropped. Quotes are escaped by doubling them.
+func statements(script string) []string {
+	var stmts []string
+	start, semi := 0, -1 // the statement being read, and the semicolon that may end it
+	code := false        // whether the statement has more than comments
+	var quote byte
+	lineComment, blockComment := false, false
+
+	for i := 0; i < len(script); i++ {
+		c, next := script[i], byte(0)
+		if i+1 < len(script) {
+			next = script[i+1]
+		}
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case blockComment:
+			if c == '*' && next == '/' {
+				blockComment = false
+				i++
+			}
+		case c == '\n':
+			lineComment = false
+			if semi >= 0 {
+				stmts = appendStatement(stmts, script[start:semi], code)
+				start, semi, code = i+1, -1, false
+			}
+		case lineComment:
+		case c == '-' && next == '-':
+			lineComment = true
+			i++
+		case c == '/' && next == '*':
+			blockComment = true
+			i++
+		case c == ';':
+			semi = i
+		case c == ' ' || c == '\t' || c == '\r':
+		default:
+			if c == '\'' || c == '"' || c == '`' {
+				quote = c
+			}
+			code, semi = true, -1
+		}
+	}
+
+	if semi >= 0 {
+		return appendStatement(stmts, script[start:semi], code)
+	}
+	return appendStatement(stmts, script[start:], code)
+}
+
+func appendStatement(stmts []string, stmt string, code bool) []string {
+	if !code {
+		return stmts
+	}
+
+	return append(stmts, strings.TrimSpace(stmt))
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func exec(ctx context.Context, e execer, query string, args ...interface{}) error {
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+
+	_, err := e.ExecContext(ctx, query, args...)
+	return err
+}
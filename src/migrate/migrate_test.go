@@ -0,0 +1,305 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_shelf", Up: "CREATE TABLE shelf (id bigint);", Down: "DROP TABLE shelf;"},
+	{Version: 2, Name: "create_book", Up: "CREATE TABLE book (id bigint);", Down: "DROP TABLE book;"},
+}
+
+var appliedColumns = []string{"version", "name", "applied_at"}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	d := dialects["mysql"]
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(d.exists)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(d.selectApplied)).
+		WillReturnRows(sqlmock.NewRows(appliedColumns).
+			AddRow(1, "create_shelf", at).
+			AddRow(7, "gone", at))
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: testMigrations}
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Migrations applied but unknown to m are reported as well
+	want := []struct {
+		version int64
+		name    string
+		applied bool
+	}{
+		{1, "create_shelf", true},
+		{2, "create_book", false},
+		{7, "gone", true},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("want %d statuses, got %d", len(want), len(statuses))
+	}
+	for i, s := range statuses {
+		if s.Version != want[i].version || s.Name != want[i].name || s.Applied != want[i].applied {
+			t.Errorf("status %d: want %+v, got %d %s %t", i, want[i], s.Version, s.Name, s.Applied)
+		}
+	}
+	if !statuses[0].AppliedAt.Equal(at) {
+		t.Errorf("want applied at %v, got %v", at, statuses[0].AppliedAt)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatusWithoutTable(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Status only reads, and does not create the table it would read
+	mock.ExpectQuery(regexp.QuoteMeta(dialects["mysql"].exists)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: testMigrations}
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range statuses {
+		if s.Applied {
+			t.Errorf("want %d_%s pending, got applied", s.Version, s.Name)
+		}
+	}
+	if len(statuses) != len(testMigrations) {
+		t.Errorf("want %d statuses, got %d", len(testMigrations), len(statuses))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"", nil},
+		{"CREATE TABLE a (id bigint);", []string{"CREATE TABLE a (id bigint)"}},
+		{"CREATE TABLE a (id bigint)", []string{"CREATE TABLE a (id bigint)"}},
+		{"CREATE TABLE a (\n  id bigint\n);\n\nDROP TABLE b;\n", []string{"CREATE TABLE a (\n  id bigint\n)", "DROP TABLE b"}},
+		// Only the semicolons ending a line end a statement
+		{"INSERT INTO a VALUES (';'); INSERT INTO a VALUES (1);", []string{"INSERT INTO a VALUES (';'); INSERT INTO a VALUES (1)"}},
+		// nor those in quotes, even at the end of a line
+		{"INSERT INTO a VALUES ('x;\ny');\nINSERT INTO a VALUES ('it''s;\n');", []string{"INSERT INTO a VALUES ('x;\ny')", "INSERT INTO a VALUES ('it''s;\n')"}},
+		{"CREATE TABLE `a;\n` (id bigint);", []string{"CREATE TABLE `a;\n` (id bigint)"}},
+		// nor those in comments
+		{"-- drop a;\nDROP TABLE b;", []string{"-- drop a;\nDROP TABLE b"}},
+		{"DROP TABLE a; -- and b;\nDROP TABLE b;", []string{"DROP TABLE a", "DROP TABLE b"}},
+		{"/* drop a;\n and b; */\nDROP TABLE b;", []string{"/* drop a;\n and b; */\nDROP TABLE b"}},
+		// and statements of nothing but comments, or nothing at all, are dropped
+		{"-- nothing to do;\n", nil},
+		{"DROP TABLE a;\n;\n  ;\n/* done */\n", []string{"DROP TABLE a"}},
+	}
+
+	for _, test := range tests {
+		if got := statements(test.script); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: want %q, got %q", test.script, test.want, got)
+		}
+	}
+}
+
+// expectLocked expects the migration lock to be taken and Table created.
+func expectLocked(mock sqlmock.Sqlmock, d *dialect) {
+	mock.ExpectQuery(regexp.QuoteMeta(d.tryLock)).
+		WithArgs(d.lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"ok"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta(d.create)).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectUnlocked expects the migration lock to be released.
+func expectUnlocked(mock sqlmock.Sqlmock, d *dialect) {
+	mock.ExpectExec(regexp.QuoteMeta(d.unlock)).
+		WithArgs(d.lockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestUp(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	d := dialects["mysql"]
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectLocked(mock, d)
+	mock.ExpectQuery(regexp.QuoteMeta(d.selectApplied)).
+		WillReturnRows(sqlmock.NewRows(appliedColumns).AddRow(1, "create_shelf", at))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE book (id bigint)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(d.insert)).
+		WithArgs(2, "create_book", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock, d)
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: testMigrations}
+	done, err := m.Up(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != 2 {
+		t.Errorf("want 2_create_book applied, got %+v", done)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpFailing(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The migration is rolled back and not recorded, and the lock released
+	d := dialects["mysql"]
+	expectLocked(mock, d)
+	mock.ExpectQuery(regexp.QuoteMeta(d.selectApplied)).WillReturnRows(sqlmock.NewRows(appliedColumns))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE shelf (id bigint)")).WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+	expectUnlocked(mock, d)
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: testMigrations}
+	done, err := m.Up(context.Background())
+	if err == nil || len(done) != 0 {
+		t.Errorf("want no migration applied and an error, got %+v %v", done, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpLocked(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Nothing is run, nor unlocked, without the lock
+	d := dialects["mysql"]
+	mock.ExpectQuery(regexp.QuoteMeta(d.tryLock)).
+		WithArgs(d.lockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"ok"}).AddRow(false))
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: testMigrations, LockTimeout: time.Nanosecond}
+	if _, err := m.Up(context.Background()); err != ErrLocked {
+		t.Errorf("want ErrLocked, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDown(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Only the newest migration is reverted
+	d := dialects["mysql"]
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectLocked(mock, d)
+	mock.ExpectQuery(regexp.QuoteMeta(d.selectApplied)).
+		WillReturnRows(sqlmock.NewRows(appliedColumns).
+			AddRow(1, "create_shelf", at).
+			AddRow(2, "create_book", at))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DROP TABLE book")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(d.delete)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock, d)
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: testMigrations}
+	done, err := m.Down(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != 2 {
+		t.Errorf("want 2_create_book reverted, got %+v", done)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDownIrreversible(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	d := dialects["mysql"]
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	expectLocked(mock, d)
+	mock.ExpectQuery(regexp.QuoteMeta(d.selectApplied)).
+		WillReturnRows(sqlmock.NewRows(appliedColumns).AddRow(1, "create_shelf", at))
+	expectUnlocked(mock, d)
+
+	m := &Migrator{DB: db, Driver: "mysql", Migrations: []Migration{{Version: 1, Name: "create_shelf", Up: "CREATE TABLE shelf (id bigint);"}}}
+	if done, err := m.Down(context.Background(), 1); err == nil || len(done) != 0 {
+		t.Errorf("want nothing reverted and an error, got %+v %v", done, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,4 @@
+CREATE TABLE [shelf] (
+  [id] bigint IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  [area] nvarchar(255) NULL
+);
@@ -0,0 +1,7 @@
+CREATE TABLE [book] (
+  [id] bigint IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  [name] nvarchar(255) NULL,
+  [author] nvarchar(255) NULL,
+  [shelf_id] bigint NULL CONSTRAINT [book_shelf_id_fk] FOREIGN KEY REFERENCES [shelf] ([id])
+);
+CREATE INDEX [book_shelf_id_fk] ON [book] ([shelf_id]);
@@ -0,0 +1,12 @@
+CREATE TABLE [audit_log] (
+  [id] bigint IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  [table_name] nvarchar(64) NOT NULL,
+  [primary_key] nvarchar(255) NULL,
+  [operation] nvarchar(16) NOT NULL,
+  [before_data] nvarchar(max) NULL,
+  [after_data] nvarchar(max) NULL,
+  [actor] nvarchar(255) NULL,
+  [request_id] nvarchar(64) NULL,
+  [created_at] datetime2 NOT NULL
+);
+CREATE INDEX [audit_log_table_name_primary_key] ON [audit_log] ([table_name], [primary_key]);
@@ -0,0 +1,11 @@
+CREATE TABLE [outbox] (
+  [id] bigint IDENTITY(1,1) NOT NULL PRIMARY KEY,
+  [topic] nvarchar(128) NOT NULL,
+  [payload] nvarchar(max) NOT NULL,
+  [created_at] datetime2 NOT NULL,
+  [attempts] int NOT NULL DEFAULT 0,
+  [next_attempt_at] datetime2 NOT NULL,
+  [last_error] nvarchar(max) NULL,
+  [published_at] datetime2 NULL
+);
+CREATE INDEX [outbox_published_at_next_attempt_at] ON [outbox] ([published_at], [next_attempt_at]);
@@ -0,0 +1 @@
+ALTER TABLE [shelf] ADD [deleted_at] datetime2 NULL;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS `shelf` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `area` varchar(255) DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
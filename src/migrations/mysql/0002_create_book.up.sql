@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS `book` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) DEFAULT NULL,
+  `author` varchar(255) DEFAULT NULL,
+  `shelf_id` bigint(20) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `book_shelf_id_fk` (`shelf_id`),
+  CONSTRAINT `book_shelf_id_fk` FOREIGN KEY (`shelf_id`) REFERENCES `shelf` (`id`)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `table_name` varchar(64) NOT NULL,
+  `primary_key` varchar(255) DEFAULT NULL,
+  `operation` varchar(16) NOT NULL,
+  `before_data` json DEFAULT NULL,
+  `after_data` json DEFAULT NULL,
+  `actor` varchar(255) DEFAULT NULL,
+  `request_id` varchar(64) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `audit_log_table_name_primary_key` (`table_name`, `primary_key`)
+);
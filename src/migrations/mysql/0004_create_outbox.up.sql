@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `outbox` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `topic` varchar(128) NOT NULL,
+  `payload` json NOT NULL,
+  `created_at` datetime NOT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL,
+  `last_error` text DEFAULT NULL,
+  `published_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `outbox_published_at_next_attempt_at` (`published_at`, `next_attempt_at`)
+);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS "shelf" (
+  "id" bigserial PRIMARY KEY,
+  "area" varchar(255)
+);
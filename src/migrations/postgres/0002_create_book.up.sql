@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS "book" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(255),
+  "author" varchar(255),
+  "shelf_id" bigint CONSTRAINT "book_shelf_id_fk" REFERENCES "shelf" ("id")
+);
+CREATE INDEX IF NOT EXISTS "book_shelf_id_fk" ON "book" ("shelf_id");
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "audit_log" (
+  "id" bigserial PRIMARY KEY,
+  "table_name" varchar(64) NOT NULL,
+  "primary_key" varchar(255),
+  "operation" varchar(16) NOT NULL,
+  "before_data" jsonb,
+  "after_data" jsonb,
+  "actor" varchar(255),
+  "request_id" varchar(64),
+  "created_at" timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "audit_log_table_name_primary_key" ON "audit_log" ("table_name", "primary_key");
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "topic" varchar(128) NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL,
+  "last_error" text,
+  "published_at" timestamp
+);
+CREATE INDEX IF NOT EXISTS "outbox_published_at_next_attempt_at" ON "outbox" ("published_at", "next_attempt_at");
@@ -0,0 +1 @@
+ALTER TABLE "shelf" DROP COLUMN "deleted_at";
@@ -0,0 +1 @@
+ALTER TABLE "shelf" ADD COLUMN "deleted_at" timestamp;
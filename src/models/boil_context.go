@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vattle/sqlboiler/boil"
+	"github.com/vattle/sqlboiler/queries"
+)
+
+// sqlExecutor is an executor whose queries are canceled with a context, as
+// *sql.DB and *sql.Tx are.
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// execContext executes query with exec, canceled with ctx when exec is an
+// sqlExecutor. Other executors only check ctx before the query is run.
+func execContext(ctx context.Context, exec boil.Executor, query string, args ...interface{}) (sql.Result, error) {
+	if se, ok := exec.(sqlExecutor); ok {
+		return se.ExecContext(ctx, query, args...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return exec.Exec(query, args...)
+}
+
+// queryContext runs query with exec, canceled with ctx when exec is an
+// sqlExecutor. Other executors only check ctx before the query is run.
+func queryContext(ctx context.Context, exec boil.Executor, query string, args ...interface{}) (*sql.Rows, error) {
+	if se, ok := exec.(sqlExecutor); ok {
+		return se.QueryContext(ctx, query, args...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return exec.Query(query, args...)
+}
+
+// queryRowContext runs query with exec, canceled with ctx when exec is an
+// sqlExecutor. Other executors ignore ctx, as a *sql.Row cannot be made to
+// hold its error.
+func queryRowContext(ctx context.Context, exec boil.Executor, query string, args ...interface{}) *sql.Row {
+	if se, ok := exec.(sqlExecutor); ok {
+		return se.QueryRowContext(ctx, query, args...)
+	}
+
+	return exec.QueryRow(query, args...)
+}
+
+// ctxExecutor runs the queries of exec canceled with ctx. It is how the
+// queries built by the queries package, which take no context, are canceled.
+type ctxExecutor struct {
+	exec boil.Executor
+	ctx  context.Context
+}
+
+func (e ctxExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return execContext(e.ctx, e.exec, query, args...)
+}
+
+func (e ctxExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return queryContext(e.ctx, e.exec, query, args...)
+}
+
+func (e ctxExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return queryRowContext(e.ctx, e.exec, query, args...)
+}
+
+// Context implements ContextExecutor.
+func (e ctxExecutor) Context() context.Context {
+	return e.ctx
+}
+
+// withContext returns a copy of q canceled with ctx, eager loading included.
+func withContext(ctx context.Context, q *queries.Query) *queries.Query {
+	c := *q
+	queries.SetExecutor(&c, ctxExecutor{exec: queries.GetExecutor(q), ctx: ctx})
+	return &c
+}
+
+// contextOf returns the context of exec when it is a ContextExecutor, such
+// as the executor of a query made by withContext.
+func contextOf(exec boil.Executor) context.Context {
+	if ce, ok := exec.(ContextExecutor); ok {
+		return ce.Context()
+	}
+
+	return context.Background()
+}
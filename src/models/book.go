@@ -17,6 +17,8 @@ import (
 	"gopkg.in/nullbio/null.v6"
 )
 
+import "context"
+
 // Book is an object representing the database table.
 type Book struct {
 	ID      int64       `boil:"id" json:"id" toml:"id" yaml:"id"`
@@ -38,6 +40,151 @@ var BookFieldMapping = map[string]string{
 	"shelf_id": "ShelfID",
 }
 
+// BookColumns holds the name of every column of book.
+var BookColumns = struct {
+	ID      string
+	Name    string
+	Author  string
+	ShelfID string
+}{
+	ID:      "id",
+	Name:    "name",
+	Author:  "author",
+	ShelfID: "shelf_id",
+}
+
+// whereHelperInt64 builds where clauses on a int64 column.
+type whereHelperInt64 struct{ field string }
+
+func (w whereHelperInt64) EQ(x int64) qm.QueryMod  { return qm.Where(w.field+" = ?", x) }
+func (w whereHelperInt64) NEQ(x int64) qm.QueryMod { return qm.Where(w.field+" <> ?", x) }
+func (w whereHelperInt64) LT(x int64) qm.QueryMod  { return qm.Where(w.field+" < ?", x) }
+func (w whereHelperInt64) LTE(x int64) qm.QueryMod { return qm.Where(w.field+" <= ?", x) }
+func (w whereHelperInt64) GT(x int64) qm.QueryMod  { return qm.Where(w.field+" > ?", x) }
+func (w whereHelperInt64) GTE(x int64) qm.QueryMod { return qm.Where(w.field+" >= ?", x) }
+
+// IN matches any of slice, which must not be empty.
+func (w whereHelperInt64) IN(slice ...int64) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" IN ?", values...)
+}
+
+// NIN matches none of slice, which must not be empty.
+func (w whereHelperInt64) NIN(slice ...int64) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" NOT IN ?", values...)
+}
+
+// whereHelperNullString builds where clauses on a null.String column.
+type whereHelperNullString struct{ field string }
+
+// EQ matches x, or NULL when x is null.
+func (w whereHelperNullString) EQ(x null.String) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNull()
+	}
+	return qm.Where(w.field+" = ?", x)
+}
+
+// NEQ matches values other than x, or not NULL when x is null.
+func (w whereHelperNullString) NEQ(x null.String) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNotNull()
+	}
+	return qm.Where(w.field+" <> ?", x)
+}
+
+func (w whereHelperNullString) IsNull() qm.QueryMod           { return qm.Where(w.field + " IS NULL") }
+func (w whereHelperNullString) IsNotNull() qm.QueryMod        { return qm.Where(w.field + " IS NOT NULL") }
+func (w whereHelperNullString) LT(x null.String) qm.QueryMod  { return qm.Where(w.field+" < ?", x) }
+func (w whereHelperNullString) LTE(x null.String) qm.QueryMod { return qm.Where(w.field+" <= ?", x) }
+func (w whereHelperNullString) GT(x null.String) qm.QueryMod  { return qm.Where(w.field+" > ?", x) }
+func (w whereHelperNullString) GTE(x null.String) qm.QueryMod { return qm.Where(w.field+" >= ?", x) }
+
+// IN matches any of slice, which must not be empty.
+func (w whereHelperNullString) IN(slice ...null.String) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" IN ?", values...)
+}
+
+// NIN matches none of slice, which must not be empty.
+func (w whereHelperNullString) NIN(slice ...null.String) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" NOT IN ?", values...)
+}
+
+// whereHelperNullInt64 builds where clauses on a null.Int64 column.
+type whereHelperNullInt64 struct{ field string }
+
+// EQ matches x, or NULL when x is null.
+func (w whereHelperNullInt64) EQ(x null.Int64) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNull()
+	}
+	return qm.Where(w.field+" = ?", x)
+}
+
+// NEQ matches values other than x, or not NULL when x is null.
+func (w whereHelperNullInt64) NEQ(x null.Int64) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNotNull()
+	}
+	return qm.Where(w.field+" <> ?", x)
+}
+
+func (w whereHelperNullInt64) IsNull() qm.QueryMod          { return qm.Where(w.field + " IS NULL") }
+func (w whereHelperNullInt64) IsNotNull() qm.QueryMod       { return qm.Where(w.field + " IS NOT NULL") }
+func (w whereHelperNullInt64) LT(x null.Int64) qm.QueryMod  { return qm.Where(w.field+" < ?", x) }
+func (w whereHelperNullInt64) LTE(x null.Int64) qm.QueryMod { return qm.Where(w.field+" <= ?", x) }
+func (w whereHelperNullInt64) GT(x null.Int64) qm.QueryMod  { return qm.Where(w.field+" > ?", x) }
+func (w whereHelperNullInt64) GTE(x null.Int64) qm.QueryMod { return qm.Where(w.field+" >= ?", x) }
+
+// IN matches any of slice, which must not be empty.
+func (w whereHelperNullInt64) IN(slice ...null.Int64) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" IN ?", values...)
+}
+
+// NIN matches none of slice, which must not be empty.
+func (w whereHelperNullInt64) NIN(slice ...null.Int64) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" NOT IN ?", values...)
+}
+
+// BookWhere holds a where clause helper for every column of
+// book, typed to the column, e.g. BookWhere.ID.EQ(x).
+// The column names are not qualified with the table name, so that the
+// helpers also apply to relationship queries, which alias the table.
+var BookWhere = struct {
+	ID      whereHelperInt64
+	Name    whereHelperNullString
+	Author  whereHelperNullString
+	ShelfID whereHelperNullInt64
+}{
+	ID:      whereHelperInt64{field: "`id`"},
+	Name:    whereHelperNullString{field: "`name`"},
+	Author:  whereHelperNullString{field: "`author`"},
+	ShelfID: whereHelperNullInt64{field: "`shelf_id`"},
+}
+
 // bookR is where relationships are stored.
 type bookR struct {
 	Shelf *Shelf
@@ -58,10 +205,15 @@ type (
 	// This should generally be used opposed to []Book.
 	BookSlice []*Book
 	// BookHook is the signature for custom Book hook methods
-	BookHook func(boil.Executor, *Book) error
+	BookHook func(context.Context, boil.Executor, *Book) error
 
 	bookQuery struct {
 		*queries.Query
+
+		// changes is set by WithChanges
+		changes bool
+		// keyset is the page asked for with SortBy, PageSize, After and Before
+		keyset keyset
 	}
 )
 
@@ -96,9 +248,9 @@ var bookAfterDeleteHooks []BookHook
 var bookAfterUpsertHooks []BookHook
 
 // doBeforeInsertHooks executes all "before insert" hooks.
-func (o *Book) doBeforeInsertHooks(exec boil.Executor) (err error) {
+func (o *Book) doBeforeInsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookBeforeInsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -107,9 +259,9 @@ func (o *Book) doBeforeInsertHooks(exec boil.Executor) (err error) {
 }
 
 // doBeforeUpdateHooks executes all "before Update" hooks.
-func (o *Book) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+func (o *Book) doBeforeUpdateHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookBeforeUpdateHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -118,9 +270,9 @@ func (o *Book) doBeforeUpdateHooks(exec boil.Executor) (err error) {
 }
 
 // doBeforeDeleteHooks executes all "before Delete" hooks.
-func (o *Book) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+func (o *Book) doBeforeDeleteHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookBeforeDeleteHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -129,9 +281,9 @@ func (o *Book) doBeforeDeleteHooks(exec boil.Executor) (err error) {
 }
 
 // doBeforeUpsertHooks executes all "before Upsert" hooks.
-func (o *Book) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+func (o *Book) doBeforeUpsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookBeforeUpsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -140,9 +292,9 @@ func (o *Book) doBeforeUpsertHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterInsertHooks executes all "after Insert" hooks.
-func (o *Book) doAfterInsertHooks(exec boil.Executor) (err error) {
+func (o *Book) doAfterInsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookAfterInsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -151,9 +303,9 @@ func (o *Book) doAfterInsertHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterSelectHooks executes all "after Select" hooks.
-func (o *Book) doAfterSelectHooks(exec boil.Executor) (err error) {
+func (o *Book) doAfterSelectHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookAfterSelectHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -162,9 +314,9 @@ func (o *Book) doAfterSelectHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterUpdateHooks executes all "after Update" hooks.
-func (o *Book) doAfterUpdateHooks(exec boil.Executor) (err error) {
+func (o *Book) doAfterUpdateHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookAfterUpdateHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -173,9 +325,9 @@ func (o *Book) doAfterUpdateHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterDeleteHooks executes all "after Delete" hooks.
-func (o *Book) doAfterDeleteHooks(exec boil.Executor) (err error) {
+func (o *Book) doAfterDeleteHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookAfterDeleteHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -184,9 +336,9 @@ func (o *Book) doAfterDeleteHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterUpsertHooks executes all "after Upsert" hooks.
-func (o *Book) doAfterUpsertHooks(exec boil.Executor) (err error) {
+func (o *Book) doAfterUpsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range bookAfterUpsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -230,11 +382,16 @@ func (q bookQuery) OneP() *Book {
 
 // One returns a single book record from the query.
 func (q bookQuery) One() (*Book, error) {
+	return q.OneContext(context.Background())
+}
+
+// OneContext returns a single book record from the query, canceled with ctx.
+func (q bookQuery) OneContext(ctx context.Context) (*Book, error) {
 	o := &Book{}
 
 	queries.SetLimit(q.Query, 1)
 
-	err := q.Bind(o)
+	err := withContext(ctx, q.Query).Bind(o)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, sql.ErrNoRows
@@ -242,7 +399,7 @@ func (q bookQuery) One() (*Book, error) {
 		return nil, errors.Wrap(err, "models: failed to execute a one query for book")
 	}
 
-	if err := o.doAfterSelectHooks(queries.GetExecutor(q.Query)); err != nil {
+	if err := o.doAfterSelectHooks(ctx, queries.GetExecutor(q.Query)); err != nil {
 		return o, err
 	}
 
@@ -261,16 +418,21 @@ func (q bookQuery) AllP() BookSlice {
 
 // All returns all Book records from the query.
 func (q bookQuery) All() (BookSlice, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all Book records from the query, canceled with ctx.
+func (q bookQuery) AllContext(ctx context.Context) (BookSlice, error) {
 	var o BookSlice
 
-	err := q.Bind(&o)
+	err := withContext(ctx, q.Query).Bind(&o)
 	if err != nil {
 		return nil, errors.Wrap(err, "models: failed to assign all query results to Book slice")
 	}
 
 	if len(bookAfterSelectHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doAfterSelectHooks(queries.GetExecutor(q.Query)); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, queries.GetExecutor(q.Query)); err != nil {
 				return o, err
 			}
 		}
@@ -291,12 +453,17 @@ func (q bookQuery) CountP() int64 {
 
 // Count returns the count of all Book records in the query.
 func (q bookQuery) Count() (int64, error) {
+	return q.CountContext(context.Background())
+}
+
+// CountContext returns the count of all Book records in the query, canceled with ctx.
+func (q bookQuery) CountContext(ctx context.Context) (int64, error) {
 	var count int64
 
 	queries.SetSelect(q.Query, nil)
 	queries.SetCount(q.Query)
 
-	err := q.Query.QueryRow().Scan(&count)
+	err := withContext(ctx, q.Query).QueryRow().Scan(&count)
 	if err != nil {
 		return 0, errors.Wrap(err, "models: failed to count book rows")
 	}
@@ -316,12 +483,17 @@ func (q bookQuery) ExistsP() bool {
 
 // Exists checks if the row exists in the table.
 func (q bookQuery) Exists() (bool, error) {
+	return q.ExistsContext(context.Background())
+}
+
+// ExistsContext checks if the row exists in the table, canceled with ctx.
+func (q bookQuery) ExistsContext(ctx context.Context) (bool, error) {
 	var count int64
 
 	queries.SetCount(q.Query)
 	queries.SetLimit(q.Query, 1)
 
-	err := q.Query.QueryRow().Scan(&count)
+	err := withContext(ctx, q.Query).QueryRow().Scan(&count)
 	if err != nil {
 		return false, errors.Wrap(err, "models: failed to check if book exists")
 	}
@@ -349,8 +521,14 @@ func (o *Book) ShelfF(exec boil.Executor, mods ...qm.QueryMod) shelfQuery {
 }
 
 // LoadShelf allows an eager lookup of values, cached into the
-// loaded structs of the objects.
+// loaded structs of the objects. The lookup is canceled with the context of
+// e, when it carries one.
 func (bookL) LoadShelf(e boil.Executor, singular bool, maybeBook interface{}) error {
+	return bookL{}.LoadShelfContext(contextOf(e), e, singular, maybeBook)
+}
+
+// LoadShelfContext is LoadShelf, with the lookup canceled with ctx.
+func (bookL) LoadShelfContext(ctx context.Context, e boil.Executor, singular bool, maybeBook interface{}) error {
 	var slice []*Book
 	var object *Book
 
@@ -378,7 +556,7 @@ func (bookL) LoadShelf(e boil.Executor, singular bool, maybeBook interface{}) er
 	}
 
 	query := fmt.Sprintf(
-		"select * from `shelf` where `id` in (%s)",
+		"select * from `shelf` where `id` in (%s) and `deleted_at` is null",
 		strmangle.Placeholders(dialect.IndexPlaceholders, count, 1, 1),
 	)
 
@@ -386,7 +564,7 @@ func (bookL) LoadShelf(e boil.Executor, singular bool, maybeBook interface{}) er
 		fmt.Fprintf(boil.DebugWriter, "%s\n%v\n", query, args)
 	}
 
-	results, err := e.Query(query, args...)
+	results, err := queryContext(ctx, e, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to eager load Shelf")
 	}
@@ -399,7 +577,7 @@ func (bookL) LoadShelf(e boil.Executor, singular bool, maybeBook interface{}) er
 
 	if len(bookAfterSelectHooks) != 0 {
 		for _, obj := range resultSlice {
-			if err := obj.doAfterSelectHooks(e); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
 				return err
 			}
 		}
@@ -426,9 +604,14 @@ func (bookL) LoadShelf(e boil.Executor, singular bool, maybeBook interface{}) er
 // Sets o.R.Shelf to related.
 // Adds o to related.R.Books.
 func (o *Book) SetShelf(exec boil.Executor, insert bool, related *Shelf) error {
+	return o.SetShelfContext(context.Background(), exec, insert, related)
+}
+
+// SetShelfContext is SetShelf, with its queries canceled with ctx.
+func (o *Book) SetShelfContext(ctx context.Context, exec boil.Executor, insert bool, related *Shelf) error {
 	var err error
 	if insert {
-		if err = related.Insert(exec); err != nil {
+		if err = related.InsertContext(ctx, exec); err != nil {
 			return errors.Wrap(err, "failed to insert into foreign table")
 		}
 	}
@@ -445,12 +628,16 @@ func (o *Book) SetShelf(exec boil.Executor, insert bool, related *Shelf) error {
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	if _, err = exec.Exec(updateQuery, values...); err != nil {
+	if _, err = execContext(ctx, exec, updateQuery, values...); err != nil {
 		return errors.Wrap(err, "failed to update local table")
 	}
 
 	o.ShelfID.Int64 = related.ID
 	o.ShelfID.Valid = true
+	// The row now holds the new key, later updates are checked against it
+	if o.readonly != nil {
+		o.readonly.ShelfID = o.ShelfID
+	}
 
 	if o.R == nil {
 		o.R = &bookR{
@@ -475,10 +662,15 @@ func (o *Book) SetShelf(exec boil.Executor, insert bool, related *Shelf) error {
 // Sets o.R.Shelf to nil.
 // Removes o from all passed in related items' relationships struct (Optional).
 func (o *Book) RemoveShelf(exec boil.Executor, related *Shelf) error {
+	return o.RemoveShelfContext(context.Background(), exec, related)
+}
+
+// RemoveShelfContext is RemoveShelf, with its queries canceled with ctx.
+func (o *Book) RemoveShelfContext(ctx context.Context, exec boil.Executor, related *Shelf) error {
 	var err error
 
 	o.ShelfID.Valid = false
-	if err = o.Update(exec, "shelf_id"); err != nil {
+	if err = o.UpdateContext(ctx, exec, "shelf_id"); err != nil {
 		o.ShelfID.Valid = true
 		return errors.Wrap(err, "failed to update local table")
 	}
@@ -511,7 +703,7 @@ func BooksG(mods ...qm.QueryMod) bookQuery {
 // Books retrieves all the records using an executor.
 func Books(exec boil.Executor, mods ...qm.QueryMod) bookQuery {
 	mods = append(mods, qm.From("`book`"))
-	return bookQuery{NewQuery(exec, mods...)}
+	return bookQuery{Query: NewQuery(exec, mods...)}
 }
 
 // FindBookG retrieves a single record by ID.
@@ -532,6 +724,11 @@ func FindBookGP(id int64, selectCols ...string) *Book {
 // FindBook retrieves a single record by ID with an executor.
 // If selectCols is empty Find will return all columns.
 func FindBook(exec boil.Executor, id int64, selectCols ...string) (*Book, error) {
+	return FindBookContext(context.Background(), exec, id, selectCols...)
+}
+
+// FindBookContext is FindBook, with the query canceled with ctx.
+func FindBookContext(ctx context.Context, exec boil.Executor, id int64, selectCols ...string) (*Book, error) {
 	bookObj := &Book{}
 	bookObj.readonly = &Book{}
 
@@ -545,7 +742,7 @@ func FindBook(exec boil.Executor, id int64, selectCols ...string) (*Book, error)
 
 	q := queries.Raw(exec, query, id)
 
-	err := q.Bind(bookObj)
+	err := withContext(ctx, q).Bind(bookObj)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, sql.ErrNoRows
@@ -595,6 +792,11 @@ func (o *Book) InsertP(exec boil.Executor, whitelist ...string) {
 // - All columns without a default value are included (i.e. name, age)
 // - All columns with a default, but non-zero are included (i.e. health = 75)
 func (o *Book) Insert(exec boil.Executor, whitelist ...string) error {
+	return o.InsertContext(context.Background(), exec, whitelist...)
+}
+
+// InsertContext is Insert, with its queries canceled with ctx.
+func (o *Book) InsertContext(ctx context.Context, exec boil.Executor, whitelist ...string) error {
 	if o == nil {
 		return errors.New("models: no book provided for insertion")
 	}
@@ -603,7 +805,7 @@ func (o *Book) Insert(exec boil.Executor, whitelist ...string) error {
 
 	var err error
 
-	if err := o.doBeforeInsertHooks(exec); err != nil {
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -646,7 +848,7 @@ func (o *Book) Insert(exec boil.Executor, whitelist ...string) error {
 		fmt.Fprintln(boil.DebugWriter, vals)
 	}
 
-	result, err := exec.Exec(cache.query, vals...)
+	result, err := execContext(ctx, exec, cache.query, vals...)
 
 	if err != nil {
 		return errors.Wrap(err, "models: unable to insert into book")
@@ -678,7 +880,7 @@ func (o *Book) Insert(exec boil.Executor, whitelist ...string) error {
 		fmt.Fprintln(boil.DebugWriter, identifierCols...)
 	}
 
-	err = exec.QueryRow(cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	err = queryRowContext(ctx, exec, cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to populate default values for book")
 	}
@@ -690,7 +892,7 @@ CacheNoHooks:
 		bookInsertCacheMut.Unlock()
 	}
 
-	return o.doAfterInsertHooks(exec)
+	return o.doAfterInsertHooks(ctx, exec)
 }
 
 // UpdateG a single Book record. See Update for
@@ -720,30 +922,57 @@ func (o *Book) UpdateP(exec boil.Executor, whitelist ...string) {
 // Update uses an executor to update the Book.
 // Whitelist behavior: If a whitelist is provided, only the columns given are updated.
 // No whitelist behavior: Without a whitelist, columns are inferred by the following rules:
-// - All columns are inferred to start with
+// - Only the columns changed since the object was selected are updated
+// - An object that was not selected has all columns inferred to start with
 // - All primary keys are subtracted from this set
+// An object that was selected and has no changes is not updated at all.
 // Update does not automatically update the record in case of default values. Use .Reload()
 // to refresh the records.
+//
+// Concurrency: for a selected object, the update only applies while the
+// updated columns still hold the values they were selected with.
+// ErrStaleObject is returned when they do not. With MySQL, connect with
+// clientFoundRows=true so that rows updated to the values they already had
+// are not taken for stale ones.
 func (o *Book) Update(exec boil.Executor, whitelist ...string) error {
+	return o.UpdateContext(context.Background(), exec, whitelist...)
+}
+
+// UpdateContext is Update, with its queries canceled with ctx.
+func (o *Book) UpdateContext(ctx context.Context, exec boil.Executor, whitelist ...string) error {
 	o.whitelist = whitelist
 	whitelist = o.Whitelist()
+	if o.readonly != nil && len(o.whitelist) == 0 && len(whitelist) == 0 {
+		return nil
+	}
 
 	o.operation = "UPDATE"
+
+	var lockCols []string
+	var lockValues []interface{}
+
 	var err error
-	if err = o.doBeforeUpdateHooks(exec); err != nil {
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
 		return err
 	}
+
+	// Inferred columns include the primary key of an object that was not
+	// selected, which is what the row is found by rather than something to set
+	if len(o.whitelist) == 0 {
+		whitelist = strmangle.SetComplement(whitelist, bookPrimaryKeyColumns)
+	}
+
+	wl := strmangle.UpdateColumnSet(bookColumns, bookPrimaryKeyColumns, whitelist)
+	if len(wl) == 0 {
+		return errors.New("models: unable to update book, could not build whitelist")
+	}
+
 	key := makeCacheKey(whitelist, nil)
 	bookUpdateCacheMut.RLock()
 	cache, cached := bookUpdateCache[key]
 	bookUpdateCacheMut.RUnlock()
 
 	if !cached {
-		wl := strmangle.UpdateColumnSet(bookColumns, bookPrimaryKeyColumns, whitelist)
-		if len(wl) == 0 {
-			return errors.New("models: unable to update book, could not build whitelist")
-		}
-
 		cache.query = fmt.Sprintf("UPDATE `book` SET %s WHERE %s",
 			strmangle.SetParamNames("`", "`", 0, wl),
 			strmangle.WhereClause("`", "`", 0, bookPrimaryKeyColumns),
@@ -756,23 +985,54 @@ func (o *Book) Update(exec boil.Executor, whitelist ...string) error {
 
 	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
 
+	if o.readonly != nil {
+		lockCols = wl
+		lockValues = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o.readonly)), cache.valueMapping[:len(wl)])
+	}
+
+	query := cache.query
+	if len(lockCols) != 0 {
+		lock, lockArgs := lockWhere(lockCols, lockValues, len(values)+1)
+		query += " AND " + lock
+		values = append(values, lockArgs...)
+	}
+
 	if boil.DebugMode {
-		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, query)
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	_, err = exec.Exec(cache.query, values...)
+	result, err := execContext(ctx, exec, query, values...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to update book row")
 	}
 
+	if len(lockCols) != 0 {
+		rowsAff, err := result.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "models: failed to get rows affected by update for book")
+		}
+		if rowsAff == 0 {
+			return ErrStaleObject
+		}
+	}
+
 	if !cached {
 		bookUpdateCacheMut.Lock()
 		bookUpdateCache[key] = cache
 		bookUpdateCacheMut.Unlock()
 	}
 
-	return o.doAfterUpdateHooks(exec)
+	if err = o.doAfterUpdateHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	// The row now holds what o does, later updates are checked against it
+	if o.readonly != nil {
+		*o.readonly = *o
+	}
+
+	return nil
 }
 
 // UpdateAllP updates all rows with matching column names, and panics on error.
@@ -783,14 +1043,33 @@ func (q bookQuery) UpdateAllP(cols M) {
 }
 
 // UpdateAll updates all rows with the specified column values.
+// See WithChanges for the Changesets of the rows updated.
 func (q bookQuery) UpdateAll(cols M) error {
+	return q.UpdateAllContext(context.Background(), cols)
+}
+
+// UpdateAllContext is UpdateAll, with its queries canceled with ctx.
+func (q bookQuery) UpdateAllContext(ctx context.Context, cols M) error {
+	rows, err := q.affected(ctx)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to select rows to update all for book")
+	}
+
 	queries.SetUpdate(q.Query, cols)
 
-	_, err := q.Query.Exec()
+	_, err = withContext(ctx, q.Query).Exec()
 	if err != nil {
 		return errors.Wrap(err, "models: unable to update all for book")
 	}
 
+	if len(rows) != 0 {
+		chs := make([]*Changeset, len(rows))
+		for i, obj := range rows {
+			chs[i] = obj.updatedChangeset(cols)
+		}
+		addChanges(ctx, queries.GetExecutor(q.Query), chs...)
+	}
+
 	return nil
 }
 
@@ -814,7 +1093,14 @@ func (o BookSlice) UpdateAllP(exec boil.Executor, cols M) {
 }
 
 // UpdateAll updates all rows with the specified column values, using an executor.
+// A Changeable executor gets a Changeset per row, with the values the rows
+// were selected with as before values.
 func (o BookSlice) UpdateAll(exec boil.Executor, cols M) error {
+	return o.UpdateAllContext(context.Background(), exec, cols)
+}
+
+// UpdateAllContext is UpdateAll, with the query canceled with ctx.
+func (o BookSlice) UpdateAllContext(ctx context.Context, exec boil.Executor, cols M) error {
 	ln := int64(len(o))
 	if ln == 0 {
 		return nil
@@ -851,11 +1137,19 @@ func (o BookSlice) UpdateAll(exec boil.Executor, cols M) error {
 		fmt.Fprintln(boil.DebugWriter, args...)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to update all in book slice")
 	}
 
+	if _, ok := exec.(Changeable); ok {
+		chs := make([]*Changeset, len(o))
+		for i, obj := range o {
+			chs[i] = obj.updatedChangeset(cols)
+		}
+		addChanges(ctx, exec, chs...)
+	}
+
 	return nil
 }
 
@@ -881,13 +1175,18 @@ func (o *Book) UpsertP(exec boil.Executor, updateColumns []string, whitelist ...
 
 // Upsert attempts an insert using an executor, and does an update or ignore on conflict.
 func (o *Book) Upsert(exec boil.Executor, updateColumns []string, whitelist ...string) error {
+	return o.UpsertContext(context.Background(), exec, updateColumns, whitelist...)
+}
+
+// UpsertContext is Upsert, with its queries canceled with ctx.
+func (o *Book) UpsertContext(ctx context.Context, exec boil.Executor, updateColumns []string, whitelist ...string) error {
 	if o == nil {
 		return errors.New("models: no book provided for upsert")
 	}
 	o.whitelist = whitelist
 	o.operation = "UPSERT"
 
-	if err := o.doBeforeUpsertHooks(exec); err != nil {
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -963,7 +1262,7 @@ func (o *Book) Upsert(exec boil.Executor, updateColumns []string, whitelist ...s
 		fmt.Fprintln(boil.DebugWriter, vals)
 	}
 
-	result, err := exec.Exec(cache.query, vals...)
+	result, err := execContext(ctx, exec, cache.query, vals...)
 
 	if err != nil {
 		return errors.Wrap(err, "models: unable to upsert for book")
@@ -995,7 +1294,7 @@ func (o *Book) Upsert(exec boil.Executor, updateColumns []string, whitelist ...s
 		fmt.Fprintln(boil.DebugWriter, identifierCols...)
 	}
 
-	err = exec.QueryRow(cache.retQuery, identifierCols...).Scan(returns...)
+	err = queryRowContext(ctx, exec, cache.retQuery, identifierCols...).Scan(returns...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to populate default values for book")
 	}
@@ -1007,7 +1306,7 @@ CacheNoHooks:
 		bookUpsertCacheMut.Unlock()
 	}
 
-	return o.doAfterUpsertHooks(exec)
+	return o.doAfterUpsertHooks(ctx, exec)
 }
 
 // DeleteP deletes a single Book record with an executor.
@@ -1041,12 +1340,18 @@ func (o *Book) DeleteGP() {
 // Delete deletes a single Book record with an executor.
 // Delete will match against the primary key column to find the record to delete.
 func (o *Book) Delete(exec boil.Executor) error {
+	return o.DeleteContext(context.Background(), exec)
+}
+
+// DeleteContext is Delete, with the query canceled with ctx.
+func (o *Book) DeleteContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil {
 		return errors.New("models: no Book provided for delete")
 	}
 	o.operation = "DELETE"
+	o.whitelist = nil
 
-	if err := o.doBeforeDeleteHooks(exec); err != nil {
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -1058,12 +1363,12 @@ func (o *Book) Delete(exec boil.Executor) error {
 		fmt.Fprintln(boil.DebugWriter, args)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to delete from book")
 	}
 
-	if err := o.doAfterDeleteHooks(exec); err != nil {
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -1078,18 +1383,39 @@ func (q bookQuery) DeleteAllP() {
 }
 
 // DeleteAll deletes all matching rows.
+// See WithChanges for the Changesets of the rows deleted.
 func (q bookQuery) DeleteAll() error {
+	return q.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext is DeleteAll, with its queries canceled with ctx.
+func (q bookQuery) DeleteAllContext(ctx context.Context) error {
 	if q.Query == nil {
 		return errors.New("models: no bookQuery provided for delete all")
 	}
 
+	rows, err := q.affected(ctx)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to select rows to delete all from book")
+	}
+
 	queries.SetDelete(q.Query)
 
-	_, err := q.Query.Exec()
+	_, err = withContext(ctx, q.Query).Exec()
 	if err != nil {
 		return errors.Wrap(err, "models: unable to delete all from book")
 	}
 
+	if len(rows) != 0 {
+		chs := make([]*Changeset, len(rows))
+		for i, obj := range rows {
+			obj.operation = "DELETE"
+			obj.whitelist = nil
+			chs[i], _ = obj.Changes()
+		}
+		addChanges(ctx, queries.GetExecutor(q.Query), chs...)
+	}
+
 	return nil
 }
 
@@ -1117,6 +1443,11 @@ func (o BookSlice) DeleteAllP(exec boil.Executor) {
 
 // DeleteAll deletes all rows in the slice, using an executor.
 func (o BookSlice) DeleteAll(exec boil.Executor) error {
+	return o.DeleteAllContext(context.Background(), exec)
+}
+
+// DeleteAllContext is DeleteAll, with the query canceled with ctx.
+func (o BookSlice) DeleteAllContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil {
 		return errors.New("models: no Book slice provided for delete all")
 	}
@@ -1125,9 +1456,14 @@ func (o BookSlice) DeleteAll(exec boil.Executor) error {
 		return nil
 	}
 
+	for _, obj := range o {
+		obj.operation = "DELETE"
+		obj.whitelist = nil
+	}
+
 	if len(bookBeforeDeleteHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
 				return err
 			}
 		}
@@ -1150,14 +1486,14 @@ func (o BookSlice) DeleteAll(exec boil.Executor) error {
 		fmt.Fprintln(boil.DebugWriter, args)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to delete all from book slice")
 	}
 
 	if len(bookAfterDeleteHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doAfterDeleteHooks(exec); err != nil {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
 				return err
 			}
 		}
@@ -1192,7 +1528,12 @@ func (o *Book) ReloadG() error {
 // Reload refetches the object from the database
 // using the primary keys with an executor.
 func (o *Book) Reload(exec boil.Executor) error {
-	ret, err := FindBook(exec, o.ID)
+	return o.ReloadContext(context.Background(), exec)
+}
+
+// ReloadContext is Reload, with the query canceled with ctx.
+func (o *Book) ReloadContext(ctx context.Context, exec boil.Executor) error {
+	ret, err := FindBookContext(ctx, exec, o.ID)
 	if err != nil {
 		return err
 	}
@@ -1232,6 +1573,11 @@ func (o *BookSlice) ReloadAllG() error {
 // ReloadAll refetches every row with matching primary key column values
 // and overwrites the original object slice with the newly updated slice.
 func (o *BookSlice) ReloadAll(exec boil.Executor) error {
+	return o.ReloadAllContext(context.Background(), exec)
+}
+
+// ReloadAllContext is ReloadAll, with the query canceled with ctx.
+func (o *BookSlice) ReloadAllContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil || len(*o) == 0 {
 		return nil
 	}
@@ -1251,7 +1597,7 @@ func (o *BookSlice) ReloadAll(exec boil.Executor) error {
 
 	q := queries.Raw(exec, sql, args...)
 
-	err := q.Bind(&books)
+	err := withContext(ctx, q).Bind(&books)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to reload all in BookSlice")
 	}
@@ -1263,6 +1609,11 @@ func (o *BookSlice) ReloadAll(exec boil.Executor) error {
 
 // BookExists checks if the Book row exists.
 func BookExists(exec boil.Executor, id int64) (bool, error) {
+	return BookExistsContext(context.Background(), exec, id)
+}
+
+// BookExistsContext is BookExists, with the query canceled with ctx.
+func BookExistsContext(ctx context.Context, exec boil.Executor, id int64) (bool, error) {
 	var exists bool
 
 	sql := "select exists(select 1 from `book` where `id`=? limit 1)"
@@ -1272,7 +1623,7 @@ func BookExists(exec boil.Executor, id int64) (bool, error) {
 		fmt.Fprintln(boil.DebugWriter, id)
 	}
 
-	row := exec.QueryRow(sql, id)
+	row := queryRowContext(ctx, exec, sql, id)
 
 	err := row.Scan(&exists)
 	if err != nil {
@@ -1307,35 +1658,67 @@ func BookExistsP(exec boil.Executor, id int64) bool {
 	return e
 }
 
-// InsertG a single record. See Insert for whitelist behavior description.
+// Changes lists the whitelisted columns of o that differ from the values it
+// was selected with. Inserts have no before values and deletes no after
+// values; an object deleted without being selected has its own values as
+// before values, and one updated without being selected has unknown ones.
+// Columns set sensitive with SetSensitive are masked or left out.
 func (o *Book) Changes() (ch *Changeset, err error) {
-	ch = &Changeset{Table: "book",
-		Changes: []*ChangeItem{}, Operation: o.Operation()}
-	v := reflect.Indirect(reflect.ValueOf(o.readonly))
-	vnew := reflect.Indirect(reflect.ValueOf(o))
+	ch = o.newChangeset(o.Operation())
 
+	ro := o.readonly
+	deleted := o.operation == "DELETE"
 	for _, c := range o.Whitelist() {
-		if f, ok := BookFieldMapping[c]; ok {
-			var before, after interface{}
-			if v.IsValid() {
-				before = v.FieldByName(f).Interface()
-			}
+		sens := sensitivity("book", c)
+		if sens == Exclude {
+			continue
+		}
 
-			if vnew.IsValid() {
-				after = vnew.FieldByName(f).Interface()
+		var chitem *ChangeItem
+		switch c {
+		case "id":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().ID}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.ID, BeforeUnknown: o.operation == "UPDATE"}
+			case o.ID != ro.ID:
+				chitem = &ChangeItem{Name: c, Before: ro.ID, After: o.ID}
 			}
-
-			chitem := &ChangeItem{Name: c}
-			if o.operation == "DELETE" {
-				chitem.Before = before
-			} else {
-				chitem.Before = before
-				chitem.After = after
+		case "name":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().Name}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.Name, BeforeUnknown: o.operation == "UPDATE"}
+			case o.Name.Valid != ro.Name.Valid || (o.Name.Valid && o.Name != ro.Name):
+				chitem = &ChangeItem{Name: c, Before: ro.Name, After: o.Name}
 			}
+		case "author":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().Author}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.Author, BeforeUnknown: o.operation == "UPDATE"}
+			case o.Author.Valid != ro.Author.Valid || (o.Author.Valid && o.Author != ro.Author):
+				chitem = &ChangeItem{Name: c, Before: ro.Author, After: o.Author}
+			}
+		case "shelf_id":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().ShelfID}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.ShelfID, BeforeUnknown: o.operation == "UPDATE"}
+			case o.ShelfID.Valid != ro.ShelfID.Valid || (o.ShelfID.Valid && o.ShelfID != ro.ShelfID):
+				chitem = &ChangeItem{Name: c, Before: ro.ShelfID, After: o.ShelfID}
+			}
+		}
 
-			if !reflect.DeepEqual(chitem.Before, chitem.After) {
-				ch.Changes = append(ch.Changes, chitem)
+		if chitem != nil {
+			if sens == Redact {
+				chitem.redact()
 			}
+			ch.Changes = append(ch.Changes, chitem)
 		}
 	}
 
@@ -1348,27 +1731,105 @@ func (o *Book) Whitelist() (wl []string) {
 		return o.whitelist
 	}
 
-	// Calculates changed columns as whitelist
-	v := reflect.Indirect(reflect.ValueOf(o.readonly))
-	vnew := reflect.Indirect(reflect.ValueOf(o))
+	// Without a selected copy to compare with, and on delete, every column
+	// counts as changed
+	ro := o.readonly
+	if ro == nil || o.operation == "DELETE" {
+		return append(wl, bookColumns...)
+	}
+
+	if o.ID != ro.ID {
+		wl = append(wl, "id")
+	}
+	if o.Name.Valid != ro.Name.Valid || (o.Name.Valid && o.Name != ro.Name) {
+		wl = append(wl, "name")
+	}
+	if o.Author.Valid != ro.Author.Valid || (o.Author.Valid && o.Author != ro.Author) {
+		wl = append(wl, "author")
+	}
+	if o.ShelfID.Valid != ro.ShelfID.Valid || (o.ShelfID.Valid && o.ShelfID != ro.ShelfID) {
+		wl = append(wl, "shelf_id")
+	}
+
+	return
+}
+
+// newChangeset starts a Changeset of op on the row of o, without changes.
+func (o *Book) newChangeset(op string) *Changeset {
+	return &Changeset{Table: "book",
+		PrimaryKey: map[string]interface{}{
+			"id": o.ID,
+		},
+		Changes: []*ChangeItem{}, Operation: op,
+		ChangedAt: time.Now().In(boil.GetLocation())}
+}
+
+// selected returns the values the row of o is known to hold: the ones o was
+// selected with, or o itself when it was not selected.
+func (o *Book) selected() *Book {
+	if o.readonly != nil {
+		return o.readonly
+	}
+
+	return o
+}
 
+// updatedChangeset records a bulk update setting cols on the row of o.
+// Columns set sensitive with SetSensitive are masked or left out.
+func (o *Book) updatedChangeset(cols M) *Changeset {
+	ch := o.newChangeset("UPDATE")
+	ro := o.selected()
 	for _, c := range bookColumns {
-		if f, ok := BookFieldMapping[c]; ok {
-			var before, after interface{}
-			if v.IsValid() {
-				before = v.FieldByName(f).Interface()
-			}
+		v, ok := cols[c]
+		if !ok {
+			continue
+		}
 
-			if vnew.IsValid() {
-				after = vnew.FieldByName(f).Interface()
-			}
-			if !reflect.DeepEqual(before, after) || o.operation == "DELETE" {
-				wl = append(wl, c)
-			}
+		sens := sensitivity("book", c)
+		if sens == Exclude {
+			continue
+		}
+
+		chitem := &ChangeItem{Name: c, After: v}
+		switch c {
+		case "id":
+			chitem.Before = ro.ID
+		case "name":
+			chitem.Before = ro.Name
+		case "author":
+			chitem.Before = ro.Author
+		case "shelf_id":
+			chitem.Before = ro.ShelfID
+		}
+		if sens == Redact {
+			chitem.redact()
 		}
+		ch.Changes = append(ch.Changes, chitem)
 	}
 
-	return
+	return ch
+}
+
+// WithChanges makes UpdateAll and DeleteAll select the rows they affect
+// beforehand, so that a Changeable executor gets a Changeset per row. Run
+// it in a transaction for the rows selected to be the ones changed.
+func (q bookQuery) WithChanges() bookQuery {
+	q.changes = true
+	return q
+}
+
+// affected selects the rows q is about to update or delete, when WithChanges
+// asked for their Changesets and the executor takes them.
+func (q bookQuery) affected(ctx context.Context) (BookSlice, error) {
+	if !q.changes {
+		return nil, nil
+	}
+	if _, ok := queries.GetExecutor(q.Query).(Changeable); !ok {
+		return nil, nil
+	}
+
+	sel := *q.Query
+	return bookQuery{Query: &sel}.AllContext(ctx)
 }
 
 func (o *Book) Operation() string {
@@ -1377,20 +1838,18 @@ func (o *Book) Operation() string {
 
 // Generated change history hook for models
 func init() {
-	chFunc := func(exec boil.Executor, s *Book) error {
+	chFunc := func(ctx context.Context, exec boil.Executor, s *Book) error {
 		if s == nil || exec == nil {
 			return nil
 		}
 
 		ch, _ := s.Changes()
-		if changeable, ok := exec.(Changeable); ok {
-			changeable.AddChange(ch)
-		}
+		addChanges(ctx, exec, ch)
 
 		return nil
 	}
 
-	afterSel := func(exec boil.Executor, s *Book) error {
+	afterSel := func(ctx context.Context, exec boil.Executor, s *Book) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1400,7 +1859,7 @@ func init() {
 		return nil
 	}
 
-	beforeInsert := func(exec boil.Executor, s *Book) error {
+	beforeInsert := func(ctx context.Context, exec boil.Executor, s *Book) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1409,7 +1868,7 @@ func init() {
 		return nil
 	}
 
-	beforeUpdate := func(exec boil.Executor, s *Book) error {
+	beforeUpdate := func(ctx context.Context, exec boil.Executor, s *Book) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1418,7 +1877,7 @@ func init() {
 		return nil
 	}
 
-	beforeUpsert := func(exec boil.Executor, s *Book) error {
+	beforeUpsert := func(ctx context.Context, exec boil.Executor, s *Book) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1427,7 +1886,7 @@ func init() {
 		return nil
 	}
 
-	afterDelete := func(exec boil.Executor, s *Book) error {
+	afterDelete := func(ctx context.Context, exec boil.Executor, s *Book) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1446,3 +1905,225 @@ func init() {
 	AddBookHook(boil.AfterDeleteHook, afterDelete)
 	AddBookHook(boil.AfterDeleteHook, chFunc)
 }
+
+// ApplyBook replays ch, recorded on book: an INSERT inserts the
+// row with its After values, an UPDATE or UPSERT sets its After values and
+// a DELETE deletes it.
+func ApplyBook(exec boil.Executor, ch *Changeset) error {
+	o, cols, err := bookFromChangeset(ch, true)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Operation {
+	case "INSERT":
+		return o.reinsert(exec, cols)
+	case "UPDATE", "UPSERT":
+		return o.restore(exec, cols)
+	case "DELETE":
+		return o.Delete(exec)
+	}
+
+	return errors.Errorf("models: unable to apply %s changes to book", ch.Operation)
+}
+
+// RevertBook undoes ch, recorded on book: an INSERT deletes the
+// row, an UPDATE restores its Before values and a DELETE inserts it again
+// with them. An UPSERT is undone as the insert or update it turned out to be.
+func RevertBook(exec boil.Executor, ch *Changeset) error {
+	o, cols, err := bookFromChangeset(ch, false)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Operation {
+	case "INSERT":
+		return o.Delete(exec)
+	case "UPSERT":
+		if ch.inserted() {
+			return o.Delete(exec)
+		}
+		return o.restore(exec, cols)
+	case "UPDATE":
+		return o.restore(exec, cols)
+	case "DELETE":
+		return o.reinsert(exec, cols)
+	}
+
+	return errors.Errorf("models: unable to revert %s changes to book", ch.Operation)
+}
+
+// bookFromChangeset builds the row ch was recorded on, with its After values
+// or its Before ones, and lists the columns set from them. Redacted values
+// are not known, so their columns are left alone. Before values that were
+// not known when ch was recorded make it fail.
+func bookFromChangeset(ch *Changeset, after bool) (*Book, []string, error) {
+	if ch == nil || ch.Table != "book" {
+		return nil, nil, errors.New("models: changes were not recorded on book")
+	}
+
+	o := &Book{}
+	cols := make([]string, 0, len(ch.Changes))
+	for _, item := range ch.Changes {
+		v := item.Before
+		if after {
+			v = item.After
+		} else if item.BeforeUnknown {
+			return nil, nil, errors.Errorf("models: unable to revert column %q of book, its value before the change is unknown", item.Name)
+		}
+		if v == RedactedValue {
+			continue
+		}
+
+		if err := setColumn(o, BookFieldMapping, item.Name, v); err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, item.Name)
+	}
+
+	// The key the row has now, even where a change was to it
+	for c, v := range ch.PrimaryKey {
+		if err := setColumn(o, BookFieldMapping, c, v); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o, cols, nil
+}
+
+// reinsert inserts o with the columns listed in cols and its primary key.
+func (o *Book) reinsert(exec boil.Executor, cols []string) error {
+	return o.Insert(exec, strmangle.SetMerge(cols, bookPrimaryKeyColumns)...)
+}
+
+// restore updates the columns of o listed in cols, leaving its primary key
+// alone.
+func (o *Book) restore(exec boil.Executor, cols []string) error {
+	cols = strmangle.SetComplement(cols, bookPrimaryKeyColumns)
+	if len(cols) == 0 {
+		return nil
+	}
+
+	return o.Update(exec, cols...)
+}
+
+func init() {
+	changeAppliers["book"] = struct {
+		apply, revert func(boil.Executor, *Changeset) error
+	}{ApplyBook, RevertBook}
+}
+
+var bookSchema = SchemaTable{
+	Name: "book",
+	Columns: []SchemaColumn{
+		{Name: "id", Type: "int64", DBType: "bigint", Nullable: false},
+		{Name: "name", Type: "null.String", DBType: "varchar", Nullable: true},
+		{Name: "author", Type: "null.String", DBType: "varchar", Nullable: true},
+		{Name: "shelf_id", Type: "null.Int64", DBType: "bigint", Nullable: true},
+	},
+	PKey: []string{"id"},
+	FKeys: []SchemaForeignKey{
+		{Name: "book_shelf_id_fk", Column: "shelf_id", ForeignTable: "shelf", ForeignColumn: "id"},
+	},
+}
+
+func init() {
+	SchemaTables["book"] = bookSchema
+}
+
+// SortBy sets the columns the pages of the query are sorted on, a "-" prefix
+// sorting one in descending order. The primary key breaks ties, and sorts
+// the pages on its own without SortBy. Do not combine it with qm.OrderBy.
+func (q bookQuery) SortBy(cols ...string) bookQuery {
+	q.keyset.sort = cols
+	return q
+}
+
+// PageSize sets the number of rows of the pages of the query.
+func (q bookQuery) PageSize(n int) bookQuery {
+	q.keyset.size = n
+	return q
+}
+
+// After makes the query select the page following the one cursor was
+// returned with, as Cursors.Next. An empty cursor selects the first page.
+func (q bookQuery) After(cursor string) bookQuery {
+	q.keyset.cursor = cursor
+	q.keyset.before = false
+	return q
+}
+
+// Before makes the query select the page preceding the one cursor was
+// returned with, as Cursors.Prev. An empty cursor selects the last page.
+func (q bookQuery) Before(cursor string) bookQuery {
+	q.keyset.cursor = cursor
+	q.keyset.before = true
+	return q
+}
+
+// PageP returns the page of Book records the query asks for, and panics on error.
+func (q bookQuery) PageP() (BookSlice, *Cursors) {
+	o, cursors, err := q.Page()
+	if err != nil {
+		panic(boil.WrapErr(err))
+	}
+
+	return o, cursors
+}
+
+// Page returns the page of Book records asked for with SortBy, PageSize,
+// After and Before, with the cursors of the pages around it. Rather than
+// skipping rows with an offset, it selects those sorted past the cursor, so
+// it is as fast on any page and rows changed in between are not skipped.
+// ErrInvalidCursor is returned for a cursor of another sort.
+func (q bookQuery) Page() (BookSlice, *Cursors, error) {
+	return q.PageContext(context.Background())
+}
+
+// PageContext is Page, with its query canceled with ctx.
+func (q bookQuery) PageContext(ctx context.Context) (BookSlice, *Cursors, error) {
+	k := q.keyset
+	cols, err := k.columns(BookFieldMapping, bookPrimaryKeyColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sel := *q.Query
+	if err := k.apply(&sel, &Book{}, BookFieldMapping, cols); err != nil {
+		return nil, nil, err
+	}
+
+	o, err := bookQuery{Query: &sel}.AllContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	more := k.size > 0 && len(o) > k.size
+	if more {
+		o = o[:k.size]
+	}
+	// Rows before the cursor were selected in reverse
+	if k.before {
+		for i, j := 0, len(o)-1; i < j; i, j = i+1, j-1 {
+			o[i], o[j] = o[j], o[i]
+		}
+	}
+
+	cursors := &Cursors{}
+	if len(o) == 0 {
+		return o, cursors, nil
+	}
+
+	if (more && !k.before) || (k.before && k.cursor != "") {
+		if cursors.Next, err = encodeCursor(o[len(o)-1], BookFieldMapping, cols); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (more && k.before) || (!k.before && k.cursor != "") {
+		if cursors.Prev, err = encodeCursor(o[0], BookFieldMapping, cols); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o, cursors, nil
+}
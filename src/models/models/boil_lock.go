@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql/driver"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/strmangle"
+)
+
+// ErrStaleObject occurs during update when the row no longer holds the values
+// the object was selected with, because it was changed or deleted since.
+var ErrStaleObject = errors.New("models: stale object, row was changed or deleted since it was selected")
+
+// lockWhere builds the optimistic locking conditions of an update, checking
+// that each of cols still holds the matching value. NULLs are checked with
+// IS NULL and take no argument; placeholders are numbered from start when
+// the dialect indexes them.
+func lockWhere(cols []string, values []interface{}, start int) (string, []interface{}) {
+	buf := strmangle.GetBuffer()
+	defer strmangle.PutBuffer(buf)
+
+	args := make([]interface{}, 0, len(values))
+	for i, c := range cols {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		buf.WriteByte(dialect.LQ)
+		buf.WriteString(c)
+		buf.WriteByte(dialect.RQ)
+
+		if isNull(values[i]) {
+			buf.WriteString(" IS NULL")
+			continue
+		}
+
+		if dialect.IndexPlaceholders {
+			buf.WriteString("=$")
+			buf.WriteString(strconv.Itoa(start + len(args)))
+		} else {
+			buf.WriteString("=?")
+		}
+		args = append(args, values[i])
+	}
+
+	return buf.String(), args
+}
+
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	valuer, ok := v.(driver.Valuer)
+	if !ok {
+		return false
+	}
+
+	dv, err := valuer.Value()
+	return err == nil && dv == nil
+}
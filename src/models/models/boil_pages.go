@@ -0,0 +1,209 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/queries"
+	"github.com/vattle/sqlboiler/strmangle"
+)
+
+// ErrInvalidCursor is returned by a page query whose After or Before cursor
+// was not made by a page of a query sorted the same way.
+var ErrInvalidCursor = errors.New("models: invalid page cursor")
+
+// nullsFirst tells whether the database sorts NULLs before other values in
+// ascending order, as MySQL and SQL Server do, or after them, as Postgres does.
+const nullsFirst = true
+
+// Cursors are the cursors of the pages on either side of a page, to pass to
+// After and Before. Next is empty on the last page, Prev on the first one.
+type Cursors struct {
+	Next string `json:"next_cursor,omitempty"`
+	Prev string `json:"prev_cursor,omitempty"`
+}
+
+// keyset is the keyset pagination of a query, set with SortBy, PageSize,
+// After and Before.
+type keyset struct {
+	sort   []string
+	size   int
+	cursor string
+	before bool
+}
+
+// keysetColumn is a column pages are sorted on.
+type keysetColumn struct {
+	name string
+	desc bool
+}
+
+// columns returns the columns k sorts on: those of its sort, followed by the
+// primary key columns not in it, which make the order total.
+func (k keyset) columns(fields map[string]string, pkey []string) ([]keysetColumn, error) {
+	cols := make([]keysetColumn, 0, len(k.sort)+len(pkey))
+	seen := make(map[string]bool, len(k.sort)+len(pkey))
+	for _, s := range k.sort {
+		c := keysetColumn{name: strings.TrimPrefix(s, "-"), desc: strings.HasPrefix(s, "-")}
+		if _, ok := fields[c.name]; !ok {
+			return nil, errors.Errorf("models: unknown sort column %q", c.name)
+		}
+		if seen[c.name] {
+			continue
+		}
+		seen[c.name] = true
+		cols = append(cols, c)
+	}
+
+	for _, name := range pkey {
+		if !seen[name] {
+			cols = append(cols, keysetColumn{name: name})
+		}
+	}
+
+	return cols, nil
+}
+
+// apply adds the conditions, order and limit of the page k asks for to q.
+// o is a model of the queried table, the cursor is decoded into.
+func (k keyset) apply(q *queries.Query, o interface{}, fields map[string]string, cols []keysetColumn) error {
+	if k.cursor != "" {
+		values, err := decodeCursor(k.cursor, o, fields, cols)
+		if err != nil {
+			return err
+		}
+
+		where, args := keysetWhere(cols, values, k.before)
+		queries.AppendWhere(q, where, args...)
+	}
+
+	for _, c := range cols {
+		dir := " ASC"
+		if c.desc != k.before {
+			dir = " DESC"
+		}
+		queries.AppendOrderBy(q, strmangle.IdentQuote(dialect.LQ, dialect.RQ, c.name)+dir)
+	}
+
+	// One row more than the page tells whether another one follows
+	if k.size > 0 {
+		queries.SetLimit(q, k.size+1)
+	}
+
+	return nil
+}
+
+// keysetWhere builds the condition selecting the rows sorted after values on
+// cols, or before them. NULLs are ordered the way the database does.
+func keysetWhere(cols []keysetColumn, values []interface{}, before bool) (string, []interface{}) {
+	buf := strmangle.GetBuffer()
+	defer strmangle.PutBuffer(buf)
+
+	var args []interface{}
+	for i, c := range cols {
+		col := strmangle.IdentQuote(dialect.LQ, dialect.RQ, c.name)
+		after, afterArgs := keysetAfter(col, values[i], c.desc != before)
+		if after == "" {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteString(" OR ")
+		}
+		buf.WriteByte('(')
+		for j, prev := range cols[:i] {
+			buf.WriteString(strmangle.IdentQuote(dialect.LQ, dialect.RQ, prev.name))
+			if isNull(values[j]) {
+				buf.WriteString(" IS NULL AND ")
+				continue
+			}
+			buf.WriteString(" = ? AND ")
+			args = append(args, values[j])
+		}
+		buf.WriteString(after)
+		buf.WriteByte(')')
+		args = append(args, afterArgs...)
+	}
+
+	if buf.Len() == 0 {
+		return "1=0", nil
+	}
+
+	return buf.String(), args
+}
+
+// keysetAfter returns the condition on col holding a value sorted after v,
+// in descending order when desc is set. It is empty when no value is.
+func keysetAfter(col string, v interface{}, desc bool) (string, []interface{}) {
+	// Whether the values after v are the greater ones, with NULL as the
+	// smallest value or the greatest one
+	greater := !desc
+	if isNull(v) {
+		if greater == nullsFirst {
+			return col + " IS NOT NULL", nil
+		}
+		return "", nil
+	}
+
+	op := " > ?"
+	if !greater {
+		op = " < ?"
+	}
+	if greater == nullsFirst {
+		return col + op, []interface{}{v}
+	}
+
+	return "(" + col + op + " OR " + col + " IS NULL)", []interface{}{v}
+}
+
+// encodeCursor returns the opaque cursor of the row o, a pointer to a model,
+// holding its values of cols.
+func encodeCursor(o interface{}, fields map[string]string, cols []keysetColumn) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(o))
+	values := make(map[string]interface{}, len(cols))
+	for _, c := range cols {
+		values[c.name] = v.FieldByName(fields[c.name]).Interface()
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, "models: unable to encode page cursor")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor sets the values cursor holds on o, a pointer to a model, and
+// returns them in the order of cols.
+func decodeCursor(cursor string, o interface{}, fields map[string]string, cols []keysetColumn) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var values map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil || len(values) != len(cols) {
+		return nil, ErrInvalidCursor
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(o))
+	args := make([]interface{}, len(cols))
+	for i, c := range cols {
+		value, ok := values[c.name]
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		if err := setColumn(o, fields, c.name, value); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		args[i] = v.FieldByName(fields[c.name]).Interface()
+	}
+
+	return args, nil
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// SchemaTable describes a table as it was when the models were generated.
+type SchemaTable struct {
+	Name    string
+	Columns []SchemaColumn
+	PKey    []string
+	FKeys   []SchemaForeignKey
+}
+
+// SchemaColumn describes a column of a SchemaTable. Type is the Go type of
+// its field, and DBType the type the database reported for it.
+type SchemaColumn struct {
+	Name     string
+	Type     string
+	DBType   string
+	Nullable bool
+}
+
+// SchemaForeignKey describes a foreign key of a SchemaTable.
+type SchemaForeignKey struct {
+	Name          string
+	Column        string
+	ForeignTable  string
+	ForeignColumn string
+}
+
+// SchemaTables holds the SchemaTable of every model, by table name.
+var SchemaTables = map[string]SchemaTable{}
+
+// SchemaVersion fingerprints the schema the models were generated from. It
+// changes whenever a table, column or key of SchemaTables does.
+func SchemaVersion() string {
+	names := make([]string, 0, len(SchemaTables))
+	for name := range SchemaTables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%+v\n", SchemaTables[name])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
@@ -0,0 +1,955 @@
+package models
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/vattle/sqlboiler/boil"
+	"github.com/vattle/sqlboiler/randomize"
+	"github.com/vattle/sqlboiler/strmangle"
+)
+
+import (
+	"context"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pkg/errors"
+	"gopkg.in/nullbio/null.v6"
+)
+
+// Every test file is given the same imports, which those of a table may not use
+var (
+	_ = bytes.MinRead
+	_ = boil.DebugMode
+	_ = strmangle.Placeholders
+)
+
+var bookDBTypes = map[string]string{`Author`: `varchar`, `ID`: `bigint`, `Name`: `varchar`, `ShelfID`: `bigint`}
+
+// randomBook returns a Book holding random, non-null values,
+// as if it was built to be inserted.
+func randomBook(t testing.TB) *Book {
+	var cols struct {
+		ID      int64
+		Name    null.String
+		Author  null.String
+		ShelfID null.Int64
+	}
+	if err := randomize.Struct(seed, &cols, bookDBTypes, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Book{
+		ID:      cols.ID,
+		Name:    cols.Name,
+		Author:  cols.Author,
+		ShelfID: cols.ShelfID,
+	}
+}
+
+// selectedBook returns a random Book as if it was selected.
+func selectedBook(t testing.TB) *Book {
+	o := randomBook(t)
+	o.readonly = &Book{}
+	*o.readonly = *o
+
+	return o
+}
+
+// checkBook fails t unless the columns of got hold the values of want.
+func checkBook(t *testing.T, got, want *Book) {
+	if !reflect.DeepEqual(got.ID, want.ID) {
+		t.Errorf("id: want %v, got %v", want.ID, got.ID)
+	}
+	if !reflect.DeepEqual(got.Name, want.Name) {
+		t.Errorf("name: want %v, got %v", want.Name, got.Name)
+	}
+	if !reflect.DeepEqual(got.Author, want.Author) {
+		t.Errorf("author: want %v, got %v", want.Author, got.Author)
+	}
+	if !reflect.DeepEqual(got.ShelfID, want.ShelfID) {
+		t.Errorf("shelf_id: want %v, got %v", want.ShelfID, got.ShelfID)
+	}
+}
+
+// bookRow returns the row of o, for sqlmock to return.
+func bookRow(t *testing.T, o *Book) *sqlmock.Rows {
+	return sqlmock.NewRows(bookColumns).AddRow(mockRow(t, o.ID, o.Name, o.Author, o.ShelfID)...)
+}
+
+func TestBookInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	mock.ExpectExec(exact("INSERT INTO `book` (`id`,`name`,`author`,`shelf_id`) VALUES (?,?,?,?)")).
+		WithArgs(o.ID, o.Name, o.Author, o.ShelfID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Insert(db, bookColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookInsertLastInsertID(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	o.ID = 0
+	mock.ExpectExec(exact("INSERT INTO `book` (`name`,`author`,`shelf_id`) VALUES (?,?,?)")).
+		WithArgs(o.Name, o.Author, o.ShelfID).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	if err := o.Insert(db); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != 42 {
+		t.Errorf("want id 42 from the last insert ID, got %v", o.ID)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	mock.ExpectExec(exact("UPDATE `book` SET `name`=?,`author`=?,`shelf_id`=? WHERE `id`=?")).
+		WithArgs(o.Name, o.Author, o.ShelfID, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookUpdateSelected(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+
+	mock.ExpectExec(exact("UPDATE `book` SET `name`=? WHERE `id`=? AND `name`=?")).
+		WithArgs(o.Name, o.ID, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "book", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "name", Before: before, After: o.Name},
+	})
+
+	// The row now holds the new values, so o is unchanged again
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestBookUpdateStale(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := selectedBook(t)
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+
+	mock.ExpectExec(exact("UPDATE `book` SET `name`=? WHERE `id`=? AND `name`=?")).
+		WithArgs(o.Name, o.ID, before).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := o.Update(db); err != ErrStaleObject {
+		t.Errorf("want ErrStaleObject, got %v", err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookUpsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	mock.ExpectExec(exact("INSERT INTO book (`id`, `name`, `author`, `shelf_id`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`),`author` = VALUES(`author`),`shelf_id` = VALUES(`shelf_id`)")).
+		WithArgs(o.ID, o.Name, o.Author, o.ShelfID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Upsert(db, nil, bookColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	mock.ExpectExec(exact("DELETE FROM `book` WHERE `id`=?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestFindBook(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	mock.ExpectQuery(exact("select * from `book` where `id`=?")).
+		WithArgs(o.ID).
+		WillReturnRows(bookRow(t, o))
+
+	found, err := FindBook(db, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkBook(t, found, o)
+	if len(found.Whitelist()) != 0 {
+		t.Errorf("want a found book unchanged, got changes to %v", found.Whitelist())
+	}
+}
+
+func TestFindBookCanceled(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	o := randomBook(t)
+	if _, err := FindBookContext(ctx, db, o.ID); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBooksCanceled(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Books(db).AllContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+	// An executor that takes no context is still checked before the query
+	rec := &changeRecorder{Executor: db}
+	if err := Books(rec).DeleteAllContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookExists(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	mock.ExpectQuery(exact("select exists(select 1 from `book` where `id`=? limit 1)")).
+		WithArgs(o.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := BookExists(db, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("want the book to exist")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookReload(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	row := randomBook(t)
+	row.ID = o.ID
+	mock.ExpectQuery(exact("select * from `book` where `id`=?")).
+		WithArgs(o.ID).
+		WillReturnRows(bookRow(t, row))
+
+	if err := o.Reload(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkBook(t, o, row)
+}
+
+func TestBookSetShelf(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	related := randomShelf(t)
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=?")).
+		WithArgs(related.ID, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.SetShelf(db, false, related); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if o.R.Shelf != related {
+		t.Error("want o.R.Shelf set to related")
+	}
+}
+
+func TestBookSetShelfThenUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := selectedBook(t)
+	related := randomShelf(t)
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=?")).
+		WithArgs(related.ID, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.SetShelf(db, false, related); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new shelf_id is the row's, so it is neither updated nor checked
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+	mock.ExpectExec(exact("UPDATE `book` SET `name`=? WHERE `id`=? AND `name`=?")).
+		WithArgs(o.Name, o.ID, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookRemoveShelf(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	related := randomShelf(t)
+	o.R = &bookR{Shelf: related}
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=?")).
+		WithArgs(nil, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.RemoveShelf(db, related); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if o.R.Shelf != nil {
+		t.Error("want o.R.Shelf cleared")
+	}
+}
+
+func TestBookWhitelist(t *testing.T) {
+	o := randomBook(t)
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, bookColumns) {
+		t.Errorf("want every column of an unselected book, got %v", wl)
+	}
+
+	o = selectedBook(t)
+	if wl := o.Whitelist(); len(wl) != 0 {
+		t.Errorf("want no column of an unchanged book, got %v", wl)
+	}
+
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, []string{"name"}) {
+		t.Errorf("want [name], got %v", wl)
+	}
+}
+
+func TestBookChangesInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomBook(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Insert(rec, bookColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "book", "INSERT", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "id", After: o.ID},
+		{Name: "name", After: o.Name},
+		{Name: "author", After: o.Author},
+		{Name: "shelf_id", After: o.ShelfID},
+	})
+}
+
+func TestBookChangesIdentified(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomBook(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The context of the write names the actor, though rec carries none
+	ctx := WithRequestID(WithActor(context.Background(), "alice"), "req-1")
+	if err := o.InsertContext(ctx, rec, bookColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(rec.changes) != 1 {
+		t.Fatalf("want 1 changeset, got %d", len(rec.changes))
+	}
+	if ch := rec.changes[0]; ch.Actor != "alice" || ch.RequestID != "req-1" {
+		t.Errorf("want changeset by alice in req-1, got by %q in %q", ch.Actor, ch.RequestID)
+	}
+}
+
+func TestBookChangesDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	o.Name = randomBook(t).Name
+	mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// A deleted row is recorded with the values it was selected with
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "book", "DELETE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "id", Before: o.readonly.ID},
+		{Name: "name", Before: o.readonly.Name},
+		{Name: "author", Before: o.readonly.Author},
+		{Name: "shelf_id", Before: o.readonly.ShelfID},
+	})
+}
+
+func TestBookChangesRedacted(t *testing.T) {
+	SetSensitive("book", "name", Redact)
+	defer SetSensitive("book", "name", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+
+	// A redacted column is still seen to change, with its values masked
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, []string{"name"}) {
+		t.Errorf("want [name], got %v", wl)
+	}
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "book", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "name", Before: RedactedValue, After: RedactedValue},
+	})
+}
+
+func TestBookChangesRedactedNull(t *testing.T) {
+	SetSensitive("book", "name", Redact)
+	defer SetSensitive("book", "name", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	for !o.Name.Valid {
+		o = selectedBook(t)
+	}
+	before := o.Name
+	o.Name.Valid = false
+
+	// Setting a redacted column to NULL reads as such, NULL is no secret
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "book", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "name", Before: RedactedValue, After: o.Name},
+	})
+
+	// and reverting it leaves the masked value alone
+	if _, cols, err := bookFromChangeset(rec.changes[0], true); err != nil || len(cols) != 1 {
+		t.Errorf("want the NULL of name applied, got %v, %v", cols, err)
+	}
+	if _, cols, err := bookFromChangeset(rec.changes[0], false); err != nil || len(cols) != 0 {
+		t.Errorf("want %v left alone, got %v, %v", before, cols, err)
+	}
+}
+
+func TestBookChangesExcluded(t *testing.T) {
+	SetSensitive("book", "name", Exclude)
+	defer SetSensitive("book", "name", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+
+	// An excluded column is still written, and left out of the changeset
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "book", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{})
+}
+
+func TestBooksChangesDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	a, b := randomBook(t), randomBook(t)
+	rows := bookRow(t, a)
+	rows.AddRow(mockRow(t, b.ID, b.Name, b.Author, b.ShelfID)...)
+	mock.ExpectQuery("^SELECT").WillReturnRows(rows)
+	mock.ExpectExec("^DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := Books(rec).WithChanges().DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every row deleted is recorded with the values it was selected with
+	expectationsMet(t, mock)
+	if len(rec.changes) != 2 {
+		t.Fatalf("want 2 changesets, got %d", len(rec.changes))
+	}
+	for i, o := range []*Book{a, b} {
+		pk := map[string]interface{}{
+			"id": o.ID,
+		}
+		checkChangeset(t, rec.changes[i], "book", "DELETE", pk, []*ChangeItem{
+			{Name: "id", Before: o.ID},
+			{Name: "name", Before: o.Name},
+			{Name: "author", Before: o.Author},
+			{Name: "shelf_id", Before: o.ShelfID},
+		})
+	}
+
+	// Without WithChanges no row is selected, nor recorded
+	rec.changes = nil
+	mock.ExpectExec("^DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := Books(rec).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(rec.changes) != 0 {
+		t.Errorf("want no changeset, got %d", len(rec.changes))
+	}
+}
+
+func TestBooksChangesUpdateAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	a, b := randomBook(t), randomBook(t)
+	after := randomBook(t).Name
+	rows := bookRow(t, a)
+	rows.AddRow(mockRow(t, b.ID, b.Name, b.Author, b.ShelfID)...)
+	mock.ExpectQuery("^SELECT").WillReturnRows(rows)
+	mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := Books(rec).WithChanges().UpdateAll(M{"name": after}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every row updated is recorded, from the value it was selected with
+	expectationsMet(t, mock)
+	if len(rec.changes) != 2 {
+		t.Fatalf("want 2 changesets, got %d", len(rec.changes))
+	}
+	for i, o := range []*Book{a, b} {
+		checkChangeset(t, rec.changes[i], "book", "UPDATE", map[string]interface{}{
+			"id": o.ID,
+		}, []*ChangeItem{
+			{Name: "name", Before: o.Name, After: after},
+		})
+	}
+}
+
+func TestBooksWhere(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	count := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"count"}).AddRow(1)
+	}
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `book` WHERE (`id` = ?);")).
+		WithArgs(o.ID).
+		WillReturnRows(count())
+	if _, err := Books(db, BookWhere.ID.EQ(o.ID)).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	// IN clauses are built after the other where clauses
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `book` WHERE `id` IN (?,?);")).
+		WithArgs(o.ID, o.ID).
+		WillReturnRows(count())
+	if _, err := Books(db, BookWhere.ID.IN(o.ID, o.ID)).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.Name.Valid = false
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `book` WHERE (`name` IS NULL);")).
+		WillReturnRows(count())
+	if _, err := Books(db, BookWhere.Name.EQ(o.Name)).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBooksPage(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	rows := bookRow(t, o)
+	rows.AddRow(mockRow(t, o.ID, o.Name, o.Author, o.ShelfID)...)
+
+	// A row more than the page tells there is a next one
+	mock.ExpectQuery(exact("SELECT * FROM `book` ORDER BY `id` ASC LIMIT 2;")).
+		WillReturnRows(rows)
+	page, cursors, err := Books(db).PageSize(1).Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || cursors.Next == "" || cursors.Prev != "" {
+		t.Fatalf("want the first of several pages, got %d rows and cursors %+v", len(page), cursors)
+	}
+
+	mock.ExpectQuery(exact("SELECT * FROM `book` WHERE ((`id` > ?)) ORDER BY `id` ASC LIMIT 2;")).
+		WithArgs(o.ID).
+		WillReturnRows(bookRow(t, o))
+	page, cursors, err = Books(db).PageSize(1).After(cursors.Next).Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || cursors.Next != "" || cursors.Prev == "" {
+		t.Fatalf("want the last of several pages, got %d rows and cursors %+v", len(page), cursors)
+	}
+
+	if _, _, err := Books(db).After("not a cursor").Page(); errors.Cause(err) != ErrInvalidCursor {
+		t.Errorf("want %v, got %v", ErrInvalidCursor, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+// reflectBookWhitelist and reflectBookChanges are the reflection based
+// versions of Whitelist and Changes that the generated comparisons replaced,
+// kept to benchmark against.
+func reflectBookWhitelist(o *Book) (wl []string) {
+	if len(o.whitelist) > 0 {
+		return o.whitelist
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(o.readonly))
+	vnew := reflect.Indirect(reflect.ValueOf(o))
+
+	for _, c := range bookColumns {
+		if f, ok := BookFieldMapping[c]; ok {
+			var before, after interface{}
+			if v.IsValid() {
+				before = v.FieldByName(f).Interface()
+			}
+
+			if vnew.IsValid() {
+				after = vnew.FieldByName(f).Interface()
+			}
+			if !reflect.DeepEqual(before, after) || o.operation == "DELETE" {
+				wl = append(wl, c)
+			}
+		}
+	}
+
+	return
+}
+
+func reflectBookChanges(o *Book) *Changeset {
+	ch := &Changeset{Table: "book", Changes: []*ChangeItem{}, Operation: o.Operation()}
+	v := reflect.Indirect(reflect.ValueOf(o.readonly))
+	vnew := reflect.Indirect(reflect.ValueOf(o))
+
+	for _, c := range reflectBookWhitelist(o) {
+		if f, ok := BookFieldMapping[c]; ok {
+			var before, after interface{}
+			if v.IsValid() {
+				before = v.FieldByName(f).Interface()
+			}
+
+			if vnew.IsValid() {
+				after = vnew.FieldByName(f).Interface()
+			}
+
+			chitem := &ChangeItem{Name: c, Before: before}
+			if o.operation != "DELETE" {
+				chitem.After = after
+			}
+
+			if !reflect.DeepEqual(chitem.Before, chitem.After) {
+				ch.Changes = append(ch.Changes, chitem)
+			}
+		}
+	}
+
+	return ch
+}
+
+// modifiedBook returns a selected Book with its name changed.
+func modifiedBook(b *testing.B) *Book {
+	o := selectedBook(b)
+	o.operation = "UPDATE"
+	o.Name = randomBook(b).Name
+
+	return o
+}
+
+func BenchmarkBookWhitelist(b *testing.B) {
+	o := modifiedBook(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Whitelist()
+	}
+}
+
+func BenchmarkBookWhitelistReflect(b *testing.B) {
+	o := modifiedBook(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reflectBookWhitelist(o)
+	}
+}
+
+func BenchmarkBookChanges(b *testing.B) {
+	o := modifiedBook(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Changes()
+	}
+}
+
+func BenchmarkBookChangesReflect(b *testing.B) {
+	o := modifiedBook(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reflectBookChanges(o)
+	}
+}
+
+func TestBookApplyInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomBook(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Insert(rec, bookColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying the insert inserts the row again, undoing it deletes the row
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(exact("DELETE FROM `book` WHERE `id`=?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Apply(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := RevertBook(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookRevertDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookRevertUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedBook(t)
+	before := o.Name
+	for reflect.DeepEqual(o.Name, before) {
+		o.Name = randomBook(t).Name
+	}
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undoing the update sets the column back to the value it was selected with
+	mock.ExpectExec(exact("UPDATE `book` SET `name`=? WHERE `id`=?")).
+		WithArgs(before, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookRevertUpdateUnknown(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	// An object that was not selected does not know the values it replaced
+	o := randomBook(t)
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := rec.changes[0]
+	if len(ch.Changes) != 1 || !ch.Changes[0].BeforeUnknown {
+		t.Fatalf("want the before value of name unknown, got %+v", ch.Changes)
+	}
+	if err := Revert(db, ch); err == nil {
+		t.Error("want an error reverting an update with unknown before values")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookRevertUpsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomBook(t)
+	before := randomBook(t)
+	pk := map[string]interface{}{
+		"id": o.ID,
+	}
+
+	// Without before values the upsert inserted the row, and is undone by
+	// deleting it
+	inserted := &Changeset{Table: "book", PrimaryKey: pk, Operation: "UPSERT", Changes: []*ChangeItem{
+		{Name: "name", After: o.Name},
+	}}
+	mock.ExpectExec(exact("DELETE FROM `book` WHERE `id`=?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, inserted); err != nil {
+		t.Fatal(err)
+	}
+
+	// With them it updated the row, and is undone by restoring them
+	updated := &Changeset{Table: "book", PrimaryKey: pk, Operation: "UPSERT", Changes: []*ChangeItem{
+		{Name: "name", Before: before.Name, After: o.Name},
+	}}
+	mock.ExpectExec(exact("UPDATE `book` SET `name`=? WHERE `id`=?")).
+		WithArgs(before.Name, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, updated); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestBookRevertOtherTable(t *testing.T) {
+	if err := RevertBook(nil, &Changeset{Table: "not_book"}); err == nil {
+		t.Error("want an error reverting changes of another table")
+	}
+}
@@ -17,10 +17,13 @@ import (
 	"gopkg.in/nullbio/null.v6"
 )
 
+import "context"
+
 // Shelf is an object representing the database table.
 type Shelf struct {
-	ID   int64       `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Area null.String `boil:"area" json:"area,omitempty" toml:"area" yaml:"area,omitempty"`
+	ID        int64       `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Area      null.String `boil:"area" json:"area,omitempty" toml:"area" yaml:"area,omitempty"`
+	DeletedAt null.Time   `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
 
 	R         *shelfR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L         shelfL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -30,8 +33,78 @@ type Shelf struct {
 }
 
 var ShelfFieldMapping = map[string]string{
-	"id":   "ID",
-	"area": "Area",
+	"id":         "ID",
+	"area":       "Area",
+	"deleted_at": "DeletedAt",
+}
+
+// ShelfColumns holds the name of every column of shelf.
+var ShelfColumns = struct {
+	ID        string
+	Area      string
+	DeletedAt string
+}{
+	ID:        "id",
+	Area:      "area",
+	DeletedAt: "deleted_at",
+}
+
+// whereHelperNullTime builds where clauses on a null.Time column.
+type whereHelperNullTime struct{ field string }
+
+// EQ matches x, or NULL when x is null.
+func (w whereHelperNullTime) EQ(x null.Time) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNull()
+	}
+	return qm.Where(w.field+" = ?", x)
+}
+
+// NEQ matches values other than x, or not NULL when x is null.
+func (w whereHelperNullTime) NEQ(x null.Time) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNotNull()
+	}
+	return qm.Where(w.field+" <> ?", x)
+}
+
+func (w whereHelperNullTime) IsNull() qm.QueryMod         { return qm.Where(w.field + " IS NULL") }
+func (w whereHelperNullTime) IsNotNull() qm.QueryMod      { return qm.Where(w.field + " IS NOT NULL") }
+func (w whereHelperNullTime) LT(x null.Time) qm.QueryMod  { return qm.Where(w.field+" < ?", x) }
+func (w whereHelperNullTime) LTE(x null.Time) qm.QueryMod { return qm.Where(w.field+" <= ?", x) }
+func (w whereHelperNullTime) GT(x null.Time) qm.QueryMod  { return qm.Where(w.field+" > ?", x) }
+func (w whereHelperNullTime) GTE(x null.Time) qm.QueryMod { return qm.Where(w.field+" >= ?", x) }
+
+// IN matches any of slice, which must not be empty.
+func (w whereHelperNullTime) IN(slice ...null.Time) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" IN ?", values...)
+}
+
+// NIN matches none of slice, which must not be empty.
+func (w whereHelperNullTime) NIN(slice ...null.Time) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" NOT IN ?", values...)
+}
+
+// ShelfWhere holds a where clause helper for every column of
+// shelf, typed to the column, e.g. ShelfWhere.ID.EQ(x).
+// The column names are not qualified with the table name, so that the
+// helpers also apply to relationship queries, which alias the table.
+var ShelfWhere = struct {
+	ID        whereHelperInt64
+	Area      whereHelperNullString
+	DeletedAt whereHelperNullTime
+}{
+	ID:        whereHelperInt64{field: "`id`"},
+	Area:      whereHelperNullString{field: "`area`"},
+	DeletedAt: whereHelperNullTime{field: "`deleted_at`"},
 }
 
 // shelfR is where relationships are stored.
@@ -43,8 +116,8 @@ type shelfR struct {
 type shelfL struct{}
 
 var (
-	shelfColumns               = []string{"id", "area"}
-	shelfColumnsWithoutDefault = []string{"area"}
+	shelfColumns               = []string{"id", "area", "deleted_at"}
+	shelfColumnsWithoutDefault = []string{"area", "deleted_at"}
 	shelfColumnsWithDefault    = []string{"id"}
 	shelfPrimaryKeyColumns     = []string{"id"}
 )
@@ -54,10 +127,17 @@ type (
 	// This should generally be used opposed to []Shelf.
 	ShelfSlice []*Shelf
 	// ShelfHook is the signature for custom Shelf hook methods
-	ShelfHook func(boil.Executor, *Shelf) error
+	ShelfHook func(context.Context, boil.Executor, *Shelf) error
 
 	shelfQuery struct {
 		*queries.Query
+
+		// changes is set by WithChanges
+		changes bool
+		// keyset is the page asked for with SortBy, PageSize, After and Before
+		keyset keyset
+		// deleted is which soft-deleted rows the query sees
+		deleted softDeleted
 	}
 )
 
@@ -92,9 +172,9 @@ var shelfAfterDeleteHooks []ShelfHook
 var shelfAfterUpsertHooks []ShelfHook
 
 // doBeforeInsertHooks executes all "before insert" hooks.
-func (o *Shelf) doBeforeInsertHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doBeforeInsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfBeforeInsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -103,9 +183,9 @@ func (o *Shelf) doBeforeInsertHooks(exec boil.Executor) (err error) {
 }
 
 // doBeforeUpdateHooks executes all "before Update" hooks.
-func (o *Shelf) doBeforeUpdateHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doBeforeUpdateHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfBeforeUpdateHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -114,9 +194,9 @@ func (o *Shelf) doBeforeUpdateHooks(exec boil.Executor) (err error) {
 }
 
 // doBeforeDeleteHooks executes all "before Delete" hooks.
-func (o *Shelf) doBeforeDeleteHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doBeforeDeleteHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfBeforeDeleteHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -125,9 +205,9 @@ func (o *Shelf) doBeforeDeleteHooks(exec boil.Executor) (err error) {
 }
 
 // doBeforeUpsertHooks executes all "before Upsert" hooks.
-func (o *Shelf) doBeforeUpsertHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doBeforeUpsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfBeforeUpsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -136,9 +216,9 @@ func (o *Shelf) doBeforeUpsertHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterInsertHooks executes all "after Insert" hooks.
-func (o *Shelf) doAfterInsertHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doAfterInsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfAfterInsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -147,9 +227,9 @@ func (o *Shelf) doAfterInsertHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterSelectHooks executes all "after Select" hooks.
-func (o *Shelf) doAfterSelectHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doAfterSelectHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfAfterSelectHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -158,9 +238,9 @@ func (o *Shelf) doAfterSelectHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterUpdateHooks executes all "after Update" hooks.
-func (o *Shelf) doAfterUpdateHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doAfterUpdateHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfAfterUpdateHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -169,9 +249,9 @@ func (o *Shelf) doAfterUpdateHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterDeleteHooks executes all "after Delete" hooks.
-func (o *Shelf) doAfterDeleteHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doAfterDeleteHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfAfterDeleteHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -180,9 +260,9 @@ func (o *Shelf) doAfterDeleteHooks(exec boil.Executor) (err error) {
 }
 
 // doAfterUpsertHooks executes all "after Upsert" hooks.
-func (o *Shelf) doAfterUpsertHooks(exec boil.Executor) (err error) {
+func (o *Shelf) doAfterUpsertHooks(ctx context.Context, exec boil.Executor) (err error) {
 	for _, hook := range shelfAfterUpsertHooks {
-		if err := hook(exec, o); err != nil {
+		if err := hook(ctx, exec, o); err != nil {
 			return err
 		}
 	}
@@ -226,11 +306,16 @@ func (q shelfQuery) OneP() *Shelf {
 
 // One returns a single shelf record from the query.
 func (q shelfQuery) One() (*Shelf, error) {
+	return q.OneContext(context.Background())
+}
+
+// OneContext returns a single shelf record from the query, canceled with ctx.
+func (q shelfQuery) OneContext(ctx context.Context) (*Shelf, error) {
 	o := &Shelf{}
 
 	queries.SetLimit(q.Query, 1)
 
-	err := q.Bind(o)
+	err := withContext(ctx, q.Query).Bind(o)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, sql.ErrNoRows
@@ -238,7 +323,7 @@ func (q shelfQuery) One() (*Shelf, error) {
 		return nil, errors.Wrap(err, "models: failed to execute a one query for shelf")
 	}
 
-	if err := o.doAfterSelectHooks(queries.GetExecutor(q.Query)); err != nil {
+	if err := o.doAfterSelectHooks(ctx, queries.GetExecutor(q.Query)); err != nil {
 		return o, err
 	}
 
@@ -257,16 +342,21 @@ func (q shelfQuery) AllP() ShelfSlice {
 
 // All returns all Shelf records from the query.
 func (q shelfQuery) All() (ShelfSlice, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all Shelf records from the query, canceled with ctx.
+func (q shelfQuery) AllContext(ctx context.Context) (ShelfSlice, error) {
 	var o ShelfSlice
 
-	err := q.Bind(&o)
+	err := withContext(ctx, q.Query).Bind(&o)
 	if err != nil {
 		return nil, errors.Wrap(err, "models: failed to assign all query results to Shelf slice")
 	}
 
 	if len(shelfAfterSelectHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doAfterSelectHooks(queries.GetExecutor(q.Query)); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, queries.GetExecutor(q.Query)); err != nil {
 				return o, err
 			}
 		}
@@ -287,12 +377,17 @@ func (q shelfQuery) CountP() int64 {
 
 // Count returns the count of all Shelf records in the query.
 func (q shelfQuery) Count() (int64, error) {
+	return q.CountContext(context.Background())
+}
+
+// CountContext returns the count of all Shelf records in the query, canceled with ctx.
+func (q shelfQuery) CountContext(ctx context.Context) (int64, error) {
 	var count int64
 
 	queries.SetSelect(q.Query, nil)
 	queries.SetCount(q.Query)
 
-	err := q.Query.QueryRow().Scan(&count)
+	err := withContext(ctx, q.Query).QueryRow().Scan(&count)
 	if err != nil {
 		return 0, errors.Wrap(err, "models: failed to count shelf rows")
 	}
@@ -312,12 +407,17 @@ func (q shelfQuery) ExistsP() bool {
 
 // Exists checks if the row exists in the table.
 func (q shelfQuery) Exists() (bool, error) {
+	return q.ExistsContext(context.Background())
+}
+
+// ExistsContext checks if the row exists in the table, canceled with ctx.
+func (q shelfQuery) ExistsContext(ctx context.Context) (bool, error) {
 	var count int64
 
 	queries.SetCount(q.Query)
 	queries.SetLimit(q.Query, 1)
 
-	err := q.Query.QueryRow().Scan(&count)
+	err := withContext(ctx, q.Query).QueryRow().Scan(&count)
 	if err != nil {
 		return false, errors.Wrap(err, "models: failed to check if shelf exists")
 	}
@@ -350,8 +450,14 @@ func (o *Shelf) Books(exec boil.Executor, mods ...qm.QueryMod) bookQuery {
 }
 
 // LoadBooks allows an eager lookup of values, cached into the
-// loaded structs of the objects.
+// loaded structs of the objects. The lookup is canceled with the context of
+// e, when it carries one.
 func (shelfL) LoadBooks(e boil.Executor, singular bool, maybeShelf interface{}) error {
+	return shelfL{}.LoadBooksContext(contextOf(e), e, singular, maybeShelf)
+}
+
+// LoadBooksContext is LoadBooks, with the lookup canceled with ctx.
+func (shelfL) LoadBooksContext(ctx context.Context, e boil.Executor, singular bool, maybeShelf interface{}) error {
 	var slice []*Shelf
 	var object *Shelf
 
@@ -386,7 +492,7 @@ func (shelfL) LoadBooks(e boil.Executor, singular bool, maybeShelf interface{})
 		fmt.Fprintf(boil.DebugWriter, "%s\n%v\n", query, args)
 	}
 
-	results, err := e.Query(query, args...)
+	results, err := queryContext(ctx, e, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to eager load book")
 	}
@@ -399,7 +505,7 @@ func (shelfL) LoadBooks(e boil.Executor, singular bool, maybeShelf interface{})
 
 	if len(bookAfterSelectHooks) != 0 {
 		for _, obj := range resultSlice {
-			if err := obj.doAfterSelectHooks(e); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
 				return err
 			}
 		}
@@ -426,16 +532,21 @@ func (shelfL) LoadBooks(e boil.Executor, singular bool, maybeShelf interface{})
 // Appends related to o.R.Books.
 // Sets related.R.Shelf appropriately.
 func (o *Shelf) AddBooks(exec boil.Executor, insert bool, related ...*Book) error {
+	return o.AddBooksContext(context.Background(), exec, insert, related...)
+}
+
+// AddBooksContext is AddBooks, with its queries canceled with ctx.
+func (o *Shelf) AddBooksContext(ctx context.Context, exec boil.Executor, insert bool, related ...*Book) error {
 	var err error
 	for _, rel := range related {
 		rel.ShelfID.Int64 = o.ID
 		rel.ShelfID.Valid = true
 		if insert {
-			if err = rel.Insert(exec); err != nil {
+			if err = rel.InsertContext(ctx, exec); err != nil {
 				return errors.Wrap(err, "failed to insert into foreign table")
 			}
 		} else {
-			if err = rel.Update(exec, "shelf_id"); err != nil {
+			if err = rel.UpdateContext(ctx, exec, "shelf_id"); err != nil {
 				return errors.Wrap(err, "failed to update foreign table")
 			}
 		}
@@ -468,6 +579,11 @@ func (o *Shelf) AddBooks(exec boil.Executor, insert bool, related ...*Book) erro
 // Replaces o.R.Books with related.
 // Sets related.R.Shelf's Books accordingly.
 func (o *Shelf) SetBooks(exec boil.Executor, insert bool, related ...*Book) error {
+	return o.SetBooksContext(context.Background(), exec, insert, related...)
+}
+
+// SetBooksContext is SetBooks, with its queries canceled with ctx.
+func (o *Shelf) SetBooksContext(ctx context.Context, exec boil.Executor, insert bool, related ...*Book) error {
 	query := "update `book` set `shelf_id` = null where `shelf_id` = ?"
 	values := []interface{}{o.ID}
 	if boil.DebugMode {
@@ -475,14 +591,16 @@ func (o *Shelf) SetBooks(exec boil.Executor, insert bool, related ...*Book) erro
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	_, err := exec.Exec(query, values...)
+	_, err := execContext(ctx, exec, query, values...)
 	if err != nil {
 		return errors.Wrap(err, "failed to remove relationships before set")
 	}
 
+	cleared := append([]*Book{}, related...)
 	if o.R != nil {
 		for _, rel := range o.R.Books {
 			rel.ShelfID.Valid = false
+			cleared = append(cleared, rel)
 			if rel.R == nil {
 				continue
 			}
@@ -492,20 +610,37 @@ func (o *Shelf) SetBooks(exec boil.Executor, insert bool, related ...*Book) erro
 
 		o.R.Books = nil
 	}
-	return o.AddBooks(exec, insert, related...)
+
+	// The rows that pointed at o no longer do, later updates of them, such as
+	// the ones adding related back, are checked against that
+	for _, rel := range cleared {
+		if rel.readonly == nil || !rel.readonly.ShelfID.Valid {
+			continue
+		}
+		if o.ID == rel.readonly.ShelfID.Int64 {
+			rel.readonly.ShelfID.Valid = false
+		}
+	}
+
+	return o.AddBooksContext(ctx, exec, insert, related...)
 }
 
 // RemoveBooks relationships from objects passed in.
 // Removes related items from R.Books (uses pointer comparison, removal does not keep order)
 // Sets related.R.Shelf.
 func (o *Shelf) RemoveBooks(exec boil.Executor, related ...*Book) error {
+	return o.RemoveBooksContext(context.Background(), exec, related...)
+}
+
+// RemoveBooksContext is RemoveBooks, with its queries canceled with ctx.
+func (o *Shelf) RemoveBooksContext(ctx context.Context, exec boil.Executor, related ...*Book) error {
 	var err error
 	for _, rel := range related {
 		rel.ShelfID.Valid = false
 		if rel.R != nil {
 			rel.R.Shelf = nil
 		}
-		if err = rel.Update(exec, "shelf_id"); err != nil {
+		if err = rel.UpdateContext(ctx, exec, "shelf_id"); err != nil {
 			return err
 		}
 	}
@@ -537,9 +672,19 @@ func ShelvesG(mods ...qm.QueryMod) shelfQuery {
 }
 
 // Shelves retrieves all the records using an executor.
+// Soft-deleted records are left out, unless asked for with WithDeleted or
+// OnlyDeleted.
 func Shelves(exec boil.Executor, mods ...qm.QueryMod) shelfQuery {
 	mods = append(mods, qm.From("`shelf`"))
-	return shelfQuery{NewQuery(exec, mods...)}
+	q, deleted := newSoftDeleteQuery(exec, mods...)
+	switch deleted {
+	case excludeDeleted:
+		queries.AppendWhere(q, "`shelf`.`deleted_at` IS NULL")
+	case onlyDeleted:
+		queries.AppendWhere(q, "`shelf`.`deleted_at` IS NOT NULL")
+	}
+
+	return shelfQuery{Query: q, deleted: deleted}
 }
 
 // FindShelfG retrieves a single record by ID.
@@ -559,7 +704,19 @@ func FindShelfGP(id int64, selectCols ...string) *Shelf {
 
 // FindShelf retrieves a single record by ID with an executor.
 // If selectCols is empty Find will return all columns.
+// A soft-deleted record is not found.
 func FindShelf(exec boil.Executor, id int64, selectCols ...string) (*Shelf, error) {
+	return FindShelfContext(context.Background(), exec, id, selectCols...)
+}
+
+// FindShelfContext is FindShelf, with the query canceled with ctx.
+func FindShelfContext(ctx context.Context, exec boil.Executor, id int64, selectCols ...string) (*Shelf, error) {
+	return findShelf(ctx, exec, false, id, selectCols...)
+}
+
+// findShelf is FindShelfContext, also finding a soft-deleted record when
+// deleted is set.
+func findShelf(ctx context.Context, exec boil.Executor, deleted bool, id int64, selectCols ...string) (*Shelf, error) {
 	shelfObj := &Shelf{}
 	shelfObj.readonly = &Shelf{}
 
@@ -570,10 +727,13 @@ func FindShelf(exec boil.Executor, id int64, selectCols ...string) (*Shelf, erro
 	query := fmt.Sprintf(
 		"select %s from `shelf` where `id`=?", sel,
 	)
+	if !deleted {
+		query += " and `deleted_at` is null"
+	}
 
 	q := queries.Raw(exec, query, id)
 
-	err := q.Bind(shelfObj)
+	err := withContext(ctx, q).Bind(shelfObj)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, sql.ErrNoRows
@@ -623,6 +783,11 @@ func (o *Shelf) InsertP(exec boil.Executor, whitelist ...string) {
 // - All columns without a default value are included (i.e. name, age)
 // - All columns with a default, but non-zero are included (i.e. health = 75)
 func (o *Shelf) Insert(exec boil.Executor, whitelist ...string) error {
+	return o.InsertContext(context.Background(), exec, whitelist...)
+}
+
+// InsertContext is Insert, with its queries canceled with ctx.
+func (o *Shelf) InsertContext(ctx context.Context, exec boil.Executor, whitelist ...string) error {
 	if o == nil {
 		return errors.New("models: no shelf provided for insertion")
 	}
@@ -631,7 +796,7 @@ func (o *Shelf) Insert(exec boil.Executor, whitelist ...string) error {
 
 	var err error
 
-	if err := o.doBeforeInsertHooks(exec); err != nil {
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -674,7 +839,7 @@ func (o *Shelf) Insert(exec boil.Executor, whitelist ...string) error {
 		fmt.Fprintln(boil.DebugWriter, vals)
 	}
 
-	result, err := exec.Exec(cache.query, vals...)
+	result, err := execContext(ctx, exec, cache.query, vals...)
 
 	if err != nil {
 		return errors.Wrap(err, "models: unable to insert into shelf")
@@ -706,7 +871,7 @@ func (o *Shelf) Insert(exec boil.Executor, whitelist ...string) error {
 		fmt.Fprintln(boil.DebugWriter, identifierCols...)
 	}
 
-	err = exec.QueryRow(cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	err = queryRowContext(ctx, exec, cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to populate default values for shelf")
 	}
@@ -718,7 +883,7 @@ CacheNoHooks:
 		shelfInsertCacheMut.Unlock()
 	}
 
-	return o.doAfterInsertHooks(exec)
+	return o.doAfterInsertHooks(ctx, exec)
 }
 
 // UpdateG a single Shelf record. See Update for
@@ -748,30 +913,57 @@ func (o *Shelf) UpdateP(exec boil.Executor, whitelist ...string) {
 // Update uses an executor to update the Shelf.
 // Whitelist behavior: If a whitelist is provided, only the columns given are updated.
 // No whitelist behavior: Without a whitelist, columns are inferred by the following rules:
-// - All columns are inferred to start with
+// - Only the columns changed since the object was selected are updated
+// - An object that was not selected has all columns inferred to start with
 // - All primary keys are subtracted from this set
+// An object that was selected and has no changes is not updated at all.
 // Update does not automatically update the record in case of default values. Use .Reload()
 // to refresh the records.
+//
+// Concurrency: for a selected object, the update only applies while the
+// updated columns still hold the values they were selected with.
+// ErrStaleObject is returned when they do not. With MySQL, connect with
+// clientFoundRows=true so that rows updated to the values they already had
+// are not taken for stale ones.
 func (o *Shelf) Update(exec boil.Executor, whitelist ...string) error {
+	return o.UpdateContext(context.Background(), exec, whitelist...)
+}
+
+// UpdateContext is Update, with its queries canceled with ctx.
+func (o *Shelf) UpdateContext(ctx context.Context, exec boil.Executor, whitelist ...string) error {
 	o.whitelist = whitelist
 	whitelist = o.Whitelist()
+	if o.readonly != nil && len(o.whitelist) == 0 && len(whitelist) == 0 {
+		return nil
+	}
 
 	o.operation = "UPDATE"
+
+	var lockCols []string
+	var lockValues []interface{}
+
 	var err error
-	if err = o.doBeforeUpdateHooks(exec); err != nil {
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
 		return err
 	}
+
+	// Inferred columns include the primary key of an object that was not
+	// selected, which is what the row is found by rather than something to set
+	if len(o.whitelist) == 0 {
+		whitelist = strmangle.SetComplement(whitelist, shelfPrimaryKeyColumns)
+	}
+
+	wl := strmangle.UpdateColumnSet(shelfColumns, shelfPrimaryKeyColumns, whitelist)
+	if len(wl) == 0 {
+		return errors.New("models: unable to update shelf, could not build whitelist")
+	}
+
 	key := makeCacheKey(whitelist, nil)
 	shelfUpdateCacheMut.RLock()
 	cache, cached := shelfUpdateCache[key]
 	shelfUpdateCacheMut.RUnlock()
 
 	if !cached {
-		wl := strmangle.UpdateColumnSet(shelfColumns, shelfPrimaryKeyColumns, whitelist)
-		if len(wl) == 0 {
-			return errors.New("models: unable to update shelf, could not build whitelist")
-		}
-
 		cache.query = fmt.Sprintf("UPDATE `shelf` SET %s WHERE %s",
 			strmangle.SetParamNames("`", "`", 0, wl),
 			strmangle.WhereClause("`", "`", 0, shelfPrimaryKeyColumns),
@@ -784,23 +976,54 @@ func (o *Shelf) Update(exec boil.Executor, whitelist ...string) error {
 
 	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
 
+	if o.readonly != nil {
+		lockCols = wl
+		lockValues = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o.readonly)), cache.valueMapping[:len(wl)])
+	}
+
+	query := cache.query
+	if len(lockCols) != 0 {
+		lock, lockArgs := lockWhere(lockCols, lockValues, len(values)+1)
+		query += " AND " + lock
+		values = append(values, lockArgs...)
+	}
+
 	if boil.DebugMode {
-		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, query)
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	_, err = exec.Exec(cache.query, values...)
+	result, err := execContext(ctx, exec, query, values...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to update shelf row")
 	}
 
+	if len(lockCols) != 0 {
+		rowsAff, err := result.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "models: failed to get rows affected by update for shelf")
+		}
+		if rowsAff == 0 {
+			return ErrStaleObject
+		}
+	}
+
 	if !cached {
 		shelfUpdateCacheMut.Lock()
 		shelfUpdateCache[key] = cache
 		shelfUpdateCacheMut.Unlock()
 	}
 
-	return o.doAfterUpdateHooks(exec)
+	if err = o.doAfterUpdateHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	// The row now holds what o does, later updates are checked against it
+	if o.readonly != nil {
+		*o.readonly = *o
+	}
+
+	return nil
 }
 
 // UpdateAllP updates all rows with matching column names, and panics on error.
@@ -811,14 +1034,33 @@ func (q shelfQuery) UpdateAllP(cols M) {
 }
 
 // UpdateAll updates all rows with the specified column values.
+// See WithChanges for the Changesets of the rows updated.
 func (q shelfQuery) UpdateAll(cols M) error {
+	return q.UpdateAllContext(context.Background(), cols)
+}
+
+// UpdateAllContext is UpdateAll, with its queries canceled with ctx.
+func (q shelfQuery) UpdateAllContext(ctx context.Context, cols M) error {
+	rows, err := q.affected(ctx)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to select rows to update all for shelf")
+	}
+
 	queries.SetUpdate(q.Query, cols)
 
-	_, err := q.Query.Exec()
+	_, err = withContext(ctx, q.Query).Exec()
 	if err != nil {
 		return errors.Wrap(err, "models: unable to update all for shelf")
 	}
 
+	if len(rows) != 0 {
+		chs := make([]*Changeset, len(rows))
+		for i, obj := range rows {
+			chs[i] = obj.updatedChangeset(cols)
+		}
+		addChanges(ctx, queries.GetExecutor(q.Query), chs...)
+	}
+
 	return nil
 }
 
@@ -842,7 +1084,14 @@ func (o ShelfSlice) UpdateAllP(exec boil.Executor, cols M) {
 }
 
 // UpdateAll updates all rows with the specified column values, using an executor.
+// A Changeable executor gets a Changeset per row, with the values the rows
+// were selected with as before values.
 func (o ShelfSlice) UpdateAll(exec boil.Executor, cols M) error {
+	return o.UpdateAllContext(context.Background(), exec, cols)
+}
+
+// UpdateAllContext is UpdateAll, with the query canceled with ctx.
+func (o ShelfSlice) UpdateAllContext(ctx context.Context, exec boil.Executor, cols M) error {
 	ln := int64(len(o))
 	if ln == 0 {
 		return nil
@@ -879,11 +1128,19 @@ func (o ShelfSlice) UpdateAll(exec boil.Executor, cols M) error {
 		fmt.Fprintln(boil.DebugWriter, args...)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to update all in shelf slice")
 	}
 
+	if _, ok := exec.(Changeable); ok {
+		chs := make([]*Changeset, len(o))
+		for i, obj := range o {
+			chs[i] = obj.updatedChangeset(cols)
+		}
+		addChanges(ctx, exec, chs...)
+	}
+
 	return nil
 }
 
@@ -909,13 +1166,18 @@ func (o *Shelf) UpsertP(exec boil.Executor, updateColumns []string, whitelist ..
 
 // Upsert attempts an insert using an executor, and does an update or ignore on conflict.
 func (o *Shelf) Upsert(exec boil.Executor, updateColumns []string, whitelist ...string) error {
+	return o.UpsertContext(context.Background(), exec, updateColumns, whitelist...)
+}
+
+// UpsertContext is Upsert, with its queries canceled with ctx.
+func (o *Shelf) UpsertContext(ctx context.Context, exec boil.Executor, updateColumns []string, whitelist ...string) error {
 	if o == nil {
 		return errors.New("models: no shelf provided for upsert")
 	}
 	o.whitelist = whitelist
 	o.operation = "UPSERT"
 
-	if err := o.doBeforeUpsertHooks(exec); err != nil {
+	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -991,7 +1253,7 @@ func (o *Shelf) Upsert(exec boil.Executor, updateColumns []string, whitelist ...
 		fmt.Fprintln(boil.DebugWriter, vals)
 	}
 
-	result, err := exec.Exec(cache.query, vals...)
+	result, err := execContext(ctx, exec, cache.query, vals...)
 
 	if err != nil {
 		return errors.Wrap(err, "models: unable to upsert for shelf")
@@ -1023,7 +1285,7 @@ func (o *Shelf) Upsert(exec boil.Executor, updateColumns []string, whitelist ...
 		fmt.Fprintln(boil.DebugWriter, identifierCols...)
 	}
 
-	err = exec.QueryRow(cache.retQuery, identifierCols...).Scan(returns...)
+	err = queryRowContext(ctx, exec, cache.retQuery, identifierCols...).Scan(returns...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to populate default values for shelf")
 	}
@@ -1035,7 +1297,7 @@ CacheNoHooks:
 		shelfUpsertCacheMut.Unlock()
 	}
 
-	return o.doAfterUpsertHooks(exec)
+	return o.doAfterUpsertHooks(ctx, exec)
 }
 
 // DeleteP deletes a single Shelf record with an executor.
@@ -1066,15 +1328,107 @@ func (o *Shelf) DeleteGP() {
 	}
 }
 
-// Delete deletes a single Shelf record with an executor.
-// Delete will match against the primary key column to find the record to delete.
+// Delete soft deletes a single Shelf record with an executor, setting its
+// deleted_at. Delete will match against the primary key column to find the
+// record to delete. The record is left out of queries from then on, see
+// Restore and HardDelete. A record deleted already keeps the time it was
+// deleted at.
 func (o *Shelf) Delete(exec boil.Executor) error {
+	return o.DeleteContext(context.Background(), exec)
+}
+
+// DeleteContext is Delete, with the query canceled with ctx.
+func (o *Shelf) DeleteContext(ctx context.Context, exec boil.Executor) error {
+	if o == nil {
+		return errors.New("models: no Shelf provided for delete")
+	}
+	operation, whitelist := o.operation, o.whitelist
+	o.operation = "SOFT_DELETE"
+	o.whitelist = []string{"deleted_at"}
+
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	// Whole seconds, which any datetime column holds unchanged, so that the
+	// deleted_at o holds is the one of the row
+	currTime := time.Now().In(boil.GetLocation()).Truncate(time.Second)
+	args := append([]interface{}{currTime}, queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), shelfPrimaryKeyMapping)...)
+	sql := "UPDATE `shelf` SET `deleted_at`=? WHERE `id`=? AND `deleted_at` IS NULL"
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, sql)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+
+	result, err := execContext(ctx, exec, sql, args...)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to soft delete from shelf")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "models: failed to get rows affected by soft delete for shelf")
+	}
+	// The row was deleted already, and nothing changed
+	if rowsAff == 0 {
+		o.operation, o.whitelist = operation, whitelist
+		return nil
+	}
+
+	o.DeletedAt.Time = currTime
+	o.DeletedAt.Valid = true
+
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	// The row now holds what o does, later updates are checked against it
+	if o.readonly != nil {
+		*o.readonly = *o
+	}
+
+	return nil
+}
+
+// Restore brings back a Shelf record soft deleted by Delete, clearing
+// its deleted_at. See Update for how a selected object is checked against
+// the row.
+func (o *Shelf) Restore(exec boil.Executor) error {
+	return o.RestoreContext(context.Background(), exec)
+}
+
+// RestoreContext is Restore, with the query canceled with ctx.
+func (o *Shelf) RestoreContext(ctx context.Context, exec boil.Executor) error {
+	if o == nil {
+		return errors.New("models: no Shelf provided for restore")
+	}
+
+	o.DeletedAt.Valid = false
+	if err := o.UpdateContext(ctx, exec, "deleted_at"); err != nil {
+		o.DeletedAt.Valid = true
+		return err
+	}
+
+	return nil
+}
+
+// HardDelete deletes a single Shelf record with an executor for good,
+// whether it was soft deleted or not. HardDelete will match against the
+// primary key column to find the record to delete.
+func (o *Shelf) HardDelete(exec boil.Executor) error {
+	return o.HardDeleteContext(context.Background(), exec)
+}
+
+// HardDeleteContext is HardDelete, with the query canceled with ctx.
+func (o *Shelf) HardDeleteContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil {
 		return errors.New("models: no Shelf provided for delete")
 	}
 	o.operation = "DELETE"
+	o.whitelist = nil
 
-	if err := o.doBeforeDeleteHooks(exec); err != nil {
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -1086,12 +1440,12 @@ func (o *Shelf) Delete(exec boil.Executor) error {
 		fmt.Fprintln(boil.DebugWriter, args)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to delete from shelf")
 	}
 
-	if err := o.doAfterDeleteHooks(exec); err != nil {
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
 		return err
 	}
 
@@ -1106,18 +1460,49 @@ func (q shelfQuery) DeleteAllP() {
 }
 
 // DeleteAll deletes all matching rows.
+// See WithChanges for the Changesets of the rows deleted.
+// The rows are soft deleted, as Delete does, and those deleted already keep
+// the time they were deleted at.
 func (q shelfQuery) DeleteAll() error {
+	return q.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext is DeleteAll, with its queries canceled with ctx.
+func (q shelfQuery) DeleteAllContext(ctx context.Context) error {
 	if q.Query == nil {
 		return errors.New("models: no shelfQuery provided for delete all")
 	}
 
-	queries.SetDelete(q.Query)
+	// Rows deleted already keep the time they were deleted at
+	if q.deleted != excludeDeleted {
+		queries.AppendWhere(q.Query, "`shelf`.`deleted_at` IS NULL")
+	}
 
-	_, err := q.Query.Exec()
+	rows, err := q.affected(ctx)
+	if err != nil {
+		return errors.Wrap(err, "models: unable to select rows to delete all from shelf")
+	}
+
+	currTime := time.Now().In(boil.GetLocation()).Truncate(time.Second)
+	queries.SetUpdate(q.Query, M{"deleted_at": currTime})
+
+	_, err = withContext(ctx, q.Query).Exec()
 	if err != nil {
 		return errors.Wrap(err, "models: unable to delete all from shelf")
 	}
 
+	if len(rows) != 0 {
+		chs := make([]*Changeset, len(rows))
+		for i, obj := range rows {
+			obj.operation = "SOFT_DELETE"
+			obj.whitelist = []string{"deleted_at"}
+			obj.DeletedAt.Time = currTime
+			obj.DeletedAt.Valid = true
+			chs[i], _ = obj.Changes()
+		}
+		addChanges(ctx, queries.GetExecutor(q.Query), chs...)
+	}
+
 	return nil
 }
 
@@ -1144,7 +1529,14 @@ func (o ShelfSlice) DeleteAllP(exec boil.Executor) {
 }
 
 // DeleteAll deletes all rows in the slice, using an executor.
+// The rows are soft deleted, as Delete does, and those deleted already keep
+// the time they were deleted at.
 func (o ShelfSlice) DeleteAll(exec boil.Executor) error {
+	return o.DeleteAllContext(context.Background(), exec)
+}
+
+// DeleteAllContext is DeleteAll, with the query canceled with ctx.
+func (o ShelfSlice) DeleteAllContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil {
 		return errors.New("models: no Shelf slice provided for delete all")
 	}
@@ -1153,24 +1545,43 @@ func (o ShelfSlice) DeleteAll(exec boil.Executor) error {
 		return nil
 	}
 
+	// Rows deleted already keep the time they were deleted at
+	var deleted ShelfSlice
+	for _, obj := range o {
+		if !obj.DeletedAt.Valid {
+			deleted = append(deleted, obj)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	o = deleted
+
+	for _, obj := range o {
+		obj.operation = "SOFT_DELETE"
+		obj.whitelist = []string{"deleted_at"}
+	}
+
 	if len(shelfBeforeDeleteHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
 				return err
 			}
 		}
 	}
 
-	var args []interface{}
+	currTime := time.Now().In(boil.GetLocation()).Truncate(time.Second)
+	args := []interface{}{currTime}
 	for _, obj := range o {
 		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), shelfPrimaryKeyMapping)
 		args = append(args, pkeyArgs...)
 	}
 
 	sql := fmt.Sprintf(
-		"DELETE FROM `shelf` WHERE (%s) IN (%s)",
+		"UPDATE `shelf` SET %s WHERE (%s) IN (%s) AND `deleted_at` IS NULL",
+		strmangle.SetParamNames("`", "`", 0, []string{"deleted_at"}),
 		strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, shelfPrimaryKeyColumns), ","),
-		strmangle.Placeholders(dialect.IndexPlaceholders, len(o)*len(shelfPrimaryKeyColumns), 1, len(shelfPrimaryKeyColumns)),
+		strmangle.Placeholders(dialect.IndexPlaceholders, len(o)*len(shelfPrimaryKeyColumns), 2, len(shelfPrimaryKeyColumns)),
 	)
 
 	if boil.DebugMode {
@@ -1178,19 +1589,31 @@ func (o ShelfSlice) DeleteAll(exec boil.Executor) error {
 		fmt.Fprintln(boil.DebugWriter, args)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to delete all from shelf slice")
 	}
 
+	for _, obj := range o {
+		obj.DeletedAt.Time = currTime
+		obj.DeletedAt.Valid = true
+	}
+
 	if len(shelfAfterDeleteHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doAfterDeleteHooks(exec); err != nil {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
 				return err
 			}
 		}
 	}
 
+	// The rows now hold what o does, later updates are checked against them
+	for _, obj := range o {
+		if obj.readonly != nil {
+			*obj.readonly = *obj
+		}
+	}
+
 	return nil
 }
 
@@ -1219,8 +1642,14 @@ func (o *Shelf) ReloadG() error {
 
 // Reload refetches the object from the database
 // using the primary keys with an executor.
+// A soft-deleted object is reloaded as well, as with ReloadAll.
 func (o *Shelf) Reload(exec boil.Executor) error {
-	ret, err := FindShelf(exec, o.ID)
+	return o.ReloadContext(context.Background(), exec)
+}
+
+// ReloadContext is Reload, with the query canceled with ctx.
+func (o *Shelf) ReloadContext(ctx context.Context, exec boil.Executor) error {
+	ret, err := findShelf(ctx, exec, true, o.ID)
 	if err != nil {
 		return err
 	}
@@ -1260,6 +1689,11 @@ func (o *ShelfSlice) ReloadAllG() error {
 // ReloadAll refetches every row with matching primary key column values
 // and overwrites the original object slice with the newly updated slice.
 func (o *ShelfSlice) ReloadAll(exec boil.Executor) error {
+	return o.ReloadAllContext(context.Background(), exec)
+}
+
+// ReloadAllContext is ReloadAll, with the query canceled with ctx.
+func (o *ShelfSlice) ReloadAllContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil || len(*o) == 0 {
 		return nil
 	}
@@ -1279,7 +1713,7 @@ func (o *ShelfSlice) ReloadAll(exec boil.Executor) error {
 
 	q := queries.Raw(exec, sql, args...)
 
-	err := q.Bind(&shelves)
+	err := withContext(ctx, q).Bind(&shelves)
 	if err != nil {
 		return errors.Wrap(err, "models: unable to reload all in ShelfSlice")
 	}
@@ -1290,17 +1724,23 @@ func (o *ShelfSlice) ReloadAll(exec boil.Executor) error {
 }
 
 // ShelfExists checks if the Shelf row exists.
+// A soft-deleted row does not.
 func ShelfExists(exec boil.Executor, id int64) (bool, error) {
+	return ShelfExistsContext(context.Background(), exec, id)
+}
+
+// ShelfExistsContext is ShelfExists, with the query canceled with ctx.
+func ShelfExistsContext(ctx context.Context, exec boil.Executor, id int64) (bool, error) {
 	var exists bool
 
-	sql := "select exists(select 1 from `shelf` where `id`=? limit 1)"
+	sql := "select exists(select 1 from `shelf` where `id`=? and `deleted_at` is null limit 1)"
 
 	if boil.DebugMode {
 		fmt.Fprintln(boil.DebugWriter, sql)
 		fmt.Fprintln(boil.DebugWriter, id)
 	}
 
-	row := exec.QueryRow(sql, id)
+	row := queryRowContext(ctx, exec, sql, id)
 
 	err := row.Scan(&exists)
 	if err != nil {
@@ -1335,35 +1775,58 @@ func ShelfExistsP(exec boil.Executor, id int64) bool {
 	return e
 }
 
-// InsertG a single record. See Insert for whitelist behavior description.
+// Changes lists the whitelisted columns of o that differ from the values it
+// was selected with. Inserts have no before values and deletes no after
+// values; an object deleted without being selected has its own values as
+// before values, and one updated without being selected has unknown ones.
+// Columns set sensitive with SetSensitive are masked or left out.
 func (o *Shelf) Changes() (ch *Changeset, err error) {
-	ch = &Changeset{Table: "shelf",
-		Changes: []*ChangeItem{}, Operation: o.Operation()}
-	v := reflect.Indirect(reflect.ValueOf(o.readonly))
-	vnew := reflect.Indirect(reflect.ValueOf(o))
+	ch = o.newChangeset(o.Operation())
 
+	ro := o.readonly
+	deleted := o.operation == "DELETE"
 	for _, c := range o.Whitelist() {
-		if f, ok := ShelfFieldMapping[c]; ok {
-			var before, after interface{}
-			if v.IsValid() {
-				before = v.FieldByName(f).Interface()
-			}
+		sens := sensitivity("shelf", c)
+		if sens == Exclude {
+			continue
+		}
 
-			if vnew.IsValid() {
-				after = vnew.FieldByName(f).Interface()
+		var chitem *ChangeItem
+		switch c {
+		case "id":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().ID}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.ID, BeforeUnknown: o.operation == "UPDATE"}
+			case o.ID != ro.ID:
+				chitem = &ChangeItem{Name: c, Before: ro.ID, After: o.ID}
 			}
-
-			chitem := &ChangeItem{Name: c}
-			if o.operation == "DELETE" {
-				chitem.Before = before
-			} else {
-				chitem.Before = before
-				chitem.After = after
+		case "area":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().Area}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.Area, BeforeUnknown: o.operation == "UPDATE"}
+			case o.Area.Valid != ro.Area.Valid || (o.Area.Valid && o.Area != ro.Area):
+				chitem = &ChangeItem{Name: c, Before: ro.Area, After: o.Area}
+			}
+		case "deleted_at":
+			switch {
+			case deleted:
+				chitem = &ChangeItem{Name: c, Before: o.selected().DeletedAt}
+			case ro == nil:
+				chitem = &ChangeItem{Name: c, After: o.DeletedAt, BeforeUnknown: o.operation == "UPDATE"}
+			case o.DeletedAt.Valid != ro.DeletedAt.Valid || (o.DeletedAt.Valid && !o.DeletedAt.Time.Equal(ro.DeletedAt.Time)):
+				chitem = &ChangeItem{Name: c, Before: ro.DeletedAt, After: o.DeletedAt}
 			}
+		}
 
-			if !reflect.DeepEqual(chitem.Before, chitem.After) {
-				ch.Changes = append(ch.Changes, chitem)
+		if chitem != nil {
+			if sens == Redact {
+				chitem.redact()
 			}
+			ch.Changes = append(ch.Changes, chitem)
 		}
 	}
 
@@ -1376,27 +1839,100 @@ func (o *Shelf) Whitelist() (wl []string) {
 		return o.whitelist
 	}
 
-	// Calculates changed columns as whitelist
-	v := reflect.Indirect(reflect.ValueOf(o.readonly))
-	vnew := reflect.Indirect(reflect.ValueOf(o))
+	// Without a selected copy to compare with, and on delete, every column
+	// counts as changed
+	ro := o.readonly
+	if ro == nil || o.operation == "DELETE" {
+		return append(wl, shelfColumns...)
+	}
 
+	if o.ID != ro.ID {
+		wl = append(wl, "id")
+	}
+	if o.Area.Valid != ro.Area.Valid || (o.Area.Valid && o.Area != ro.Area) {
+		wl = append(wl, "area")
+	}
+	if o.DeletedAt.Valid != ro.DeletedAt.Valid || (o.DeletedAt.Valid && !o.DeletedAt.Time.Equal(ro.DeletedAt.Time)) {
+		wl = append(wl, "deleted_at")
+	}
+
+	return
+}
+
+// newChangeset starts a Changeset of op on the row of o, without changes.
+func (o *Shelf) newChangeset(op string) *Changeset {
+	return &Changeset{Table: "shelf",
+		PrimaryKey: map[string]interface{}{
+			"id": o.ID,
+		},
+		Changes: []*ChangeItem{}, Operation: op,
+		ChangedAt: time.Now().In(boil.GetLocation())}
+}
+
+// selected returns the values the row of o is known to hold: the ones o was
+// selected with, or o itself when it was not selected.
+func (o *Shelf) selected() *Shelf {
+	if o.readonly != nil {
+		return o.readonly
+	}
+
+	return o
+}
+
+// updatedChangeset records a bulk update setting cols on the row of o.
+// Columns set sensitive with SetSensitive are masked or left out.
+func (o *Shelf) updatedChangeset(cols M) *Changeset {
+	ch := o.newChangeset("UPDATE")
+	ro := o.selected()
 	for _, c := range shelfColumns {
-		if f, ok := ShelfFieldMapping[c]; ok {
-			var before, after interface{}
-			if v.IsValid() {
-				before = v.FieldByName(f).Interface()
-			}
+		v, ok := cols[c]
+		if !ok {
+			continue
+		}
 
-			if vnew.IsValid() {
-				after = vnew.FieldByName(f).Interface()
-			}
-			if !reflect.DeepEqual(before, after) || o.operation == "DELETE" {
-				wl = append(wl, c)
-			}
+		sens := sensitivity("shelf", c)
+		if sens == Exclude {
+			continue
+		}
+
+		chitem := &ChangeItem{Name: c, After: v}
+		switch c {
+		case "id":
+			chitem.Before = ro.ID
+		case "area":
+			chitem.Before = ro.Area
+		case "deleted_at":
+			chitem.Before = ro.DeletedAt
 		}
+		if sens == Redact {
+			chitem.redact()
+		}
+		ch.Changes = append(ch.Changes, chitem)
 	}
 
-	return
+	return ch
+}
+
+// WithChanges makes UpdateAll and DeleteAll select the rows they affect
+// beforehand, so that a Changeable executor gets a Changeset per row. Run
+// it in a transaction for the rows selected to be the ones changed.
+func (q shelfQuery) WithChanges() shelfQuery {
+	q.changes = true
+	return q
+}
+
+// affected selects the rows q is about to update or delete, when WithChanges
+// asked for their Changesets and the executor takes them.
+func (q shelfQuery) affected(ctx context.Context) (ShelfSlice, error) {
+	if !q.changes {
+		return nil, nil
+	}
+	if _, ok := queries.GetExecutor(q.Query).(Changeable); !ok {
+		return nil, nil
+	}
+
+	sel := *q.Query
+	return shelfQuery{Query: &sel}.AllContext(ctx)
 }
 
 func (o *Shelf) Operation() string {
@@ -1405,20 +1941,18 @@ func (o *Shelf) Operation() string {
 
 // Generated change history hook for models
 func init() {
-	chFunc := func(exec boil.Executor, s *Shelf) error {
+	chFunc := func(ctx context.Context, exec boil.Executor, s *Shelf) error {
 		if s == nil || exec == nil {
 			return nil
 		}
 
 		ch, _ := s.Changes()
-		if changeable, ok := exec.(Changeable); ok {
-			changeable.AddChange(ch)
-		}
+		addChanges(ctx, exec, ch)
 
 		return nil
 	}
 
-	afterSel := func(exec boil.Executor, s *Shelf) error {
+	afterSel := func(ctx context.Context, exec boil.Executor, s *Shelf) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1428,7 +1962,7 @@ func init() {
 		return nil
 	}
 
-	beforeInsert := func(exec boil.Executor, s *Shelf) error {
+	beforeInsert := func(ctx context.Context, exec boil.Executor, s *Shelf) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1437,7 +1971,7 @@ func init() {
 		return nil
 	}
 
-	beforeUpdate := func(exec boil.Executor, s *Shelf) error {
+	beforeUpdate := func(ctx context.Context, exec boil.Executor, s *Shelf) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1446,7 +1980,7 @@ func init() {
 		return nil
 	}
 
-	beforeUpsert := func(exec boil.Executor, s *Shelf) error {
+	beforeUpsert := func(ctx context.Context, exec boil.Executor, s *Shelf) error {
 		if s == nil || exec == nil {
 			return nil
 		}
@@ -1455,12 +1989,14 @@ func init() {
 		return nil
 	}
 
-	afterDelete := func(exec boil.Executor, s *Shelf) error {
+	afterDelete := func(ctx context.Context, exec boil.Executor, s *Shelf) error {
 		if s == nil || exec == nil {
 			return nil
 		}
 
-		s.operation = "DELETE"
+		if s.operation != "SOFT_DELETE" {
+			s.operation = "DELETE"
+		}
 		return nil
 	}
 
@@ -1474,3 +2010,222 @@ func init() {
 	AddShelfHook(boil.AfterDeleteHook, afterDelete)
 	AddShelfHook(boil.AfterDeleteHook, chFunc)
 }
+
+// ApplyShelf replays ch, recorded on shelf: an INSERT inserts the
+// row with its After values, an UPDATE or UPSERT sets its After values and
+// a DELETE deletes it. A SOFT_DELETE sets deleted_at again.
+func ApplyShelf(exec boil.Executor, ch *Changeset) error {
+	o, cols, err := shelfFromChangeset(ch, true)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Operation {
+	case "INSERT":
+		return o.reinsert(exec, cols)
+	case "UPDATE", "UPSERT", "SOFT_DELETE":
+		return o.restore(exec, cols)
+	case "DELETE":
+		return o.HardDelete(exec)
+	}
+
+	return errors.Errorf("models: unable to apply %s changes to shelf", ch.Operation)
+}
+
+// RevertShelf undoes ch, recorded on shelf: an INSERT deletes the
+// row, an UPDATE restores its Before values and a DELETE inserts it again
+// with them. An UPSERT is undone as the insert or update it turned out to be.
+// A SOFT_DELETE is undone by clearing deleted_at.
+func RevertShelf(exec boil.Executor, ch *Changeset) error {
+	o, cols, err := shelfFromChangeset(ch, false)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Operation {
+	case "INSERT":
+		return o.HardDelete(exec)
+	case "UPSERT":
+		if ch.inserted() {
+			return o.HardDelete(exec)
+		}
+		return o.restore(exec, cols)
+	case "UPDATE", "SOFT_DELETE":
+		return o.restore(exec, cols)
+	case "DELETE":
+		return o.reinsert(exec, cols)
+	}
+
+	return errors.Errorf("models: unable to revert %s changes to shelf", ch.Operation)
+}
+
+// shelfFromChangeset builds the row ch was recorded on, with its After values
+// or its Before ones, and lists the columns set from them. Redacted values
+// are not known, so their columns are left alone. Before values that were
+// not known when ch was recorded make it fail.
+func shelfFromChangeset(ch *Changeset, after bool) (*Shelf, []string, error) {
+	if ch == nil || ch.Table != "shelf" {
+		return nil, nil, errors.New("models: changes were not recorded on shelf")
+	}
+
+	o := &Shelf{}
+	cols := make([]string, 0, len(ch.Changes))
+	for _, item := range ch.Changes {
+		v := item.Before
+		if after {
+			v = item.After
+		} else if item.BeforeUnknown {
+			return nil, nil, errors.Errorf("models: unable to revert column %q of shelf, its value before the change is unknown", item.Name)
+		}
+		if v == RedactedValue {
+			continue
+		}
+
+		if err := setColumn(o, ShelfFieldMapping, item.Name, v); err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, item.Name)
+	}
+
+	// The key the row has now, even where a change was to it
+	for c, v := range ch.PrimaryKey {
+		if err := setColumn(o, ShelfFieldMapping, c, v); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o, cols, nil
+}
+
+// reinsert inserts o with the columns listed in cols and its primary key.
+func (o *Shelf) reinsert(exec boil.Executor, cols []string) error {
+	return o.Insert(exec, strmangle.SetMerge(cols, shelfPrimaryKeyColumns)...)
+}
+
+// restore updates the columns of o listed in cols, leaving its primary key
+// alone.
+func (o *Shelf) restore(exec boil.Executor, cols []string) error {
+	cols = strmangle.SetComplement(cols, shelfPrimaryKeyColumns)
+	if len(cols) == 0 {
+		return nil
+	}
+
+	return o.Update(exec, cols...)
+}
+
+func init() {
+	changeAppliers["shelf"] = struct {
+		apply, revert func(boil.Executor, *Changeset) error
+	}{ApplyShelf, RevertShelf}
+}
+
+var shelfSchema = SchemaTable{
+	Name: "shelf",
+	Columns: []SchemaColumn{
+		{Name: "id", Type: "int64", DBType: "bigint", Nullable: false},
+		{Name: "area", Type: "null.String", DBType: "varchar", Nullable: true},
+		{Name: "deleted_at", Type: "null.Time", DBType: "datetime", Nullable: true},
+	},
+	PKey: []string{"id"},
+}
+
+func init() {
+	SchemaTables["shelf"] = shelfSchema
+}
+
+// SortBy sets the columns the pages of the query are sorted on, a "-" prefix
+// sorting one in descending order. The primary key breaks ties, and sorts
+// the pages on its own without SortBy. Do not combine it with qm.OrderBy.
+func (q shelfQuery) SortBy(cols ...string) shelfQuery {
+	q.keyset.sort = cols
+	return q
+}
+
+// PageSize sets the number of rows of the pages of the query.
+func (q shelfQuery) PageSize(n int) shelfQuery {
+	q.keyset.size = n
+	return q
+}
+
+// After makes the query select the page following the one cursor was
+// returned with, as Cursors.Next. An empty cursor selects the first page.
+func (q shelfQuery) After(cursor string) shelfQuery {
+	q.keyset.cursor = cursor
+	q.keyset.before = false
+	return q
+}
+
+// Before makes the query select the page preceding the one cursor was
+// returned with, as Cursors.Prev. An empty cursor selects the last page.
+func (q shelfQuery) Before(cursor string) shelfQuery {
+	q.keyset.cursor = cursor
+	q.keyset.before = true
+	return q
+}
+
+// PageP returns the page of Shelf records the query asks for, and panics on error.
+func (q shelfQuery) PageP() (ShelfSlice, *Cursors) {
+	o, cursors, err := q.Page()
+	if err != nil {
+		panic(boil.WrapErr(err))
+	}
+
+	return o, cursors
+}
+
+// Page returns the page of Shelf records asked for with SortBy, PageSize,
+// After and Before, with the cursors of the pages around it. Rather than
+// skipping rows with an offset, it selects those sorted past the cursor, so
+// it is as fast on any page and rows changed in between are not skipped.
+// ErrInvalidCursor is returned for a cursor of another sort.
+func (q shelfQuery) Page() (ShelfSlice, *Cursors, error) {
+	return q.PageContext(context.Background())
+}
+
+// PageContext is Page, with its query canceled with ctx.
+func (q shelfQuery) PageContext(ctx context.Context) (ShelfSlice, *Cursors, error) {
+	k := q.keyset
+	cols, err := k.columns(ShelfFieldMapping, shelfPrimaryKeyColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sel := *q.Query
+	if err := k.apply(&sel, &Shelf{}, ShelfFieldMapping, cols); err != nil {
+		return nil, nil, err
+	}
+
+	o, err := shelfQuery{Query: &sel}.AllContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	more := k.size > 0 && len(o) > k.size
+	if more {
+		o = o[:k.size]
+	}
+	// Rows before the cursor were selected in reverse
+	if k.before {
+		for i, j := 0, len(o)-1; i < j; i, j = i+1, j-1 {
+			o[i], o[j] = o[j], o[i]
+		}
+	}
+
+	cursors := &Cursors{}
+	if len(o) == 0 {
+		return o, cursors, nil
+	}
+
+	if (more && !k.before) || (k.before && k.cursor != "") {
+		if cursors.Next, err = encodeCursor(o[len(o)-1], ShelfFieldMapping, cols); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (more && k.before) || (!k.before && k.cursor != "") {
+		if cursors.Prev, err = encodeCursor(o[0], ShelfFieldMapping, cols); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o, cursors, nil
+}
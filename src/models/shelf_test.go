@@ -0,0 +1,1171 @@
+package models
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/vattle/sqlboiler/boil"
+	"github.com/vattle/sqlboiler/randomize"
+	"github.com/vattle/sqlboiler/strmangle"
+)
+
+import (
+	"context"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pkg/errors"
+	"gopkg.in/nullbio/null.v6"
+)
+
+// Every test file is given the same imports, which those of a table may not use
+var (
+	_ = bytes.MinRead
+	_ = boil.DebugMode
+	_ = strmangle.Placeholders
+)
+
+var shelfDBTypes = map[string]string{`Area`: `varchar`, `DeletedAt`: `datetime`, `ID`: `bigint`}
+
+// randomShelf returns a Shelf holding random, non-null values,
+// as if it was built to be inserted.
+func randomShelf(t testing.TB) *Shelf {
+	var cols struct {
+		ID        int64
+		Area      null.String
+		DeletedAt null.Time
+	}
+	if err := randomize.Struct(seed, &cols, shelfDBTypes, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Shelf{
+		ID:        cols.ID,
+		Area:      cols.Area,
+		DeletedAt: cols.DeletedAt,
+	}
+}
+
+// selectedShelf returns a random Shelf as if it was selected.
+func selectedShelf(t testing.TB) *Shelf {
+	o := randomShelf(t)
+	o.readonly = &Shelf{}
+	*o.readonly = *o
+
+	return o
+}
+
+// checkShelf fails t unless the columns of got hold the values of want.
+func checkShelf(t *testing.T, got, want *Shelf) {
+	if !reflect.DeepEqual(got.ID, want.ID) {
+		t.Errorf("id: want %v, got %v", want.ID, got.ID)
+	}
+	if !reflect.DeepEqual(got.Area, want.Area) {
+		t.Errorf("area: want %v, got %v", want.Area, got.Area)
+	}
+	if !reflect.DeepEqual(got.DeletedAt, want.DeletedAt) {
+		t.Errorf("deleted_at: want %v, got %v", want.DeletedAt, got.DeletedAt)
+	}
+}
+
+// shelfRow returns the row of o, for sqlmock to return.
+func shelfRow(t *testing.T, o *Shelf) *sqlmock.Rows {
+	return sqlmock.NewRows(shelfColumns).AddRow(mockRow(t, o.ID, o.Area, o.DeletedAt)...)
+}
+
+func TestShelfInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	mock.ExpectExec(exact("INSERT INTO `shelf` (`id`,`area`,`deleted_at`) VALUES (?,?,?)")).
+		WithArgs(o.ID, o.Area, o.DeletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Insert(db, shelfColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfInsertLastInsertID(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	o.ID = 0
+	mock.ExpectExec(exact("INSERT INTO `shelf` (`area`,`deleted_at`) VALUES (?,?)")).
+		WithArgs(o.Area, o.DeletedAt).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	if err := o.Insert(db); err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != 42 {
+		t.Errorf("want id 42 from the last insert ID, got %v", o.ID)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	mock.ExpectExec(exact("UPDATE `shelf` SET `area`=?,`deleted_at`=? WHERE `id`=?")).
+		WithArgs(o.Area, o.DeletedAt, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfUpdateSelected(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	before := o.Area
+	for reflect.DeepEqual(o.Area, before) {
+		o.Area = randomShelf(t).Area
+	}
+
+	mock.ExpectExec(exact("UPDATE `shelf` SET `area`=? WHERE `id`=? AND `area`=?")).
+		WithArgs(o.Area, o.ID, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "area", Before: before, After: o.Area},
+	})
+
+	// The row now holds the new values, so o is unchanged again
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+	expectationsMet(t, mock)
+}
+
+func TestShelfUpdateStale(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := selectedShelf(t)
+	before := o.Area
+	for reflect.DeepEqual(o.Area, before) {
+		o.Area = randomShelf(t).Area
+	}
+
+	mock.ExpectExec(exact("UPDATE `shelf` SET `area`=? WHERE `id`=? AND `area`=?")).
+		WithArgs(o.Area, o.ID, before).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := o.Update(db); err != ErrStaleObject {
+		t.Errorf("want ErrStaleObject, got %v", err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfUpsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	mock.ExpectExec(exact("INSERT INTO shelf (`id`, `area`, `deleted_at`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `area` = VALUES(`area`),`deleted_at` = VALUES(`deleted_at`)")).
+		WithArgs(o.ID, o.Area, o.DeletedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Upsert(db, nil, shelfColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	o.DeletedAt.Valid = false
+	mock.ExpectExec(exact("UPDATE `shelf` SET `deleted_at`=? WHERE `id`=? AND `deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(db); err != nil {
+		t.Fatal(err)
+	}
+	if !o.DeletedAt.Valid {
+		t.Error("want deleted_at set on a soft deleted shelf")
+	}
+	if ns := o.DeletedAt.Time.Nanosecond(); ns != 0 {
+		t.Errorf("want deleted_at in whole seconds, got %dns more", ns)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfDeleteDeleted(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomShelf(t)
+	o.DeletedAt = null.TimeFrom(time.Now().Add(-time.Hour))
+	deletedAt := o.DeletedAt
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+	if o.DeletedAt != deletedAt {
+		t.Errorf("want deleted_at %v kept, got %v", deletedAt, o.DeletedAt)
+	}
+	if len(rec.changes) != 0 {
+		t.Errorf("want no changeset deleting a deleted shelf, got %d", len(rec.changes))
+	}
+	if o.operation != "" || o.whitelist != nil {
+		t.Errorf("want a deleted shelf left as it was, got operation %q on %v", o.operation, o.whitelist)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelvesSliceDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	o.DeletedAt.Valid = false
+	deleted := randomShelf(t)
+	deleted.DeletedAt = null.TimeFrom(time.Now().Add(-time.Hour))
+	deletedAt := deleted.DeletedAt
+	mock.ExpectExec(`^UPDATE .+ SET .deleted_at.=\? WHERE \(.+\) IN \(.+\) AND .deleted_at. IS NULL$`).
+		WithArgs(sqlmock.AnyArg(), o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := (ShelfSlice{o, deleted}).DeleteAll(db); err != nil {
+		t.Fatal(err)
+	}
+	if !o.DeletedAt.Valid {
+		t.Error("want deleted_at set on a soft deleted shelf")
+	}
+	if deleted.DeletedAt != deletedAt {
+		t.Errorf("want deleted_at %v kept, got %v", deletedAt, deleted.DeletedAt)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelvesQueryDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(exact("UPDATE `shelf` SET (`deleted_at`) = (?) WHERE (`shelf`.`deleted_at` IS NULL);")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Shelves(db).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rows deleted already are left alone, even when the query sees them
+	mock.ExpectExec(exact("UPDATE `shelf` SET (`deleted_at`) = (?) WHERE (`shelf`.`deleted_at` IS NULL);")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Shelves(db, WithDeleted()).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfHardDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	mock.ExpectExec(exact("DELETE FROM `shelf` WHERE `id`=?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.HardDelete(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRestore(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	o.DeletedAt.Valid = true
+	mock.ExpectExec(exact("UPDATE `shelf` SET `deleted_at`=? WHERE `id`=?")).
+		WithArgs(nil, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Restore(db); err != nil {
+		t.Fatal(err)
+	}
+	if o.DeletedAt.Valid {
+		t.Error("want deleted_at cleared on a restored shelf")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelvesExcludeDeleted(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `shelf` WHERE (`shelf`.`deleted_at` IS NULL);")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	if _, err := Shelves(db).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelvesWithDeleted(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `shelf`;")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `shelf` WHERE (`shelf`.`deleted_at` IS NOT NULL);")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	if _, err := Shelves(db, WithDeleted()).Count(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Shelves(db, OnlyDeleted()).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestFindShelf(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	mock.ExpectQuery(exact("select * from `shelf` where `id`=? and `deleted_at` is null")).
+		WithArgs(o.ID).
+		WillReturnRows(shelfRow(t, o))
+
+	found, err := FindShelf(db, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkShelf(t, found, o)
+	if len(found.Whitelist()) != 0 {
+		t.Errorf("want a found shelf unchanged, got changes to %v", found.Whitelist())
+	}
+}
+
+func TestFindShelfCanceled(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	o := randomShelf(t)
+	if _, err := FindShelfContext(ctx, db, o.ID); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelvesCanceled(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Shelves(db).AllContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+	// An executor that takes no context is still checked before the query
+	rec := &changeRecorder{Executor: db}
+	if err := Shelves(rec).DeleteAllContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfExists(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	mock.ExpectQuery(exact("select exists(select 1 from `shelf` where `id`=? and `deleted_at` is null limit 1)")).
+		WithArgs(o.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := ShelfExists(db, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("want the shelf to exist")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfReload(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	row := randomShelf(t)
+	row.ID = o.ID
+	// A soft-deleted row is reloaded as well
+	mock.ExpectQuery(exact("select * from `shelf` where `id`=?")).
+		WithArgs(o.ID).
+		WillReturnRows(shelfRow(t, row))
+
+	if err := o.Reload(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkShelf(t, o, row)
+}
+
+func TestShelfAddBooks(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	rel := randomBook(t)
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=?")).
+		WithArgs(o.ID, rel.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.AddBooks(db, false, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(o.R.Books) != 1 || o.R.Books[0] != rel {
+		t.Error("want rel added to o.R.Books")
+	}
+}
+
+func TestShelfSetBooks(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	rel := randomBook(t)
+	mock.ExpectExec(exact("update `book` set `shelf_id` = null where `shelf_id` = ?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=?")).
+		WithArgs(o.ID, rel.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.SetBooks(db, false, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfSetBooksSelected(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// rel already belongs to o, so the update adding it back checks that the
+	// row was cleared
+	o := randomShelf(t)
+	rel := randomBook(t)
+	rel.ShelfID.Int64 = o.ID
+	rel.ShelfID.Valid = true
+	rel.readonly = &Book{}
+	*rel.readonly = *rel
+	mock.ExpectExec(exact("update `book` set `shelf_id` = null where `shelf_id` = ?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=? AND `shelf_id` IS NULL")).
+		WithArgs(o.ID, rel.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.SetBooks(db, false, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRemoveBooks(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	rel := randomBook(t)
+	mock.ExpectExec(exact("UPDATE `book` SET `shelf_id`=? WHERE `id`=?")).
+		WithArgs(nil, rel.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.RemoveBooks(db, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if rel.ShelfID.Valid {
+		t.Error("want rel.ShelfID set to null")
+	}
+}
+
+func TestShelfWhitelist(t *testing.T) {
+	o := randomShelf(t)
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, shelfColumns) {
+		t.Errorf("want every column of an unselected shelf, got %v", wl)
+	}
+
+	o = selectedShelf(t)
+	if wl := o.Whitelist(); len(wl) != 0 {
+		t.Errorf("want no column of an unchanged shelf, got %v", wl)
+	}
+
+	before := o.Area
+	for reflect.DeepEqual(o.Area, before) {
+		o.Area = randomShelf(t).Area
+	}
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, []string{"area"}) {
+		t.Errorf("want [area], got %v", wl)
+	}
+}
+
+func TestShelfChangesInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomShelf(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Insert(rec, shelfColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "INSERT", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "id", After: o.ID},
+		{Name: "area", After: o.Area},
+		{Name: "deleted_at", After: o.DeletedAt},
+	})
+}
+
+func TestShelfChangesIdentified(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomShelf(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The context of the write names the actor, though rec carries none
+	ctx := WithRequestID(WithActor(context.Background(), "alice"), "req-1")
+	if err := o.InsertContext(ctx, rec, shelfColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(rec.changes) != 1 {
+		t.Fatalf("want 1 changeset, got %d", len(rec.changes))
+	}
+	if ch := rec.changes[0]; ch.Actor != "alice" || ch.RequestID != "req-1" {
+		t.Errorf("want changeset by alice in req-1, got by %q in %q", ch.Actor, ch.RequestID)
+	}
+}
+
+func TestShelfChangesDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	o.DeletedAt = null.Time{}
+	*o.readonly = *o
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// A soft deleted row is recorded with the deleted_at it was given
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "SOFT_DELETE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "deleted_at", Before: null.Time{}, After: o.DeletedAt},
+	})
+}
+
+func TestShelfChangesHardDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	o.Area = randomShelf(t).Area
+	mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.HardDelete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// A deleted row is recorded with the values it was selected with
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "DELETE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "id", Before: o.readonly.ID},
+		{Name: "area", Before: o.readonly.Area},
+		{Name: "deleted_at", Before: o.readonly.DeletedAt},
+	})
+}
+
+func TestShelfChangesRedacted(t *testing.T) {
+	SetSensitive("shelf", "area", Redact)
+	defer SetSensitive("shelf", "area", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	before := o.Area
+	for reflect.DeepEqual(o.Area, before) {
+		o.Area = randomShelf(t).Area
+	}
+
+	// A redacted column is still seen to change, with its values masked
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, []string{"area"}) {
+		t.Errorf("want [area], got %v", wl)
+	}
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "area", Before: RedactedValue, After: RedactedValue},
+	})
+}
+
+func TestShelfChangesRedactedNull(t *testing.T) {
+	SetSensitive("shelf", "area", Redact)
+	defer SetSensitive("shelf", "area", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	for !o.Area.Valid {
+		o = selectedShelf(t)
+	}
+	before := o.Area
+	o.Area.Valid = false
+
+	// Setting a redacted column to NULL reads as such, NULL is no secret
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "area"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{
+		{Name: "area", Before: RedactedValue, After: o.Area},
+	})
+
+	// and reverting it leaves the masked value alone
+	if _, cols, err := shelfFromChangeset(rec.changes[0], true); err != nil || len(cols) != 1 {
+		t.Errorf("want the NULL of area applied, got %v, %v", cols, err)
+	}
+	if _, cols, err := shelfFromChangeset(rec.changes[0], false); err != nil || len(cols) != 0 {
+		t.Errorf("want %v left alone, got %v, %v", before, cols, err)
+	}
+}
+
+func TestShelfChangesExcluded(t *testing.T) {
+	SetSensitive("shelf", "area", Exclude)
+	defer SetSensitive("shelf", "area", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	before := o.Area
+	for reflect.DeepEqual(o.Area, before) {
+		o.Area = randomShelf(t).Area
+	}
+
+	// An excluded column is still written, and left out of the changeset
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "shelf", "UPDATE", map[string]interface{}{
+		"id": o.ID,
+	}, []*ChangeItem{})
+}
+
+func TestShelvesChangesDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	a, b := randomShelf(t), randomShelf(t)
+	a.DeletedAt, b.DeletedAt = null.Time{}, null.Time{}
+	rows := shelfRow(t, a)
+	rows.AddRow(mockRow(t, b.ID, b.Area, b.DeletedAt)...)
+	mock.ExpectQuery("^SELECT").WillReturnRows(rows)
+	mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := Shelves(rec).WithChanges().DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every row deleted is recorded with the values it was selected with
+	expectationsMet(t, mock)
+	if len(rec.changes) != 2 {
+		t.Fatalf("want 2 changesets, got %d", len(rec.changes))
+	}
+	for i, o := range []*Shelf{a, b} {
+		pk := map[string]interface{}{
+			"id": o.ID,
+		}
+		ch := rec.changes[i]
+		if len(ch.Changes) != 1 {
+			t.Fatalf("want 1 change, got %d", len(ch.Changes))
+		}
+		deletedAt, _ := ch.Changes[0].After.(null.Time)
+		checkChangeset(t, ch, "shelf", "SOFT_DELETE", pk, []*ChangeItem{
+			{Name: "deleted_at", Before: null.Time{}, After: deletedAt},
+		})
+		if !deletedAt.Valid {
+			t.Errorf("want deleted_at set, got %v", ch.Changes[0].After)
+		}
+	}
+
+	// Without WithChanges no row is selected, nor recorded
+	rec.changes = nil
+	mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := Shelves(rec).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(rec.changes) != 0 {
+		t.Errorf("want no changeset, got %d", len(rec.changes))
+	}
+}
+
+func TestShelvesChangesUpdateAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	a, b := randomShelf(t), randomShelf(t)
+	after := randomShelf(t).Area
+	rows := shelfRow(t, a)
+	rows.AddRow(mockRow(t, b.ID, b.Area, b.DeletedAt)...)
+	mock.ExpectQuery("^SELECT").WillReturnRows(rows)
+	mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := Shelves(rec).WithChanges().UpdateAll(M{"area": after}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every row updated is recorded, from the value it was selected with
+	expectationsMet(t, mock)
+	if len(rec.changes) != 2 {
+		t.Fatalf("want 2 changesets, got %d", len(rec.changes))
+	}
+	for i, o := range []*Shelf{a, b} {
+		checkChangeset(t, rec.changes[i], "shelf", "UPDATE", map[string]interface{}{
+			"id": o.ID,
+		}, []*ChangeItem{
+			{Name: "area", Before: o.Area, After: after},
+		})
+	}
+}
+
+func TestShelvesWhere(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	count := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"count"}).AddRow(1)
+	}
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `shelf` WHERE (`id` = ?) AND (`shelf`.`deleted_at` IS NULL);")).
+		WithArgs(o.ID).
+		WillReturnRows(count())
+	if _, err := Shelves(db, ShelfWhere.ID.EQ(o.ID)).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	// IN clauses are built after the other where clauses
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `shelf` WHERE (`shelf`.`deleted_at` IS NULL) AND `id` IN (?,?);")).
+		WithArgs(o.ID, o.ID).
+		WillReturnRows(count())
+	if _, err := Shelves(db, ShelfWhere.ID.IN(o.ID, o.ID)).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	o.Area.Valid = false
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM `shelf` WHERE (`area` IS NULL) AND (`shelf`.`deleted_at` IS NULL);")).
+		WillReturnRows(count())
+	if _, err := Shelves(db, ShelfWhere.Area.EQ(o.Area)).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelvesPage(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	rows := shelfRow(t, o)
+	rows.AddRow(mockRow(t, o.ID, o.Area, o.DeletedAt)...)
+
+	// A row more than the page tells there is a next one
+	mock.ExpectQuery(exact("SELECT * FROM `shelf` WHERE (`shelf`.`deleted_at` IS NULL) ORDER BY `id` ASC LIMIT 2;")).
+		WillReturnRows(rows)
+	page, cursors, err := Shelves(db).PageSize(1).Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || cursors.Next == "" || cursors.Prev != "" {
+		t.Fatalf("want the first of several pages, got %d rows and cursors %+v", len(page), cursors)
+	}
+
+	mock.ExpectQuery(exact("SELECT * FROM `shelf` WHERE (`shelf`.`deleted_at` IS NULL) AND ((`id` > ?)) ORDER BY `id` ASC LIMIT 2;")).
+		WithArgs(o.ID).
+		WillReturnRows(shelfRow(t, o))
+	page, cursors, err = Shelves(db).PageSize(1).After(cursors.Next).Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || cursors.Next != "" || cursors.Prev == "" {
+		t.Fatalf("want the last of several pages, got %d rows and cursors %+v", len(page), cursors)
+	}
+
+	if _, _, err := Shelves(db).After("not a cursor").Page(); errors.Cause(err) != ErrInvalidCursor {
+		t.Errorf("want %v, got %v", ErrInvalidCursor, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+// reflectShelfWhitelist and reflectShelfChanges are the reflection based
+// versions of Whitelist and Changes that the generated comparisons replaced,
+// kept to benchmark against.
+func reflectShelfWhitelist(o *Shelf) (wl []string) {
+	if len(o.whitelist) > 0 {
+		return o.whitelist
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(o.readonly))
+	vnew := reflect.Indirect(reflect.ValueOf(o))
+
+	for _, c := range shelfColumns {
+		if f, ok := ShelfFieldMapping[c]; ok {
+			var before, after interface{}
+			if v.IsValid() {
+				before = v.FieldByName(f).Interface()
+			}
+
+			if vnew.IsValid() {
+				after = vnew.FieldByName(f).Interface()
+			}
+			if !reflect.DeepEqual(before, after) || o.operation == "DELETE" {
+				wl = append(wl, c)
+			}
+		}
+	}
+
+	return
+}
+
+func reflectShelfChanges(o *Shelf) *Changeset {
+	ch := &Changeset{Table: "shelf", Changes: []*ChangeItem{}, Operation: o.Operation()}
+	v := reflect.Indirect(reflect.ValueOf(o.readonly))
+	vnew := reflect.Indirect(reflect.ValueOf(o))
+
+	for _, c := range reflectShelfWhitelist(o) {
+		if f, ok := ShelfFieldMapping[c]; ok {
+			var before, after interface{}
+			if v.IsValid() {
+				before = v.FieldByName(f).Interface()
+			}
+
+			if vnew.IsValid() {
+				after = vnew.FieldByName(f).Interface()
+			}
+
+			chitem := &ChangeItem{Name: c, Before: before}
+			if o.operation != "DELETE" {
+				chitem.After = after
+			}
+
+			if !reflect.DeepEqual(chitem.Before, chitem.After) {
+				ch.Changes = append(ch.Changes, chitem)
+			}
+		}
+	}
+
+	return ch
+}
+
+// modifiedShelf returns a selected Shelf with its area changed.
+func modifiedShelf(b *testing.B) *Shelf {
+	o := selectedShelf(b)
+	o.operation = "UPDATE"
+	o.Area = randomShelf(b).Area
+
+	return o
+}
+
+func BenchmarkShelfWhitelist(b *testing.B) {
+	o := modifiedShelf(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Whitelist()
+	}
+}
+
+func BenchmarkShelfWhitelistReflect(b *testing.B) {
+	o := modifiedShelf(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reflectShelfWhitelist(o)
+	}
+}
+
+func BenchmarkShelfChanges(b *testing.B) {
+	o := modifiedShelf(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Changes()
+	}
+}
+
+func BenchmarkShelfChangesReflect(b *testing.B) {
+	o := modifiedShelf(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reflectShelfChanges(o)
+	}
+}
+
+func TestShelfApplyInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := randomShelf(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Insert(rec, shelfColumns...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying the insert inserts the row again, undoing it deletes the row
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(exact("DELETE FROM `shelf` WHERE `id`=?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Apply(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := RevertShelf(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRevertDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.HardDelete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRevertUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	before := o.Area
+	for reflect.DeepEqual(o.Area, before) {
+		o.Area = randomShelf(t).Area
+	}
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "area"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undoing the update sets the column back to the value it was selected with
+	mock.ExpectExec(exact("UPDATE `shelf` SET `area`=? WHERE `id`=?")).
+		WithArgs(before, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRevertUpdateUnknown(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	// An object that was not selected does not know the values it replaced
+	o := randomShelf(t)
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "area"); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := rec.changes[0]
+	if len(ch.Changes) != 1 || !ch.Changes[0].BeforeUnknown {
+		t.Fatalf("want the before value of area unknown, got %+v", ch.Changes)
+	}
+	if err := Revert(db, ch); err == nil {
+		t.Error("want an error reverting an update with unknown before values")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRevertUpsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := randomShelf(t)
+	before := randomShelf(t)
+	pk := map[string]interface{}{
+		"id": o.ID,
+	}
+
+	// Without before values the upsert inserted the row, and is undone by
+	// deleting it
+	inserted := &Changeset{Table: "shelf", PrimaryKey: pk, Operation: "UPSERT", Changes: []*ChangeItem{
+		{Name: "area", After: o.Area},
+	}}
+	mock.ExpectExec(exact("DELETE FROM `shelf` WHERE `id`=?")).
+		WithArgs(o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, inserted); err != nil {
+		t.Fatal(err)
+	}
+
+	// With them it updated the row, and is undone by restoring them
+	updated := &Changeset{Table: "shelf", PrimaryKey: pk, Operation: "UPSERT", Changes: []*ChangeItem{
+		{Name: "area", Before: before.Area, After: o.Area},
+	}}
+	mock.ExpectExec(exact("UPDATE `shelf` SET `area`=? WHERE `id`=?")).
+		WithArgs(before.Area, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, updated); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRevertSoftDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selectedShelf(t)
+	o.DeletedAt = null.Time{}
+	*o.readonly = *o
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undoing a soft delete clears deleted_at
+	mock.ExpectExec(exact("UPDATE `shelf` SET `deleted_at`=? WHERE `id`=?")).
+		WithArgs(nil, o.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func TestShelfRevertOtherTable(t *testing.T) {
+	if err := RevertShelf(nil, &Changeset{Table: "not_shelf"}); err == nil {
+		t.Error("want an error reverting changes of another table")
+	}
+}
@@ -0,0 +1,47 @@
+package outbox
+
+import "fmt"
+
+// dialect holds the statements that differ between databases.
+type dialect struct {
+	insert string
+
+	// selectDue selects the unpublished events due at a time, up to a
+	// number of them.
+	selectDue string
+	published string
+	failed    string
+}
+
+var dialects = map[string]*dialect{
+	"mysql": {
+		insert: "INSERT INTO `" + Table + "` (`topic`, `payload`, `created_at`, `next_attempt_at`) VALUES (?, ?, ?, ?)",
+		selectDue: "SELECT `id`, `topic`, `payload`, `created_at`, `attempts` FROM `" + Table + "` " +
+			"WHERE `published_at` IS NULL AND `next_attempt_at` <= ? ORDER BY `id` LIMIT ?",
+		published: "UPDATE `" + Table + "` SET `published_at` = ? WHERE `id` = ?",
+		failed:    "UPDATE `" + Table + "` SET `attempts` = `attempts` + 1, `last_error` = ?, `next_attempt_at` = ? WHERE `id` = ?",
+	},
+	"postgres": {
+		insert: `INSERT INTO "` + Table + `" ("topic", "payload", "created_at", "next_attempt_at") VALUES ($1, $2, $3, $4)`,
+		selectDue: `SELECT "id", "topic", "payload", "created_at", "attempts" FROM "` + Table + `" ` +
+			`WHERE "published_at" IS NULL AND "next_attempt_at" <= $1 ORDER BY "id" LIMIT $2`,
+		published: `UPDATE "` + Table + `" SET "published_at" = $1 WHERE "id" = $2`,
+		failed:    `UPDATE "` + Table + `" SET "attempts" = "attempts" + 1, "last_error" = $1, "next_attempt_at" = $2 WHERE "id" = $3`,
+	},
+	"mssql": {
+		insert: "INSERT INTO [" + Table + "] ([topic], [payload], [created_at], [next_attempt_at]) VALUES (?, ?, ?, ?)",
+		selectDue: "SELECT [id], [topic], [payload], [created_at], [attempts] FROM [" + Table + "] " +
+			"WHERE [published_at] IS NULL AND [next_attempt_at] <= ? ORDER BY [id] OFFSET 0 ROWS FETCH NEXT ? ROWS ONLY",
+		published: "UPDATE [" + Table + "] SET [published_at] = ? WHERE [id] = ?",
+		failed:    "UPDATE [" + Table + "] SET [attempts] = [attempts] + 1, [last_error] = ?, [next_attempt_at] = ? WHERE [id] = ?",
+	},
+}
+
+func dialectOf(driver string) (*dialect, error) {
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("outbox: unsupported driver %q", driver)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,93 @@
+// Package outbox publishes the Changesets the generated models hand to a
+// changes.Tx as events. The events are written to the outbox table in the
+// transaction that made the changes, and a Relay delivers them to a
+// Publisher once it has committed.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"changes"
+	"models"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// Table is the table Sink writes to and Relay reads from, created by the
+// migrations.
+const Table = "outbox"
+
+// Event is a Changeset on its way to a Publisher.
+type Event struct {
+	ID        int64           `json:"id"`
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	// Attempts counts the earlier, failed, deliveries of the event.
+	Attempts int `json:"attempts"`
+}
+
+// Changeset decodes the Changeset the event carries.
+func (e *Event) Changeset() (*models.Changeset, error) {
+	ch := &models.Changeset{}
+	if err := json.Unmarshal(e.Payload, ch); err != nil {
+		return nil, errors.Wrapf(err, "outbox: unable to decode event %d", e.ID)
+	}
+
+	return ch, nil
+}
+
+// Topic names the events of ch: its table and operation, e.g. book.update.
+func Topic(ch *models.Changeset) string {
+	return ch.Table + "." + strings.ToLower(ch.Operation)
+}
+
+// Sink writes the Changesets of a changes.Tx to the outbox, as an event
+// each, for a Relay to publish once the transaction has committed.
+type Sink struct {
+	d *dialect
+}
+
+var _ changes.Sink = &Sink{}
+
+// NewSink returns a Sink writing to a database of the given database/sql
+// driver: mysql, postgres or mssql.
+func NewSink(driver string) (*Sink, error) {
+	d, err := dialectOf(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sink{d: d}, nil
+}
+
+// Write implements changes.Sink, with an event per Changeset.
+func (s *Sink) Write(ctx context.Context, tx *sql.Tx, chs []*models.Changeset) error {
+	now := time.Now().In(boil.GetLocation())
+	for _, ch := range chs {
+		payload, err := json.Marshal(ch)
+		if err != nil {
+			return errors.Wrap(err, "outbox: unable to encode changeset")
+		}
+
+		args := []interface{}{Topic(ch), string(payload), now, now}
+
+		if boil.DebugMode {
+			fmt.Fprintln(boil.DebugWriter, s.d.insert)
+			fmt.Fprintln(boil.DebugWriter, args)
+		}
+
+		if _, err := tx.ExecContext(ctx, s.d.insert, args...); err != nil {
+			return errors.Wrap(err, "outbox: unable to write event")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package outbox
+
+import (
+	"regexp"
+	"testing"
+
+	"changes"
+	"models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		driver string
+		insert string
+	}{
+		{"mysql", "INSERT INTO `outbox` (`topic`, `payload`, `created_at`, `next_attempt_at`) VALUES (?, ?, ?, ?)"},
+		{"postgres", `INSERT INTO "outbox" ("topic", "payload", "created_at", "next_attempt_at") VALUES ($1, $2, $3, $4)`},
+		{"mssql", "INSERT INTO [outbox] ([topic], [payload], [created_at], [next_attempt_at]) VALUES (?, ?, ?, ?)"},
+	}
+
+	ch := &models.Changeset{
+		Table:      "book",
+		PrimaryKey: map[string]interface{}{"id": 1},
+		Operation:  "UPDATE",
+		Changes:    []*models.ChangeItem{{Name: "name", Before: "a", After: "b"}},
+	}
+	payload := `{"table":"book","primary_key":{"id":1},"changes":[{"name":"name","before":"a","after":"b"}],"operation":"UPDATE","changed_at":"0001-01-01T00:00:00Z"}`
+
+	for _, test := range tests {
+		s, err := NewSink(test.driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(test.insert)).
+			WithArgs("book.update", payload, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := changes.Begin(db, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tx.AddChange(ch)
+		if err := tx.Commit(); err != nil {
+			t.Errorf("%s: %v", test.driver, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", test.driver, err)
+		}
+		db.Close()
+	}
+}
+
+func TestNewSinkUnsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewSink("sqlite3"); err == nil {
+		t.Error("want an error for a driver without a dialect")
+	}
+}
+
+func TestTopic(t *testing.T) {
+	t.Parallel()
+
+	if got := Topic(&models.Changeset{Table: "shelf", Operation: "SOFT_DELETE"}); got != "shelf.soft_delete" {
+		t.Errorf("want shelf.soft_delete, got %s", got)
+	}
+}
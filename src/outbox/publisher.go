@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// ChanPublisher publishes events to a channel, for subscribers in the same
+// process. Publish blocks until the event is received or the context is
+// done, in which case the event is retried later.
+type ChanPublisher chan *Event
+
+// NewChanPublisher returns a ChanPublisher buffering size events.
+func NewChanPublisher(size int) ChanPublisher {
+	return make(ChanPublisher, size)
+}
+
+func (p ChanPublisher) Publish(ctx context.Context, e *Event) error {
+	select {
+	case p <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WriterPublisher publishes events as lines of JSON written to W, for
+// local testing.
+type WriterPublisher struct {
+	W io.Writer
+
+	mu     sync.Mutex
+	closer io.Closer
+}
+
+// NewStdoutPublisher returns a WriterPublisher writing to standard output.
+func NewStdoutPublisher() *WriterPublisher {
+	return &WriterPublisher{W: os.Stdout}
+}
+
+// NewFilePublisher returns a WriterPublisher appending to the file at path,
+// which is created if need be. Close it when done.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WriterPublisher{W: f, closer: f}, nil
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.W.Write(append(b, '\n'))
+	return err
+}
+
+// Close closes the file of a WriterPublisher made by NewFilePublisher.
+func (p *WriterPublisher) Close() error {
+	if p.closer == nil {
+		return nil
+	}
+
+	return p.closer.Close()
+}
@@ -0,0 +1,188 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// Publisher delivers events to the services that react to them.
+type Publisher interface {
+	Publish(ctx context.Context, e *Event) error
+}
+
+// Relay delivers the events of the outbox to a Publisher. An event is only
+// marked as published once Publish returned, so delivery is at least once:
+// an event is published again if the relay stops in between, and
+// subscribers should expect duplicates. A failed event is retried after a
+// backoff, letting the events behind it through in the meantime.
+//
+// One Relay should run per database.
+type Relay struct {
+	DB        *sql.DB
+	Publisher Publisher
+
+	// Driver names the database/sql driver of DB: mysql, postgres or mssql.
+	Driver string
+
+	// BatchSize is the number of events read at a time, 100 by default.
+	BatchSize int
+
+	// Interval is how long an idle relay waits before looking for new
+	// events, a second by default.
+	Interval time.Duration
+
+	// Backoff returns how long to wait before retrying an event that failed
+	// attempts times. By default it doubles from a second up to ten minutes.
+	Backoff func(attempts int) time.Duration
+}
+
+// Backoff waits a second after the first failure and doubles the wait with
+// each one after that, up to ten minutes.
+func Backoff(attempts int) time.Duration {
+	d := time.Second
+	for i := 1; i < attempts && d < 10*time.Minute; i++ {
+		d *= 2
+	}
+	if d > 10*time.Minute {
+		d = 10 * time.Minute
+	}
+
+	return d
+}
+
+// Run relays events until ctx is done. Errors reading or updating the outbox
+// are logged and retried after Interval.
+func (r *Relay) Run(ctx context.Context) error {
+	if _, err := dialectOf(r.Driver); err != nil {
+		return err
+	}
+
+	for {
+		n, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.Printf("outbox: %v", err)
+		}
+
+		if n > 0 && err == nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval()):
+		}
+	}
+}
+
+// RelayOnce publishes one batch of due events, and returns how many it
+// handled, whether or not they were published.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	d, err := dialectOf(r.Driver)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := r.due(ctx, d)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, e := range events {
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+
+		if err := r.publish(ctx, d, e); err != nil {
+			return i, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// due reads the unpublished events whose next attempt has come.
+func (r *Relay) due(ctx context.Context, d *dialect) ([]*Event, error) {
+	now := time.Now().In(boil.GetLocation())
+	args := []interface{}{now, r.batchSize()}
+
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, d.selectDue)
+		fmt.Fprintln(boil.DebugWriter, args)
+	}
+
+	rows, err := r.DB.QueryContext(ctx, d.selectDue, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read outbox")
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		e := &Event{}
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.Topic, &payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, errors.Wrap(err, "unable to read outbox")
+		}
+		e.Payload = payload
+		events = append(events, e)
+	}
+
+	return events, errors.Wrap(rows.Err(), "unable to read outbox")
+}
+
+// publish hands e to the Publisher and records the outcome.
+func (r *Relay) publish(ctx context.Context, d *dialect, e *Event) error {
+	now := time.Now().In(boil.GetLocation())
+
+	if err := r.Publisher.Publish(ctx, e); err != nil {
+		next := now.Add(r.backoff(e.Attempts + 1))
+		log.Printf("outbox: unable to publish event %d, retrying at %s: %v", e.ID, next.Format(time.RFC3339), err)
+		return r.exec(ctx, d.failed, err.Error(), next, e.ID)
+	}
+
+	return r.exec(ctx, d.published, now, e.ID)
+}
+
+func (r *Relay) exec(ctx context.Context, query string, args ...interface{}) error {
+	if boil.DebugMode {
+		fmt.Fprintln(boil.DebugWriter, query)
+		fmt.Fprintln(boil.DebugWriter, args...)
+	}
+
+	_, err := r.DB.ExecContext(ctx, query, args...)
+	return errors.Wrap(err, "unable to update outbox")
+}
+
+func (r *Relay) batchSize() int {
+	if r.BatchSize > 0 {
+		return r.BatchSize
+	}
+
+	return 100
+}
+
+func (r *Relay) interval() time.Duration {
+	if r.Interval > 0 {
+		return r.Interval
+	}
+
+	return time.Second
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	if r.Backoff != nil {
+		return r.Backoff(attempts)
+	}
+
+	return Backoff(attempts)
+}
@@ -0,0 +1,159 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// failingPublisher fails to publish the events of the given IDs, and
+// publishes the others to a ChanPublisher.
+type failingPublisher struct {
+	ChanPublisher
+	fail map[int64]bool
+}
+
+func (p failingPublisher) Publish(ctx context.Context, e *Event) error {
+	if p.fail[e.ID] {
+		return errors.New("broker unavailable")
+	}
+
+	return p.ChanPublisher.Publish(ctx, e)
+}
+
+var eventColumns = []string{"id", "topic", "payload", "created_at", "attempts"}
+
+func expectDue(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectQuery(regexp.QuoteMeta(dialects["mysql"].selectDue)).
+		WithArgs(sqlmock.AnyArg(), 100).
+		WillReturnRows(rows)
+}
+
+func TestRelayOnce(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	expectDue(mock, sqlmock.NewRows(eventColumns).
+		AddRow(1, "book.insert", `{"table":"book"}`, now, 0).
+		AddRow(2, "book.update", `{"table":"book"}`, now, 2).
+		AddRow(3, "shelf.delete", `{"table":"shelf"}`, now, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dialects["mysql"].published)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(dialects["mysql"].failed)).
+		WithArgs("broker unavailable", sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(dialects["mysql"].published)).
+		WithArgs(sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	p := failingPublisher{ChanPublisher: NewChanPublisher(3), fail: map[int64]bool{2: true}}
+	r := &Relay{DB: db, Publisher: p, Driver: "mysql"}
+
+	n, err := r.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("want 3 events handled, got %d", n)
+	}
+
+	// The failed event doesn't hold back the ones behind it
+	for _, id := range []int64{1, 3} {
+		e := <-p.ChanPublisher
+		if e.ID != id {
+			t.Errorf("want event %d published, got %d", id, e.ID)
+		}
+	}
+	if len(p.ChanPublisher) != 0 {
+		t.Errorf("want no other event published, got %d", len(p.ChanPublisher))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// An event published but not marked as such, as when the relay stops in
+// between, is published again: delivery is at least once.
+func TestRelayOnceAtLeastOnce(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	expectDue(mock, sqlmock.NewRows(eventColumns).AddRow(1, "book.insert", `{"table":"book"}`, now, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dialects["mysql"].published)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnError(errors.New("connection lost"))
+	expectDue(mock, sqlmock.NewRows(eventColumns).AddRow(1, "book.insert", `{"table":"book"}`, now, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dialects["mysql"].published)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	p := NewChanPublisher(2)
+	r := &Relay{DB: db, Publisher: p, Driver: "mysql"}
+
+	if _, err := r.RelayOnce(context.Background()); err == nil {
+		t.Fatal("want an error when the event can't be marked as published")
+	}
+	if n, err := r.RelayOnce(context.Background()); err != nil || n != 1 {
+		t.Fatalf("want the event handled again, got %d, %v", n, err)
+	}
+
+	if len(p) != 2 {
+		t.Fatalf("want the event published twice, got %d", len(p))
+	}
+	for i := 0; i < 2; i++ {
+		if e := <-p; e.ID != 1 {
+			t.Errorf("want event 1, got %d", e.ID)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRelayUnsupported(t *testing.T) {
+	t.Parallel()
+
+	r := &Relay{Driver: "sqlite3"}
+	if err := r.Run(context.Background()); err == nil {
+		t.Error("want an error for a driver without a dialect")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{30, 10 * time.Minute},
+	}
+
+	for _, test := range tests {
+		if got := Backoff(test.attempts); got != test.want {
+			t.Errorf("Backoff(%d): want %s, got %s", test.attempts, test.want, got)
+		}
+	}
+}
@@ -1,10 +1,29 @@
 {{- define "relationship_to_one_struct_helper" -}}
 {{- end -}}
 
+{{- /* where_helper_type names the where clause helper of a Go type, such as
+whereHelperNullString for null.String */ -}}
+{{- define "where_helper_type" -}}
+whereHelper
+{{- if eq . "[]byte" -}}
+	Bytes
+{{- else if eq (printf "%.5s" .) "null." -}}
+	Null{{slice . 5}}
+{{- else if eq (printf "%.5s" .) "time." -}}
+	Time{{slice . 5}}
+{{- else if eq (printf "%.6s" .) "types." -}}
+	Types{{slice . 6}}
+{{- else -}}
+	{{titleCase .}}
+{{- end -}}
+{{- end -}}
+
 {{- $dot := . -}}
 {{- $tableNameSingular := .Table.Name | singular -}}
 {{- $modelName := $tableNameSingular | titleCase -}}
 {{- $modelNameCamel := $tableNameSingular | camelCase -}}
+import "context"
+
 // {{$modelName}} is an object representing the database table.
 type {{$modelName}} struct {
 	{{range $column := .Table.Columns -}}
@@ -27,6 +46,98 @@ var {{$modelName}}FieldMapping = map[string]string{
 	{{end -}}
 }
 
+// {{$modelName}}Columns holds the name of every column of {{.Table.Name}}.
+var {{$modelName}}Columns = struct {
+	{{range $column := .Table.Columns -}}
+	{{titleCase $column.Name}} string
+	{{end -}}
+}{
+	{{range $column := .Table.Columns -}}
+	{{titleCase $column.Name}}: "{{$column.Name}}",
+	{{end -}}
+}
+
+{{/* The helpers of a Go type are declared in the file of the first table
+with a column of that type */ -}}
+{{- $types := onceNew -}}
+{{- $before := true -}}
+{{- range $.Tables -}}
+	{{- if eq .Name $dot.Table.Name -}}
+		{{- $before = false -}}
+	{{- end -}}
+	{{- if $before -}}
+		{{- range .Columns -}}
+			{{- $_ := oncePut $types .Type -}}
+		{{- end -}}
+	{{- end -}}
+{{- end -}}
+{{- range $column := .Table.Columns -}}
+{{- if oncePut $types $column.Type -}}
+// {{template "where_helper_type" $column.Type}} builds where clauses on a {{$column.Type}} column.
+type {{template "where_helper_type" $column.Type}} struct{ field string }
+
+{{if $column.Nullable -}}
+// EQ matches x, or NULL when x is null.
+func (w {{template "where_helper_type" $column.Type}}) EQ(x {{$column.Type}}) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNull()
+	}
+	return qm.Where(w.field+" = ?", x)
+}
+
+// NEQ matches values other than x, or not NULL when x is null.
+func (w {{template "where_helper_type" $column.Type}}) NEQ(x {{$column.Type}}) qm.QueryMod {
+	if !x.Valid {
+		return w.IsNotNull()
+	}
+	return qm.Where(w.field+" <> ?", x)
+}
+
+func (w {{template "where_helper_type" $column.Type}}) IsNull() qm.QueryMod    { return qm.Where(w.field + " IS NULL") }
+func (w {{template "where_helper_type" $column.Type}}) IsNotNull() qm.QueryMod { return qm.Where(w.field + " IS NOT NULL") }
+{{- else -}}
+func (w {{template "where_helper_type" $column.Type}}) EQ(x {{$column.Type}}) qm.QueryMod  { return qm.Where(w.field+" = ?", x) }
+func (w {{template "where_helper_type" $column.Type}}) NEQ(x {{$column.Type}}) qm.QueryMod { return qm.Where(w.field+" <> ?", x) }
+{{- end}}
+func (w {{template "where_helper_type" $column.Type}}) LT(x {{$column.Type}}) qm.QueryMod  { return qm.Where(w.field+" < ?", x) }
+func (w {{template "where_helper_type" $column.Type}}) LTE(x {{$column.Type}}) qm.QueryMod { return qm.Where(w.field+" <= ?", x) }
+func (w {{template "where_helper_type" $column.Type}}) GT(x {{$column.Type}}) qm.QueryMod  { return qm.Where(w.field+" > ?", x) }
+func (w {{template "where_helper_type" $column.Type}}) GTE(x {{$column.Type}}) qm.QueryMod { return qm.Where(w.field+" >= ?", x) }
+
+// IN matches any of slice, which must not be empty.
+func (w {{template "where_helper_type" $column.Type}}) IN(slice ...{{$column.Type}}) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" IN ?", values...)
+}
+
+// NIN matches none of slice, which must not be empty.
+func (w {{template "where_helper_type" $column.Type}}) NIN(slice ...{{$column.Type}}) qm.QueryMod {
+	values := make([]interface{}, len(slice))
+	for i, x := range slice {
+		values[i] = x
+	}
+	return qm.WhereIn(w.field+" NOT IN ?", values...)
+}
+
+{{end -}}
+{{- end -}}
+// {{$modelName}}Where holds a where clause helper for every column of
+// {{.Table.Name}}, typed to the column, e.g. {{$modelName}}Where.{{titleCase (index .Table.Columns 0).Name}}.EQ(x).
+// The column names are not qualified with the table name, so that the
+// helpers also apply to relationship queries, which alias the table.
+var {{$modelName}}Where = struct {
+	{{range $column := .Table.Columns -}}
+	{{titleCase $column.Name}} {{template "where_helper_type" $column.Type}}
+	{{end -}}
+}{
+	{{range $column := .Table.Columns -}}
+	{{titleCase $column.Name}}: {{template "where_helper_type" $column.Type}}{field: "{{$column.Name | $dot.Quotes}}"},
+	{{end -}}
+}
+
 {{- if .Table.IsJoinTable -}}
 {{- else}}
 // {{$modelNameCamel}}R is where relationships are stored.
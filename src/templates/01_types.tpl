@@ -2,6 +2,12 @@
 {{else -}}
 {{- $varNameSingular := .Table.Name | singular | camelCase -}}
 {{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
 var (
 	{{$varNameSingular}}Columns               = []string{{"{"}}{{.Table.Columns | columnNames | stringMap .StringFuncs.quoteWrap | join ", "}}{{"}"}}
 	{{$varNameSingular}}ColumnsWithoutDefault = []string{{"{"}}{{.Table.Columns | filterColumnsByDefault false | columnNames | stringMap .StringFuncs.quoteWrap | join ","}}{{"}"}}
@@ -15,11 +21,20 @@ type (
 	{{$tableNameSingular}}Slice []*{{$tableNameSingular}}
 	{{if not .NoHooks -}}
 	// {{$tableNameSingular}}Hook is the signature for custom {{$tableNameSingular}} hook methods
-	{{$tableNameSingular}}Hook func(boil.Executor, *{{$tableNameSingular}}) error
+	{{$tableNameSingular}}Hook func(context.Context, boil.Executor, *{{$tableNameSingular}}) error
 	{{- end}}
 
 	{{$varNameSingular}}Query struct {
 		*queries.Query
+
+		// changes is set by WithChanges
+		changes bool
+		// keyset is the page asked for with SortBy, PageSize, After and Before
+		keyset keyset
+		{{- if $softDelete}}
+		// deleted is which soft-deleted rows the query sees
+		deleted softDeleted
+		{{- end}}
 	}
 )
 
@@ -12,11 +12,16 @@ func (q {{$varNameSingular}}Query) OneP() (*{{$tableNameSingular}}) {
 
 // One returns a single {{$varNameSingular}} record from the query.
 func (q {{$varNameSingular}}Query) One() (*{{$tableNameSingular}}, error) {
+	return q.OneContext(context.Background())
+}
+
+// OneContext returns a single {{$varNameSingular}} record from the query, canceled with ctx.
+func (q {{$varNameSingular}}Query) OneContext(ctx context.Context) (*{{$tableNameSingular}}, error) {
 	o := &{{$tableNameSingular}}{}
 
 	queries.SetLimit(q.Query, 1)
 
-	err := q.Bind(o)
+	err := withContext(ctx, q.Query).Bind(o)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, sql.ErrNoRows
@@ -25,7 +30,7 @@ func (q {{$varNameSingular}}Query) One() (*{{$tableNameSingular}}, error) {
 	}
 
 	{{if not .NoHooks -}}
-	if err := o.doAfterSelectHooks(queries.GetExecutor(q.Query)); err != nil {
+	if err := o.doAfterSelectHooks(ctx, queries.GetExecutor(q.Query)); err != nil {
 		return o, err
 	}
 	{{- end}}
@@ -45,9 +50,14 @@ func (q {{$varNameSingular}}Query) AllP() {{$tableNameSingular}}Slice {
 
 // All returns all {{$tableNameSingular}} records from the query.
 func (q {{$varNameSingular}}Query) All() ({{$tableNameSingular}}Slice, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all {{$tableNameSingular}} records from the query, canceled with ctx.
+func (q {{$varNameSingular}}Query) AllContext(ctx context.Context) ({{$tableNameSingular}}Slice, error) {
 	var o {{$tableNameSingular}}Slice
 
-	err := q.Bind(&o)
+	err := withContext(ctx, q.Query).Bind(&o)
 	if err != nil {
 		return nil, errors.Wrap(err, "{{.PkgName}}: failed to assign all query results to {{$tableNameSingular}} slice")
 	}
@@ -55,7 +65,7 @@ func (q {{$varNameSingular}}Query) All() ({{$tableNameSingular}}Slice, error) {
 	{{if not .NoHooks -}}
 	if len({{$varNameSingular}}AfterSelectHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doAfterSelectHooks(queries.GetExecutor(q.Query)); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, queries.GetExecutor(q.Query)); err != nil {
 				return o, err
 			}
 		}
@@ -77,12 +87,17 @@ func (q {{$varNameSingular}}Query) CountP() int64 {
 
 // Count returns the count of all {{$tableNameSingular}} records in the query.
 func (q {{$varNameSingular}}Query) Count() (int64, error) {
+	return q.CountContext(context.Background())
+}
+
+// CountContext returns the count of all {{$tableNameSingular}} records in the query, canceled with ctx.
+func (q {{$varNameSingular}}Query) CountContext(ctx context.Context) (int64, error) {
 	var count int64
 
 	queries.SetSelect(q.Query, nil)
 	queries.SetCount(q.Query)
 
-	err := q.Query.QueryRow().Scan(&count)
+	err := withContext(ctx, q.Query).QueryRow().Scan(&count)
 	if err != nil {
 		return 0, errors.Wrap(err, "{{.PkgName}}: failed to count {{.Table.Name}} rows")
 	}
@@ -102,12 +117,17 @@ func (q {{$varNameSingular}}Query) ExistsP() bool {
 
 // Exists checks if the row exists in the table.
 func (q {{$varNameSingular}}Query) Exists() (bool, error) {
+	return q.ExistsContext(context.Background())
+}
+
+// ExistsContext checks if the row exists in the table, canceled with ctx.
+func (q {{$varNameSingular}}Query) ExistsContext(ctx context.Context) (bool, error) {
 	var count int64
 
 	queries.SetCount(q.Query)
 	queries.SetLimit(q.Query, 1)
 
-	err := q.Query.QueryRow().Scan(&count)
+	err := withContext(ctx, q.Query).QueryRow().Scan(&count)
 	if err != nil {
 		return false, errors.Wrap(err, "{{.PkgName}}: failed to check if {{.Table.Name}} exists")
 	}
@@ -7,8 +7,14 @@
 		{{- $arg := printf "maybe%s" $txt.LocalTable.NameGo -}}
 		{{- $slice := printf "%sSlice" $txt.LocalTable.NameGo}}
 // Load{{$txt.Function.Name}} allows an eager lookup of values, cached into the
-// loaded structs of the objects.
+// loaded structs of the objects. The lookup is canceled with the context of
+// e, when it carries one.
 func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singular bool, {{$arg}} interface{}) error {
+	return {{$varNameSingular}}L{}.Load{{$txt.Function.Name}}Context(contextOf(e), e, singular, {{$arg}})
+}
+
+// Load{{$txt.Function.Name}}Context is Load{{$txt.Function.Name}}, with the lookup canceled with ctx.
+func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}Context(ctx context.Context, e boil.Executor, singular bool, {{$arg}} interface{}) error {
 	var slice []*{{$txt.LocalTable.NameGo}}
 	var object *{{$txt.LocalTable.NameGo}}
 
@@ -44,7 +50,7 @@ func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singula
 		fmt.Fprintf(boil.DebugWriter, "%s\n%v\n", query, args)
 	}
 
-	results, err := e.Query(query, args...)
+	results, err := queryContext(ctx, e, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to eager load {{$txt.ForeignTable.NameGo}}")
 	}
@@ -58,7 +64,7 @@ func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singula
 	{{if not $dot.NoHooks -}}
 	if len({{$varNameSingular}}AfterSelectHooks) != 0 {
 		for _, obj := range resultSlice {
-			if err := obj.doAfterSelectHooks(e); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
 				return err
 			}
 		}
@@ -4,12 +4,24 @@
 	{{- range .Table.ToManyRelationships -}}
 		{{- $varNameSingular := $dot.Table.Name | singular | camelCase -}}
 		{{- $txt := txtsFromToMany $dot.Tables $dot.Table . -}}
+		{{- $softDelete := false -}}
+		{{- range (getTable $dot.Tables .ForeignTable).Columns -}}
+			{{- if and (eq .Name "deleted_at") .Nullable -}}
+				{{- $softDelete = true -}}
+			{{- end -}}
+		{{- end -}}
 		{{- $arg := printf "maybe%s" $txt.LocalTable.NameGo -}}
 		{{- $slice := printf "%sSlice" $txt.LocalTable.NameGo -}}
 		{{- $schemaForeignTable := .ForeignTable | $dot.SchemaTable}}
 // Load{{$txt.Function.Name}} allows an eager lookup of values, cached into the
-// loaded structs of the objects.
+// loaded structs of the objects. The lookup is canceled with the context of
+// e, when it carries one.
 func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singular bool, {{$arg}} interface{}) error {
+	return {{$varNameSingular}}L{}.Load{{$txt.Function.Name}}Context(contextOf(e), e, singular, {{$arg}})
+}
+
+// Load{{$txt.Function.Name}}Context is Load{{$txt.Function.Name}}, with the lookup canceled with ctx.
+func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}Context(ctx context.Context, e boil.Executor, singular bool, {{$arg}} interface{}) error {
 	var slice []*{{$txt.LocalTable.NameGo}}
 	var object *{{$txt.LocalTable.NameGo}}
 
@@ -39,12 +51,12 @@ func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singula
 		{{if .ToJoinTable -}}
 			{{- $schemaJoinTable := .JoinTable | $dot.SchemaTable -}}
 	query := fmt.Sprintf(
-		"select {{id 0 | $dot.Quotes}}.*, {{id 1 | $dot.Quotes}}.{{.JoinLocalColumn | $dot.Quotes}} from {{$schemaForeignTable}} as {{id 0 | $dot.Quotes}} inner join {{$schemaJoinTable}} as {{id 1 | $dot.Quotes}} on {{id 0 | $dot.Quotes}}.{{.ForeignColumn | $dot.Quotes}} = {{id 1 | $dot.Quotes}}.{{.JoinForeignColumn | $dot.Quotes}} where {{id 1 | $dot.Quotes}}.{{.JoinLocalColumn | $dot.Quotes}} in (%s)",
+		"select {{id 0 | $dot.Quotes}}.*, {{id 1 | $dot.Quotes}}.{{.JoinLocalColumn | $dot.Quotes}} from {{$schemaForeignTable}} as {{id 0 | $dot.Quotes}} inner join {{$schemaJoinTable}} as {{id 1 | $dot.Quotes}} on {{id 0 | $dot.Quotes}}.{{.ForeignColumn | $dot.Quotes}} = {{id 1 | $dot.Quotes}}.{{.JoinForeignColumn | $dot.Quotes}} where {{id 1 | $dot.Quotes}}.{{.JoinLocalColumn | $dot.Quotes}} in (%s){{if $softDelete}} and {{id 0 | $dot.Quotes}}.{{"deleted_at" | $dot.Quotes}} is null{{end}}",
 		strmangle.Placeholders(dialect.IndexPlaceholders, count, 1, 1),
 	)
 		{{else -}}
 	query := fmt.Sprintf(
-		"select * from {{$schemaForeignTable}} where {{.ForeignColumn | $dot.Quotes}} in (%s)",
+		"select * from {{$schemaForeignTable}} where {{.ForeignColumn | $dot.Quotes}} in (%s){{if $softDelete}} and {{"deleted_at" | $dot.Quotes}} is null{{end}}",
 		strmangle.Placeholders(dialect.IndexPlaceholders, count, 1, 1),
 	)
 		{{end -}}
@@ -53,7 +65,7 @@ func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singula
 		fmt.Fprintf(boil.DebugWriter, "%s\n%v\n", query, args)
 	}
 
-	results, err := e.Query(query, args...)
+	results, err := queryContext(ctx, e, query, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to eager load {{.ForeignTable}}")
 	}
@@ -90,7 +102,7 @@ func ({{$varNameSingular}}L) Load{{$txt.Function.Name}}(e boil.Executor, singula
 	{{if not $dot.NoHooks -}}
 	if len({{.ForeignTable | singular | camelCase}}AfterSelectHooks) != 0 {
 		for _, obj := range resultSlice {
-			if err := obj.doAfterSelectHooks(e); err != nil {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
 				return err
 			}
 		}
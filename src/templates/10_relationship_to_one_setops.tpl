@@ -10,9 +10,14 @@
 // Sets o.R.{{$txt.Function.Name}} to related.
 // Adds o to related.R.{{$txt.Function.ForeignName}}.
 func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executor, insert bool, related *{{$txt.ForeignTable.NameGo}}) error {
+	return o.Set{{$txt.Function.Name}}Context(context.Background(), exec, insert, related)
+}
+
+// Set{{$txt.Function.Name}}Context is Set{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, insert bool, related *{{$txt.ForeignTable.NameGo}}) error {
 	var err error
 	if insert {
-		if err = related.Insert(exec); err != nil {
+		if err = related.InsertContext(ctx, exec); err != nil {
 			return errors.Wrap(err, "failed to insert into foreign table")
 		}
 	}
@@ -29,14 +34,18 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	if _, err = exec.Exec(updateQuery, values...); err != nil {
+	if _, err = execContext(ctx, exec, updateQuery, values...); err != nil {
 		return errors.Wrap(err, "failed to update local table")
 	}
 
 	o.{{$txt.Function.LocalAssignment}} = related.{{$txt.Function.ForeignAssignment}}
 	{{if .Nullable -}}
 	o.{{$txt.LocalTable.ColumnNameGo}}.Valid = true
-	{{- end}}
+	{{end -}}
+	// The row now holds the new key, later updates are checked against it
+	if o.readonly != nil {
+		o.readonly.{{$txt.LocalTable.ColumnNameGo}} = o.{{$txt.LocalTable.ColumnNameGo}}
+	}
 
 	if o.R == nil {
 		o.R = &{{$varNameSingular}}R{
@@ -72,10 +81,15 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 // Sets o.R.{{$txt.Function.Name}} to nil.
 // Removes o from all passed in related items' relationships struct (Optional).
 func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}(exec boil.Executor, related *{{$txt.ForeignTable.NameGo}}) error {
+	return o.Remove{{$txt.Function.Name}}Context(context.Background(), exec, related)
+}
+
+// Remove{{$txt.Function.Name}}Context is Remove{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, related *{{$txt.ForeignTable.NameGo}}) error {
 	var err error
 
 	o.{{$txt.LocalTable.ColumnNameGo}}.Valid = false
-	if err = o.Update(exec, "{{.Column}}"); err != nil {
+	if err = o.UpdateContext(ctx, exec, "{{.Column}}"); err != nil {
 		o.{{$txt.LocalTable.ColumnNameGo}}.Valid = true
 		return errors.Wrap(err, "failed to update local table")
 	}
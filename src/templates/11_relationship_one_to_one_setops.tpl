@@ -11,6 +11,11 @@
 // Sets o.R.{{$txt.Function.Name}} to related.
 // Adds o to related.R.{{$txt.Function.ForeignName}}.
 func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executor, insert bool, related *{{$txt.ForeignTable.NameGo}}) error {
+	return o.Set{{$txt.Function.Name}}Context(context.Background(), exec, insert, related)
+}
+
+// Set{{$txt.Function.Name}}Context is Set{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, insert bool, related *{{$txt.ForeignTable.NameGo}}) error {
 	var err error
 
 	if insert {
@@ -19,7 +24,7 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 		related.{{$txt.ForeignTable.ColumnNameGo}}.Valid = true
 		{{- end}}
 
-		if err = related.Insert(exec); err != nil {
+		if err = related.InsertContext(ctx, exec); err != nil {
 			return errors.Wrap(err, "failed to insert into foreign table")
 		}
 	} else {
@@ -35,14 +40,18 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 			fmt.Fprintln(boil.DebugWriter, values)
 		}
 
-		if _, err = exec.Exec(updateQuery, values...); err != nil {
+		if _, err = execContext(ctx, exec, updateQuery, values...); err != nil {
 			return errors.Wrap(err, "failed to update foreign table")
 		}
 
 		related.{{$txt.Function.ForeignAssignment}} = o.{{$txt.Function.LocalAssignment}}
 		{{if .ForeignColumnNullable -}}
 		related.{{$txt.ForeignTable.ColumnNameGo}}.Valid = true
-		{{- end}}
+		{{end -}}
+		// The row now holds the new key, later updates are checked against it
+		if related.readonly != nil {
+			related.readonly.{{$txt.ForeignTable.ColumnNameGo}} = related.{{$txt.ForeignTable.ColumnNameGo}}
+		}
 	}
 
 
@@ -69,10 +78,15 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 // Sets o.R.{{$txt.Function.Name}} to nil.
 // Removes o from all passed in related items' relationships struct (Optional).
 func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}(exec boil.Executor, related *{{$txt.ForeignTable.NameGo}}) error {
+	return o.Remove{{$txt.Function.Name}}Context(context.Background(), exec, related)
+}
+
+// Remove{{$txt.Function.Name}}Context is Remove{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, related *{{$txt.ForeignTable.NameGo}}) error {
 	var err error
 
 	related.{{$txt.ForeignTable.ColumnNameGo}}.Valid = false
-	if err = related.Update(exec, "{{.ForeignColumn}}"); err != nil {
+	if err = related.UpdateContext(ctx, exec, "{{.ForeignColumn}}"); err != nil {
 		related.{{$txt.ForeignTable.ColumnNameGo}}.Valid = true
 		return errors.Wrap(err, "failed to update local table")
 	}
@@ -11,6 +11,11 @@
 // Appends related to o.R.{{$txt.Function.Name}}.
 // Sets related.R.{{$txt.Function.ForeignName}} appropriately.
 func (o *{{$txt.LocalTable.NameGo}}) Add{{$txt.Function.Name}}(exec boil.Executor, insert bool, related ...*{{$txt.ForeignTable.NameGo}}) error {
+	return o.Add{{$txt.Function.Name}}Context(context.Background(), exec, insert, related...)
+}
+
+// Add{{$txt.Function.Name}}Context is Add{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Add{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, insert bool, related ...*{{$txt.ForeignTable.NameGo}}) error {
 	var err error
 	for _, rel := range related {
 		{{if not .ToJoinTable -}}
@@ -20,11 +25,11 @@ func (o *{{$txt.LocalTable.NameGo}}) Add{{$txt.Function.Name}}(exec boil.Executo
 			{{end -}}
 		{{end -}}
 		if insert {
-			if err = rel.Insert(exec); err != nil {
+			if err = rel.InsertContext(ctx, exec); err != nil {
 				return errors.Wrap(err, "failed to insert into foreign table")
 			}
 		}{{if not .ToJoinTable}} else {
-			if err = rel.Update(exec, "{{.ForeignColumn}}"); err != nil {
+			if err = rel.UpdateContext(ctx, exec, "{{.ForeignColumn}}"); err != nil {
 				return errors.Wrap(err, "failed to update foreign table")
 			}
 		}{{end -}}
@@ -40,7 +45,7 @@ func (o *{{$txt.LocalTable.NameGo}}) Add{{$txt.Function.Name}}(exec boil.Executo
 			fmt.Fprintln(boil.DebugWriter, values)
 		}
 
-		_, err = exec.Exec(query, values...)
+		_, err = execContext(ctx, exec, query, values...)
 		if err != nil {
 			return errors.Wrap(err, "failed to insert into join table")
 		}
@@ -88,6 +93,11 @@ func (o *{{$txt.LocalTable.NameGo}}) Add{{$txt.Function.Name}}(exec boil.Executo
 // Replaces o.R.{{$txt.Function.Name}} with related.
 // Sets related.R.{{$txt.Function.ForeignName}}'s {{$txt.Function.Name}} accordingly.
 func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executor, insert bool, related ...*{{$txt.ForeignTable.NameGo}}) error {
+	return o.Set{{$txt.Function.Name}}Context(context.Background(), exec, insert, related...)
+}
+
+// Set{{$txt.Function.Name}}Context is Set{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, insert bool, related ...*{{$txt.ForeignTable.NameGo}}) error {
 	{{if .ToJoinTable -}}
 	query := "delete from {{.JoinTable | $dot.SchemaTable}} where {{.JoinLocalColumn | $dot.Quotes}} = {{if $dot.Dialect.IndexPlaceholders}}$1{{else}}?{{end}}"
 	values := []interface{}{{"{"}}o.{{$txt.LocalTable.ColumnNameGo}}}
@@ -100,7 +110,7 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	_, err := exec.Exec(query, values...)
+	_, err := execContext(ctx, exec, query, values...)
 	if err != nil {
 		return errors.Wrap(err, "failed to remove relationships before set")
 	}
@@ -109,9 +119,11 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 	remove{{$txt.Function.Name}}From{{$txt.Function.ForeignName}}Slice(o, related)
 	o.R.{{$txt.Function.Name}} = nil
 	{{else -}}
+	cleared := append([]*{{$txt.ForeignTable.NameGo}}{}, related...)
 	if o.R != nil {
 		for _, rel := range o.R.{{$txt.Function.Name}} {
 			rel.{{$txt.ForeignTable.ColumnNameGo}}.Valid = false
+			cleared = append(cleared, rel)
 			if rel.R == nil {
 				continue
 			}
@@ -121,15 +133,36 @@ func (o *{{$txt.LocalTable.NameGo}}) Set{{$txt.Function.Name}}(exec boil.Executo
 
 		o.R.{{$txt.Function.Name}} = nil
 	}
+
+	// The rows that pointed at o no longer do, later updates of them, such as
+	// the ones adding related back, are checked against that
+	for _, rel := range cleared {
+		if rel.readonly == nil || !rel.readonly.{{$txt.ForeignTable.ColumnNameGo}}.Valid {
+			continue
+		}
+		{{if $txt.Function.UsesBytes -}}
+		if 0 == bytes.Compare(o.{{$txt.Function.LocalAssignment}}, rel.readonly.{{$txt.Function.ForeignAssignment}}) {
+		{{else -}}
+		if o.{{$txt.Function.LocalAssignment}} == rel.readonly.{{$txt.Function.ForeignAssignment}} {
+		{{end -}}
+			rel.readonly.{{$txt.ForeignTable.ColumnNameGo}}.Valid = false
+		}
+	}
+
 	{{end -}}
 
-	return o.Add{{$txt.Function.Name}}(exec, insert, related...)
+	return o.Add{{$txt.Function.Name}}Context(ctx, exec, insert, related...)
 }
 
 // Remove{{$txt.Function.Name}} relationships from objects passed in.
 // Removes related items from R.{{$txt.Function.Name}} (uses pointer comparison, removal does not keep order)
 // Sets related.R.{{$txt.Function.ForeignName}}.
 func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}(exec boil.Executor, related ...*{{$txt.ForeignTable.NameGo}}) error {
+	return o.Remove{{$txt.Function.Name}}Context(context.Background(), exec, related...)
+}
+
+// Remove{{$txt.Function.Name}}Context is Remove{{$txt.Function.Name}}, with its queries canceled with ctx.
+func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}Context(ctx context.Context, exec boil.Executor, related ...*{{$txt.ForeignTable.NameGo}}) error {
 	var err error
 	{{if .ToJoinTable -}}
 	query := fmt.Sprintf(
@@ -143,7 +176,7 @@ func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}(exec boil.Exec
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	_, err = exec.Exec(query, values...)
+	_, err = execContext(ctx, exec, query, values...)
 	if err != nil {
 		return errors.Wrap(err, "failed to remove relationships before set")
 	}
@@ -155,7 +188,7 @@ func (o *{{$txt.LocalTable.NameGo}}) Remove{{$txt.Function.Name}}(exec boil.Exec
 			rel.R.{{$txt.Function.ForeignName}} = nil
 		}
 		{{end -}}
-		if err = rel.Update(exec, "{{.ForeignColumn}}"); err != nil {
+		if err = rel.UpdateContext(ctx, exec, "{{.ForeignColumn}}"); err != nil {
 			return err
 		}
 	}
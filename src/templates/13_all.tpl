@@ -1,12 +1,35 @@
 {{- $tableNamePlural := .Table.Name | plural | titleCase -}}
-{{- $varNameSingular := .Table.Name | singular | camelCase}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end}}
 // {{$tableNamePlural}}G retrieves all records.
 func {{$tableNamePlural}}G(mods ...qm.QueryMod) {{$varNameSingular}}Query {
 	return {{$tableNamePlural}}(boil.GetDB(), mods...)
 }
 
 // {{$tableNamePlural}} retrieves all the records using an executor.
+{{- if $softDelete}}
+// Soft-deleted records are left out, unless asked for with WithDeleted or
+// OnlyDeleted.
+{{- end}}
 func {{$tableNamePlural}}(exec boil.Executor, mods ...qm.QueryMod) {{$varNameSingular}}Query {
-	mods = append(mods, qm.From("{{.Table.Name | .SchemaTable}}"))
-	return {{$varNameSingular}}Query{NewQuery(exec, mods...)}
+	mods = append(mods, qm.From("{{$schemaTable}}"))
+	{{- if $softDelete}}
+	q, deleted := newSoftDeleteQuery(exec, mods...)
+	switch deleted {
+	case excludeDeleted:
+		queries.AppendWhere(q, "{{$schemaTable}}.{{"deleted_at" | .Quotes}} IS NULL")
+	case onlyDeleted:
+		queries.AppendWhere(q, "{{$schemaTable}}.{{"deleted_at" | .Quotes}} IS NOT NULL")
+	}
+
+	return {{$varNameSingular}}Query{Query: q, deleted: deleted}
+	{{- else}}
+	return {{$varNameSingular}}Query{Query: NewQuery(exec, mods...)}
+	{{- end}}
 }
@@ -2,6 +2,12 @@
 {{- $varNameSingular := .Table.Name | singular | camelCase -}}
 {{- $colDefs := sqlColDefinitions .Table.Columns .Table.PKey.Columns -}}
 {{- $pkNames := $colDefs.Names | stringMap .StringFuncs.camelCase -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
 {{- $pkArgs := joinSlices " " $pkNames $colDefs.Types | join ", "}}
 // Find{{$tableNameSingular}}G retrieves a single record by ID.
 func Find{{$tableNameSingular}}G({{$pkArgs}}, selectCols ...string) (*{{$tableNameSingular}}, error) {
@@ -20,7 +26,23 @@ func Find{{$tableNameSingular}}GP({{$pkArgs}}, selectCols ...string) *{{$tableNa
 
 // Find{{$tableNameSingular}} retrieves a single record by ID with an executor.
 // If selectCols is empty Find will return all columns.
+{{- if $softDelete}}
+// A soft-deleted record is not found.
+{{- end}}
 func Find{{$tableNameSingular}}(exec boil.Executor, {{$pkArgs}}, selectCols ...string) (*{{$tableNameSingular}}, error) {
+	return Find{{$tableNameSingular}}Context(context.Background(), exec, {{$pkNames | join ", "}}, selectCols...)
+}
+
+// Find{{$tableNameSingular}}Context is Find{{$tableNameSingular}}, with the query canceled with ctx.
+func Find{{$tableNameSingular}}Context(ctx context.Context, exec boil.Executor, {{$pkArgs}}, selectCols ...string) (*{{$tableNameSingular}}, error) {
+	{{- if $softDelete}}
+	return find{{$tableNameSingular}}(ctx, exec, false, {{$pkNames | join ", "}}, selectCols...)
+}
+
+// find{{$tableNameSingular}} is Find{{$tableNameSingular}}Context, also finding a soft-deleted record when
+// deleted is set.
+func find{{$tableNameSingular}}(ctx context.Context, exec boil.Executor, deleted bool, {{$pkArgs}}, selectCols ...string) (*{{$tableNameSingular}}, error) {
+	{{- end}}
 	{{$varNameSingular}}Obj := &{{$tableNameSingular}}{}
   {{$varNameSingular}}Obj.readonly = &{{$tableNameSingular}}{}
 
@@ -31,10 +53,15 @@ func Find{{$tableNameSingular}}(exec boil.Executor, {{$pkArgs}}, selectCols ...s
 	query := fmt.Sprintf(
 		"select %s from {{.Table.Name | .SchemaTable}} where {{if .Dialect.IndexPlaceholders}}{{whereClause .LQ .RQ 1 .Table.PKey.Columns}}{{else}}{{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{end}}", sel,
 	)
+	{{- if $softDelete}}
+	if !deleted {
+		query += " and {{"deleted_at" | .Quotes}} is null"
+	}
+	{{- end}}
 
 	q := queries.Raw(exec, query, {{$pkNames | join ", "}})
 
-	err := q.Bind({{$varNameSingular}}Obj)
+	err := withContext(ctx, q).Bind({{$varNameSingular}}Obj)
 	if err != nil {
 		if errors.Cause(err) == sql.ErrNoRows {
 			return nil, sql.ErrNoRows
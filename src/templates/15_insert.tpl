@@ -28,6 +28,11 @@ func (o *{{$tableNameSingular}}) InsertP(exec boil.Executor, whitelist ... strin
 // - All columns without a default value are included (i.e. name, age)
 // - All columns with a default, but non-zero are included (i.e. health = 75)
 func (o *{{$tableNameSingular}}) Insert(exec boil.Executor, whitelist ... string) error {
+	return o.InsertContext(context.Background(), exec, whitelist...)
+}
+
+// InsertContext is Insert, with its queries canceled with ctx.
+func (o *{{$tableNameSingular}}) InsertContext(ctx context.Context, exec boil.Executor, whitelist ... string) error {
 	if o == nil {
 		return errors.New("{{.PkgName}}: no {{.Table.Name}} provided for insertion")
 	}
@@ -38,7 +43,7 @@ func (o *{{$tableNameSingular}}) Insert(exec boil.Executor, whitelist ... string
 	{{- template "timestamp_insert_helper" . }}
 
 	{{if not .NoHooks -}}
-	if err := o.doBeforeInsertHooks(exec); err != nil {
+	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
 		return err
 	}
 	{{- end}}
@@ -89,9 +94,9 @@ func (o *{{$tableNameSingular}}) Insert(exec boil.Executor, whitelist ... string
 	{{if .UseLastInsertID -}}
 	{{- $canLastInsertID := .Table.CanLastInsertID -}}
 	{{if $canLastInsertID -}}
-	result, err := exec.Exec(cache.query, vals...)
+	result, err := execContext(ctx, exec, cache.query, vals...)
 	{{else -}}
-	_, err = exec.Exec(cache.query, vals...)
+	_, err = execContext(ctx, exec, cache.query, vals...)
 	{{- end}}
 	if err != nil {
 		return errors.Wrap(err, "{{.PkgName}}: unable to insert into {{.Table.Name}}")
@@ -132,15 +137,15 @@ func (o *{{$tableNameSingular}}) Insert(exec boil.Executor, whitelist ... string
 		fmt.Fprintln(boil.DebugWriter, identifierCols...)
 	}
 
-	err = exec.QueryRow(cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+	err = queryRowContext(ctx, exec, cache.retQuery, identifierCols...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
 	if err != nil {
 		return errors.Wrap(err, "{{.PkgName}}: unable to populate default values for {{.Table.Name}}")
 	}
 	{{else}}
 	if len(cache.retMapping) != 0 {
-		err = exec.QueryRow(cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
+		err = queryRowContext(ctx, exec, cache.query, vals...).Scan(queries.PtrsFromMapping(value, cache.retMapping)...)
 	} else {
-		_, err = exec.Exec(cache.query, vals...)
+		_, err = execContext(ctx, exec, cache.query, vals...)
 	}
 
 	if err != nil {
@@ -158,7 +163,7 @@ CacheNoHooks:
 	}
 
 	{{if not .NoHooks -}}
-	return o.doAfterInsertHooks(exec)
+	return o.doAfterInsertHooks(ctx, exec)
 	{{- else -}}
 	return nil
 	{{- end}}
@@ -25,27 +25,89 @@ func (o *{{$tableNameSingular}}) UpdateP(exec boil.Executor, whitelist ... strin
 	}
 }
 
+{{$lockColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "version") (not .Nullable) -}}
+		{{- $lockColumn = "version" -}}
+	{{- end -}}
+{{- end -}}
+{{- if not $lockColumn -}}
+	{{- range .Table.Columns -}}
+		{{- if eq .Name "updated_at" -}}
+			{{- $lockColumn = "updated_at" -}}
+		{{- end -}}
+	{{- end -}}
+{{- end -}}
 // Update uses an executor to update the {{$tableNameSingular}}.
 // Whitelist behavior: If a whitelist is provided, only the columns given are updated.
 // No whitelist behavior: Without a whitelist, columns are inferred by the following rules:
-// - All columns are inferred to start with
+// - Only the columns changed since the object was selected are updated
+// - An object that was not selected has all columns inferred to start with
 // - All primary keys are subtracted from this set
+// An object that was selected and has no changes is not updated at all.
 // Update does not automatically update the record in case of default values. Use .Reload()
 // to refresh the records.
+//
+{{if eq $lockColumn "version" -}}
+// Concurrency: the update only applies while the row still has the version
+// o holds, and increments it. ErrStaleObject is returned when it does not.
+{{- else if eq $lockColumn "updated_at" -}}
+// Concurrency: the update only applies while the row still has the updated_at
+// o holds. ErrStaleObject is returned when it does not.
+{{- else -}}
+// Concurrency: for a selected object, the update only applies while the
+// updated columns still hold the values they were selected with.
+// ErrStaleObject is returned when they do not.
+{{- end}} With MySQL, connect with
+// clientFoundRows=true so that rows updated to the values they already had
+// are not taken for stale ones.
 func (o *{{$tableNameSingular}}) Update(exec boil.Executor, whitelist ... string) error {
+	return o.UpdateContext(context.Background(), exec, whitelist...)
+}
+
+// UpdateContext is Update, with its queries canceled with ctx.
+func (o *{{$tableNameSingular}}) UpdateContext(ctx context.Context, exec boil.Executor, whitelist ... string) error {
 	o.whitelist = whitelist
 	whitelist = o.Whitelist()
+	if o.readonly != nil && len(o.whitelist) == 0 && len(whitelist) == 0 {
+		return nil
+	}
 
   o.operation = "UPDATE"
 
+	{{if eq $lockColumn "version" -}}
+	lockCols := []string{"version"}
+	lockValues := []interface{}{o.Version}
+	o.Version++
+	whitelist = strmangle.SetMerge(whitelist, lockCols)
+	{{- else if eq $lockColumn "updated_at" -}}
+	lockCols := []string{"updated_at"}
+	lockValues := []interface{}{o.UpdatedAt}
+	{{- template "timestamp_update_helper" . -}}
+	whitelist = strmangle.SetMerge(whitelist, lockCols)
+	{{- else -}}
+	var lockCols []string
+	var lockValues []interface{}
 	{{- template "timestamp_update_helper" . -}}
+	{{- end}}
 
 	var err error
 	{{if not .NoHooks -}}
-	if err = o.doBeforeUpdateHooks(exec); err != nil {
+	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
 		return err
 	}
-	{{end -}}
+	{{end}}
+
+	// Inferred columns include the primary key of an object that was not
+	// selected, which is what the row is found by rather than something to set
+	if len(o.whitelist) == 0 {
+		whitelist = strmangle.SetComplement(whitelist, {{$varNameSingular}}PrimaryKeyColumns)
+	}
+
+	wl := strmangle.UpdateColumnSet({{$varNameSingular}}Columns, {{$varNameSingular}}PrimaryKeyColumns, whitelist)
+	if len(wl) == 0 {
+		return errors.New("{{.PkgName}}: unable to update {{.Table.Name}}, could not build whitelist")
+	}
 
 	key := makeCacheKey(whitelist, nil)
 	{{$varNameSingular}}UpdateCacheMut.RLock()
@@ -53,11 +115,6 @@ func (o *{{$tableNameSingular}}) Update(exec boil.Executor, whitelist ... string
 	{{$varNameSingular}}UpdateCacheMut.RUnlock()
 
 	if !cached {
-		wl := strmangle.UpdateColumnSet({{$varNameSingular}}Columns, {{$varNameSingular}}PrimaryKeyColumns, whitelist)
-		if len(wl) == 0 {
-			return errors.New("{{.PkgName}}: unable to update {{.Table.Name}}, could not build whitelist")
-		}
-
 		cache.query = fmt.Sprintf("UPDATE {{$schemaTable}} SET %s WHERE %s",
 			strmangle.SetParamNames("{{.LQ}}", "{{.RQ}}", {{if .Dialect.IndexPlaceholders}}1{{else}}0{{end}}, wl),
 			strmangle.WhereClause("{{.LQ}}", "{{.RQ}}", {{if .Dialect.IndexPlaceholders}}len(wl)+1{{else}}0{{end}}, {{$varNameSingular}}PrimaryKeyColumns),
@@ -70,16 +127,46 @@ func (o *{{$tableNameSingular}}) Update(exec boil.Executor, whitelist ... string
 
 	values := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), cache.valueMapping)
 
+	{{if not $lockColumn -}}
+	if o.readonly != nil {
+		lockCols = wl
+		lockValues = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o.readonly)), cache.valueMapping[:len(wl)])
+	}
+
+	{{end -}}
+	query := cache.query
+	if len(lockCols) != 0 {
+		lock, lockArgs := lockWhere(lockCols, lockValues, len(values)+1)
+		query += " AND " + lock
+		values = append(values, lockArgs...)
+	}
+
 	if boil.DebugMode {
-		fmt.Fprintln(boil.DebugWriter, cache.query)
+		fmt.Fprintln(boil.DebugWriter, query)
 		fmt.Fprintln(boil.DebugWriter, values)
 	}
 
-	_, err = exec.Exec(cache.query, values...)
+	result, err := execContext(ctx, exec, query, values...)
 	if err != nil {
+		{{- if eq $lockColumn "version"}}
+		o.Version--
+		{{- end}}
 		return errors.Wrap(err, "{{.PkgName}}: unable to update {{.Table.Name}} row")
 	}
 
+	if len(lockCols) != 0 {
+		rowsAff, err := result.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "{{.PkgName}}: failed to get rows affected by update for {{.Table.Name}}")
+		}
+		if rowsAff == 0 {
+			{{- if eq $lockColumn "version"}}
+			o.Version--
+			{{- end}}
+			return ErrStaleObject
+		}
+	}
+
 	if !cached {
 		{{$varNameSingular}}UpdateCacheMut.Lock()
 		{{$varNameSingular}}UpdateCache[key] = cache
@@ -87,10 +174,17 @@ func (o *{{$tableNameSingular}}) Update(exec boil.Executor, whitelist ... string
 	}
 
 	{{if not .NoHooks -}}
-	return o.doAfterUpdateHooks(exec)
-	{{- else -}}
+	if err = o.doAfterUpdateHooks(ctx, exec); err != nil {
+		return err
+	}
+
+	{{end -}}
+	// The row now holds what o does, later updates are checked against it
+	if o.readonly != nil {
+		*o.readonly = *o
+	}
+
 	return nil
-	{{- end}}
 }
 
 // UpdateAllP updates all rows with matching column names, and panics on error.
@@ -101,14 +195,33 @@ func (q {{$varNameSingular}}Query) UpdateAllP(cols M) {
 }
 
 // UpdateAll updates all rows with the specified column values.
+// See WithChanges for the Changesets of the rows updated.
 func (q {{$varNameSingular}}Query) UpdateAll(cols M) error {
+	return q.UpdateAllContext(context.Background(), cols)
+}
+
+// UpdateAllContext is UpdateAll, with its queries canceled with ctx.
+func (q {{$varNameSingular}}Query) UpdateAllContext(ctx context.Context, cols M) error {
+	rows, err := q.affected(ctx)
+	if err != nil {
+		return errors.Wrap(err, "{{.PkgName}}: unable to select rows to update all for {{.Table.Name}}")
+	}
+
 	queries.SetUpdate(q.Query, cols)
 
-	_, err := q.Query.Exec()
+	_, err = withContext(ctx, q.Query).Exec()
 	if err != nil {
 		return errors.Wrap(err, "{{.PkgName}}: unable to update all for {{.Table.Name}}")
 	}
 
+	if len(rows) != 0 {
+		chs := make([]*Changeset, len(rows))
+		for i, obj := range rows {
+			chs[i] = obj.updatedChangeset(cols)
+		}
+		addChanges(ctx, queries.GetExecutor(q.Query), chs...)
+	}
+
 	return nil
 }
 
@@ -132,7 +245,14 @@ func (o {{$tableNameSingular}}Slice) UpdateAllP(exec boil.Executor, cols M) {
 }
 
 // UpdateAll updates all rows with the specified column values, using an executor.
+// A Changeable executor gets a Changeset per row, with the values the rows
+// were selected with as before values.
 func (o {{$tableNameSingular}}Slice) UpdateAll(exec boil.Executor, cols M) error {
+	return o.UpdateAllContext(context.Background(), exec, cols)
+}
+
+// UpdateAllContext is UpdateAll, with the query canceled with ctx.
+func (o {{$tableNameSingular}}Slice) UpdateAllContext(ctx context.Context, exec boil.Executor, cols M) error {
 	ln := int64(len(o))
 	if ln == 0 {
 		return nil
@@ -169,10 +289,18 @@ func (o {{$tableNameSingular}}Slice) UpdateAll(exec boil.Executor, cols M) error
 		fmt.Fprintln(boil.DebugWriter, args...)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "{{.PkgName}}: unable to update all in {{$varNameSingular}} slice")
 	}
 
+	if _, ok := exec.(Changeable); ok {
+		chs := make([]*Changeset, len(o))
+		for i, obj := range o {
+			chs[i] = obj.updatedChangeset(cols)
+		}
+		addChanges(ctx, exec, chs...)
+	}
+
 	return nil
 }
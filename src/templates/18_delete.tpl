@@ -1,5 +1,11 @@
 {{- $tableNameSingular := .Table.Name | singular | titleCase -}}
 {{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
 {{- $schemaTable := .Table.Name | .SchemaTable}}
 // DeleteP deletes a single {{$tableNameSingular}} record with an executor.
 // DeleteP will match against the primary key column to find the record to delete.
@@ -29,16 +35,156 @@ func (o *{{$tableNameSingular}}) DeleteGP() {
 	}
 }
 
+{{if $softDelete -}}
+// Delete soft deletes a single {{$tableNameSingular}} record with an executor, setting its
+// deleted_at. Delete will match against the primary key column to find the
+// record to delete. The record is left out of queries from then on, see
+// Restore and HardDelete. A record deleted already keeps the time it was
+// deleted at.
+func (o *{{$tableNameSingular}}) Delete(exec boil.Executor) error {
+	return o.DeleteContext(context.Background(), exec)
+}
+
+// DeleteContext is Delete, with the query canceled with ctx.
+func (o *{{$tableNameSingular}}) DeleteContext(ctx context.Context, exec boil.Executor) error {
+	if o == nil {
+	return errors.New("{{.PkgName}}: no {{$tableNameSingular}} provided for delete")
+	}
+  operation, whitelist := o.operation, o.whitelist
+  o.operation = "SOFT_DELETE"
+  o.whitelist = []string{"deleted_at"}
+
+	{{if not .NoHooks -}}
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+	return err
+	}
+	{{- end}}
+
+	// Whole seconds, which any datetime column holds unchanged, so that the
+	// deleted_at o holds is the one of the row
+	currTime := time.Now().In(boil.GetLocation()).Truncate(time.Second)
+	args := append([]interface{}{currTime}, queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), {{$varNameSingular}}PrimaryKeyMapping)...)
+	sql := "UPDATE {{$schemaTable}} SET {{if .Dialect.IndexPlaceholders}}{{"deleted_at" | .Quotes}}=$1 WHERE {{whereClause .LQ .RQ 2 .Table.PKey.Columns}}{{else}}{{"deleted_at" | .Quotes}}=? WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{end}} AND {{"deleted_at" | .Quotes}} IS NULL"
+
+	if boil.DebugMode {
+	fmt.Fprintln(boil.DebugWriter, sql)
+	fmt.Fprintln(boil.DebugWriter, args)
+	}
+
+	result, err := execContext(ctx, exec, sql, args...)
+	if err != nil {
+	return errors.Wrap(err, "{{.PkgName}}: unable to soft delete from {{.Table.Name}}")
+	}
+
+	rowsAff, err := result.RowsAffected()
+	if err != nil {
+	return errors.Wrap(err, "{{.PkgName}}: failed to get rows affected by soft delete for {{.Table.Name}}")
+	}
+	// The row was deleted already, and nothing changed
+	if rowsAff == 0 {
+		o.operation, o.whitelist = operation, whitelist
+		return nil
+	}
+
+	o.DeletedAt.Time = currTime
+	o.DeletedAt.Valid = true
+
+	{{if not .NoHooks -}}
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+	return err
+	}
+	{{- end}}
+
+	// The row now holds what o does, later updates are checked against it
+	if o.readonly != nil {
+		*o.readonly = *o
+	}
+
+	return nil
+}
+
+// Restore brings back a {{$tableNameSingular}} record soft deleted by Delete, clearing
+// its deleted_at. See Update for how a selected object is checked against
+// the row.
+func (o *{{$tableNameSingular}}) Restore(exec boil.Executor) error {
+	return o.RestoreContext(context.Background(), exec)
+}
+
+// RestoreContext is Restore, with the query canceled with ctx.
+func (o *{{$tableNameSingular}}) RestoreContext(ctx context.Context, exec boil.Executor) error {
+	if o == nil {
+	return errors.New("{{.PkgName}}: no {{$tableNameSingular}} provided for restore")
+	}
+
+	o.DeletedAt.Valid = false
+	if err := o.UpdateContext(ctx, exec, "deleted_at"); err != nil {
+		o.DeletedAt.Valid = true
+		return err
+	}
+
+	return nil
+}
+
+// HardDelete deletes a single {{$tableNameSingular}} record with an executor for good,
+// whether it was soft deleted or not. HardDelete will match against the
+// primary key column to find the record to delete.
+func (o *{{$tableNameSingular}}) HardDelete(exec boil.Executor) error {
+	return o.HardDeleteContext(context.Background(), exec)
+}
+
+// HardDeleteContext is HardDelete, with the query canceled with ctx.
+func (o *{{$tableNameSingular}}) HardDeleteContext(ctx context.Context, exec boil.Executor) error {
+	if o == nil {
+	return errors.New("{{.PkgName}}: no {{$tableNameSingular}} provided for delete")
+	}
+  o.operation = "DELETE"
+  o.whitelist = nil
+
+	{{if not .NoHooks -}}
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
+	return err
+	}
+	{{- end}}
+
+	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), {{$varNameSingular}}PrimaryKeyMapping)
+	sql := "DELETE FROM {{$schemaTable}} WHERE {{if .Dialect.IndexPlaceholders}}{{whereClause .LQ .RQ 1 .Table.PKey.Columns}}{{else}}{{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{end}}"
+
+	if boil.DebugMode {
+	fmt.Fprintln(boil.DebugWriter, sql)
+	fmt.Fprintln(boil.DebugWriter, args)
+	}
+
+	_, err := execContext(ctx, exec, sql, args...)
+	if err != nil {
+	return errors.Wrap(err, "{{.PkgName}}: unable to delete from {{.Table.Name}}")
+	}
+
+	{{if not .NoHooks -}}
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
+	return err
+	}
+	{{- end}}
+
+	return nil
+}
+
+{{- else -}}
 // Delete deletes a single {{$tableNameSingular}} record with an executor.
 // Delete will match against the primary key column to find the record to delete.
 func (o *{{$tableNameSingular}}) Delete(exec boil.Executor) error {
+	return o.DeleteContext(context.Background(), exec)
+}
+
+// DeleteContext is Delete, with the query canceled with ctx.
+func (o *{{$tableNameSingular}}) DeleteContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil {
 	return errors.New("{{.PkgName}}: no {{$tableNameSingular}} provided for delete")
 	}
   o.operation = "DELETE"
+  o.whitelist = nil
 
 	{{if not .NoHooks -}}
-	if err := o.doBeforeDeleteHooks(exec); err != nil {
+	if err := o.doBeforeDeleteHooks(ctx, exec); err != nil {
 	return err
 	}
 	{{- end}}
@@ -51,13 +197,13 @@ func (o *{{$tableNameSingular}}) Delete(exec boil.Executor) error {
 	fmt.Fprintln(boil.DebugWriter, args)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 	return errors.Wrap(err, "{{.PkgName}}: unable to delete from {{.Table.Name}}")
 	}
 
 	{{if not .NoHooks -}}
-	if err := o.doAfterDeleteHooks(exec); err != nil {
+	if err := o.doAfterDeleteHooks(ctx, exec); err != nil {
 	return err
 	}
 	{{- end}}
@@ -65,6 +211,8 @@ func (o *{{$tableNameSingular}}) Delete(exec boil.Executor) error {
 	return nil
 }
 
+{{- end}}
+
 // DeleteAllP deletes all rows, and panics on error.
 func (q {{$varNameSingular}}Query) DeleteAllP() {
 	if err := q.DeleteAll(); err != nil {
@@ -73,18 +221,62 @@ func (q {{$varNameSingular}}Query) DeleteAllP() {
 }
 
 // DeleteAll deletes all matching rows.
+// See WithChanges for the Changesets of the rows deleted.
+{{- if $softDelete}}
+// The rows are soft deleted, as Delete does, and those deleted already keep
+// the time they were deleted at.
+{{- end}}
 func (q {{$varNameSingular}}Query) DeleteAll() error {
+	return q.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext is DeleteAll, with its queries canceled with ctx.
+func (q {{$varNameSingular}}Query) DeleteAllContext(ctx context.Context) error {
 	if q.Query == nil {
 	return errors.New("{{.PkgName}}: no {{$varNameSingular}}Query provided for delete all")
 	}
+	{{- if $softDelete}}
+
+	// Rows deleted already keep the time they were deleted at
+	if q.deleted != excludeDeleted {
+		queries.AppendWhere(q.Query, "{{$schemaTable}}.{{"deleted_at" | .Quotes}} IS NULL")
+	}
+	{{- end}}
+
+	rows, err := q.affected(ctx)
+	if err != nil {
+	return errors.Wrap(err, "{{.PkgName}}: unable to select rows to delete all from {{.Table.Name}}")
+	}
 
+	{{if $softDelete -}}
+	currTime := time.Now().In(boil.GetLocation()).Truncate(time.Second)
+	queries.SetUpdate(q.Query, M{"deleted_at": currTime})
+	{{- else -}}
 	queries.SetDelete(q.Query)
+	{{- end}}
 
-	_, err := q.Query.Exec()
+	_, err = withContext(ctx, q.Query).Exec()
 	if err != nil {
 	return errors.Wrap(err, "{{.PkgName}}: unable to delete all from {{.Table.Name}}")
 	}
 
+	if len(rows) != 0 {
+	chs := make([]*Changeset, len(rows))
+	for i, obj := range rows {
+		{{- if $softDelete}}
+		obj.operation = "SOFT_DELETE"
+		obj.whitelist = []string{"deleted_at"}
+		obj.DeletedAt.Time = currTime
+		obj.DeletedAt.Valid = true
+		{{- else}}
+		obj.operation = "DELETE"
+		obj.whitelist = nil
+		{{- end}}
+		chs[i], _ = obj.Changes()
+	}
+	addChanges(ctx, queries.GetExecutor(q.Query), chs...)
+	}
+
 	return nil
 }
 
@@ -111,7 +303,16 @@ func (o {{$tableNameSingular}}Slice) DeleteAllP(exec boil.Executor) {
 }
 
 // DeleteAll deletes all rows in the slice, using an executor.
+{{- if $softDelete}}
+// The rows are soft deleted, as Delete does, and those deleted already keep
+// the time they were deleted at.
+{{- end}}
 func (o {{$tableNameSingular}}Slice) DeleteAll(exec boil.Executor) error {
+	return o.DeleteAllContext(context.Background(), exec)
+}
+
+// DeleteAllContext is DeleteAll, with the query canceled with ctx.
+func (o {{$tableNameSingular}}Slice) DeleteAllContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil {
 		return errors.New("{{.PkgName}}: no {{$tableNameSingular}} slice provided for delete all")
 	}
@@ -119,48 +320,102 @@ func (o {{$tableNameSingular}}Slice) DeleteAll(exec boil.Executor) error {
 	if len(o) == 0 {
 		return nil
 	}
+	{{- if $softDelete}}
+
+	// Rows deleted already keep the time they were deleted at
+	var deleted {{$tableNameSingular}}Slice
+	for _, obj := range o {
+		if !obj.DeletedAt.Valid {
+			deleted = append(deleted, obj)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	o = deleted
+	{{- end}}
+
+	for _, obj := range o {
+		{{- if $softDelete}}
+		obj.operation = "SOFT_DELETE"
+		obj.whitelist = []string{"deleted_at"}
+		{{- else}}
+		obj.operation = "DELETE"
+		obj.whitelist = nil
+		{{- end}}
+	}
 
 	{{if not .NoHooks -}}
 	if len({{$varNameSingular}}BeforeDeleteHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doBeforeDeleteHooks(exec); err != nil {
+			if err := obj.doBeforeDeleteHooks(ctx, exec); err != nil {
 				return err
 			}
 		}
 	}
 	{{- end}}
 
+	{{if $softDelete -}}
+	currTime := time.Now().In(boil.GetLocation()).Truncate(time.Second)
+	args := []interface{}{currTime}
+	{{- else -}}
 	var args []interface{}
+	{{- end}}
 	for _, obj := range o {
 		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), {{$varNameSingular}}PrimaryKeyMapping)
 		args = append(args, pkeyArgs...)
 	}
 
+	{{if $softDelete -}}
+	sql := fmt.Sprintf(
+		"UPDATE {{$schemaTable}} SET %s WHERE (%s) IN (%s) AND {{"deleted_at" | .Quotes}} IS NULL",
+		strmangle.SetParamNames("{{.LQ}}", "{{.RQ}}", {{if .Dialect.IndexPlaceholders}}1{{else}}0{{end}}, []string{"deleted_at"}),
+		strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, {{$varNameSingular}}PrimaryKeyColumns), ","),
+		strmangle.Placeholders(dialect.IndexPlaceholders, len(o) * len({{$varNameSingular}}PrimaryKeyColumns), 2, len({{$varNameSingular}}PrimaryKeyColumns)),
+	)
+	{{- else -}}
 	sql := fmt.Sprintf(
 		"DELETE FROM {{$schemaTable}} WHERE (%s) IN (%s)",
 		strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, {{$varNameSingular}}PrimaryKeyColumns), ","),
 		strmangle.Placeholders(dialect.IndexPlaceholders, len(o) * len({{$varNameSingular}}PrimaryKeyColumns), 1, len({{$varNameSingular}}PrimaryKeyColumns)),
 	)
+	{{- end}}
 
 	if boil.DebugMode {
 		fmt.Fprintln(boil.DebugWriter, sql)
 		fmt.Fprintln(boil.DebugWriter, args)
 	}
 
-	_, err := exec.Exec(sql, args...)
+	_, err := execContext(ctx, exec, sql, args...)
 	if err != nil {
 		return errors.Wrap(err, "{{.PkgName}}: unable to delete all from {{$varNameSingular}} slice")
 	}
+	{{- if $softDelete}}
+
+	for _, obj := range o {
+		obj.DeletedAt.Time = currTime
+		obj.DeletedAt.Valid = true
+	}
+	{{- end}}
 
 	{{if not .NoHooks -}}
 	if len({{$varNameSingular}}AfterDeleteHooks) != 0 {
 		for _, obj := range o {
-			if err := obj.doAfterDeleteHooks(exec); err != nil {
+			if err := obj.doAfterDeleteHooks(ctx, exec); err != nil {
 				return err
 			}
 		}
 	}
 	{{- end}}
+	{{- if $softDelete}}
+
+	// The rows now hold what o does, later updates are checked against them
+	for _, obj := range o {
+		if obj.readonly != nil {
+			*obj.readonly = *obj
+		}
+	}
+	{{- end}}
 
 	return nil
 }
@@ -1,7 +1,13 @@
 {{- $tableNameSingular := .Table.Name | singular | titleCase -}}
 {{- $varNameSingular := .Table.Name | singular | camelCase -}}
 {{- $varNamePlural := .Table.Name | plural | camelCase -}}
-{{- $schemaTable := .Table.Name | .SchemaTable}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end}}
 // ReloadGP refetches the object from the database and panics on error.
 func (o *{{$tableNameSingular}}) ReloadGP() {
 	if err := o.ReloadG(); err != nil {
@@ -27,8 +33,20 @@ func (o *{{$tableNameSingular}}) ReloadG() error {
 
 // Reload refetches the object from the database
 // using the primary keys with an executor.
+{{- if $softDelete}}
+// A soft-deleted object is reloaded as well, as with ReloadAll.
+{{- end}}
 func (o *{{$tableNameSingular}}) Reload(exec boil.Executor) error {
-	ret, err := Find{{$tableNameSingular}}(exec, {{.Table.PKey.Columns | stringMap .StringFuncs.titleCase | prefixStringSlice "o." | join ", "}})
+	return o.ReloadContext(context.Background(), exec)
+}
+
+// ReloadContext is Reload, with the query canceled with ctx.
+func (o *{{$tableNameSingular}}) ReloadContext(ctx context.Context, exec boil.Executor) error {
+	{{- if $softDelete}}
+	ret, err := find{{$tableNameSingular}}(ctx, exec, true, {{.Table.PKey.Columns | stringMap .StringFuncs.titleCase | prefixStringSlice "o." | join ", "}})
+	{{- else}}
+	ret, err := Find{{$tableNameSingular}}Context(ctx, exec, {{.Table.PKey.Columns | stringMap .StringFuncs.titleCase | prefixStringSlice "o." | join ", "}})
+	{{- end}}
 	if err != nil {
 		return err
 	}
@@ -68,6 +86,11 @@ func (o *{{$tableNameSingular}}Slice) ReloadAllG() error {
 // ReloadAll refetches every row with matching primary key column values
 // and overwrites the original object slice with the newly updated slice.
 func (o *{{$tableNameSingular}}Slice) ReloadAll(exec boil.Executor) error {
+	return o.ReloadAllContext(context.Background(), exec)
+}
+
+// ReloadAllContext is ReloadAll, with the query canceled with ctx.
+func (o *{{$tableNameSingular}}Slice) ReloadAllContext(ctx context.Context, exec boil.Executor) error {
 	if o == nil || len(*o) == 0 {
 		return nil
 	}
@@ -87,7 +110,7 @@ func (o *{{$tableNameSingular}}Slice) ReloadAll(exec boil.Executor) error {
 
 	q := queries.Raw(exec, sql, args...)
 
-	err := q.Bind(&{{$varNamePlural}})
+	err := withContext(ctx, q).Bind(&{{$varNamePlural}})
 	if err != nil {
 		return errors.Wrap(err, "{{.PkgName}}: unable to reload all in {{$tableNameSingular}}Slice")
 	}
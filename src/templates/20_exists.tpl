@@ -2,19 +2,33 @@
 {{- $colDefs := sqlColDefinitions .Table.Columns .Table.PKey.Columns -}}
 {{- $pkNames := $colDefs.Names | stringMap .StringFuncs.camelCase -}}
 {{- $pkArgs := joinSlices " " $pkNames $colDefs.Types | join ", " -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
 {{- $schemaTable := .Table.Name | .SchemaTable}}
 // {{$tableNameSingular}}Exists checks if the {{$tableNameSingular}} row exists.
+{{- if $softDelete}}
+// A soft-deleted row does not.
+{{- end}}
 func {{$tableNameSingular}}Exists(exec boil.Executor, {{$pkArgs}}) (bool, error) {
+	return {{$tableNameSingular}}ExistsContext(context.Background(), exec, {{$pkNames | join ", "}})
+}
+
+// {{$tableNameSingular}}ExistsContext is {{$tableNameSingular}}Exists, with the query canceled with ctx.
+func {{$tableNameSingular}}ExistsContext(ctx context.Context, exec boil.Executor, {{$pkArgs}}) (bool, error) {
 	var exists bool
 
-	sql := "select exists(select 1 from {{$schemaTable}} where {{if .Dialect.IndexPlaceholders}}{{whereClause .LQ .RQ 1 .Table.PKey.Columns}}{{else}}{{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{end}} limit 1)"
+	sql := "select exists(select 1 from {{$schemaTable}} where {{if .Dialect.IndexPlaceholders}}{{whereClause .LQ .RQ 1 .Table.PKey.Columns}}{{else}}{{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{end}}{{if $softDelete}} and {{"deleted_at" | .Quotes}} is null{{end}} limit 1)"
 
 	if boil.DebugMode {
 		fmt.Fprintln(boil.DebugWriter, sql)
 		fmt.Fprintln(boil.DebugWriter, {{$pkNames | join ", "}})
 	}
 
-	row := exec.QueryRow(sql, {{$pkNames | join ", "}})
+	row := queryRowContext(ctx, exec, sql, {{$pkNames | join ", "}})
 
 	err := row.Scan(&exists)
 	if err != nil {
@@ -1,36 +1,71 @@
 {{- $tableNameSingular := .Table.Name | singular | titleCase -}}
 {{- $modelName := $tableNameSingular | titleCase -}}
 {{- $varNameSingular := .Table.Name | singular | camelCase -}}
-{{- $schemaTable := .Table.Name | .SchemaTable}}
-// InsertG a single record. See Insert for whitelist behavior description.
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end}}
+{{- define "column_changed" -}}
+{{- $f := titleCase .Name -}}
+{{- if eq .Type "int" "int8" "int16" "int32" "int64" "uint" "uint8" "uint16" "uint32" "uint64" "float32" "float64" "bool" "string" "types.Byte" -}}
+o.{{$f}} != ro.{{$f}}
+{{- else if eq .Type "null.Bool" "null.Byte" "null.Int" "null.Int8" "null.Int16" "null.Int32" "null.Int64" "null.Uint" "null.Uint8" "null.Uint16" "null.Uint32" "null.Uint64" "null.Float32" "null.Float64" "null.String" -}}
+o.{{$f}}.Valid != ro.{{$f}}.Valid || (o.{{$f}}.Valid && o.{{$f}} != ro.{{$f}})
+{{- else if eq .Type "time.Time" -}}
+!o.{{$f}}.Equal(ro.{{$f}})
+{{- else if eq .Type "null.Time" -}}
+o.{{$f}}.Valid != ro.{{$f}}.Valid || (o.{{$f}}.Valid && !o.{{$f}}.Time.Equal(ro.{{$f}}.Time))
+{{- else if eq .Type "[]byte" "types.JSON" -}}
+!bytes.Equal(o.{{$f}}, ro.{{$f}})
+{{- else if eq .Type "null.Bytes" -}}
+o.{{$f}}.Valid != ro.{{$f}}.Valid || (o.{{$f}}.Valid && !bytes.Equal(o.{{$f}}.Bytes, ro.{{$f}}.Bytes))
+{{- else if eq .Type "null.JSON" -}}
+o.{{$f}}.Valid != ro.{{$f}}.Valid || (o.{{$f}}.Valid && !bytes.Equal(o.{{$f}}.JSON, ro.{{$f}}.JSON))
+{{- else -}}
+!reflect.DeepEqual(o.{{$f}}, ro.{{$f}})
+{{- end -}}
+{{- end -}}
+
+// Changes lists the whitelisted columns of o that differ from the values it
+// was selected with. Inserts have no before values and deletes no after
+// values; an object deleted without being selected has its own values as
+// before values, and one updated without being selected has unknown ones.
+// Columns set sensitive with SetSensitive are masked or left out.
 func (o *{{$tableNameSingular}}) Changes()(ch *Changeset,err error) {
-  ch = &Changeset{Table: "{{.Table.Name}}",
-      Changes: []*ChangeItem{}, Operation: o.Operation()}
-	v := reflect.Indirect(reflect.ValueOf(o.readonly))
-	vnew := reflect.Indirect(reflect.ValueOf(o))
+  ch = o.newChangeset(o.Operation())
 
+  ro := o.readonly
+  deleted := o.operation == "DELETE"
   for _, c := range o.Whitelist() {
-    if f, ok := {{$modelName}}FieldMapping[c]; ok {
-      var before, after interface{}
-      if v.IsValid() {
-        before = v.FieldByName(f).Interface()
-      }
-
-      if vnew.IsValid() {
-        after = vnew.FieldByName(f).Interface()
-      }
+    sens := sensitivity("{{.Table.Name}}", c)
+    if sens == Exclude {
+      continue
+    }
 
-      chitem := &ChangeItem{Name: c}
-      if o.operation == "DELETE" {
-        chitem.Before = before
-      }else{
-        chitem.Before = before
-        chitem.After = after
+    var chitem *ChangeItem
+    switch c {
+    {{- range .Table.Columns}}
+    {{- $f := titleCase .Name}}
+    case "{{.Name}}":
+      switch {
+      case deleted:
+        chitem = &ChangeItem{Name: c, Before: o.selected().{{$f}}}
+      case ro == nil:
+        chitem = &ChangeItem{Name: c, After: o.{{$f}}, BeforeUnknown: o.operation == "UPDATE"}
+      case {{template "column_changed" .}}:
+        chitem = &ChangeItem{Name: c, Before: ro.{{$f}}, After: o.{{$f}}}
       }
+    {{- end}}
+    }
 
-      if !reflect.DeepEqual(chitem.Before, chitem.After) {
-        ch.Changes = append(ch.Changes, chitem)
+    if chitem != nil {
+      if sens == Redact {
+        chitem.redact()
       }
+      ch.Changes = append(ch.Changes, chitem)
     }
   }
 
@@ -43,49 +78,115 @@ func (o *{{$tableNameSingular}}) Whitelist()(wl []string) {
 		return o.whitelist
 	}
 
-	// Calculates changed columns as whitelist
-	v := reflect.Indirect(reflect.ValueOf(o.readonly))
-	vnew := reflect.Indirect(reflect.ValueOf(o))
-
-	for _, c := range {{$varNameSingular}}Columns {
-		if f, ok := {{$modelName}}FieldMapping[c]; ok {
-			var before, after interface{}
-			if v.IsValid() {
-				before = v.FieldByName(f).Interface()
-			}
-
-			if vnew.IsValid() {
-				after = vnew.FieldByName(f).Interface()
-			}
-			if !reflect.DeepEqual(before, after) || o.operation == "DELETE"{
-				wl = append(wl, c)
-			}
-		}
+	// Without a selected copy to compare with, and on delete, every column
+	// counts as changed
+	ro := o.readonly
+	if ro == nil || o.operation == "DELETE" {
+		return append(wl, {{$varNameSingular}}Columns...)
 	}
 
+	{{range .Table.Columns -}}
+	if {{template "column_changed" .}} {
+		wl = append(wl, "{{.Name}}")
+	}
+	{{end}}
 	return
 }
 
+// newChangeset starts a Changeset of op on the row of o, without changes.
+func (o *{{$tableNameSingular}}) newChangeset(op string) *Changeset {
+  return &Changeset{Table: "{{.Table.Name}}",
+      PrimaryKey: map[string]interface{}{
+        {{range .Table.PKey.Columns -}}
+        "{{.}}": o.{{titleCase .}},
+        {{end -}}
+      },
+      Changes: []*ChangeItem{}, Operation: op,
+      ChangedAt: time.Now().In(boil.GetLocation())}
+}
+
+// selected returns the values the row of o is known to hold: the ones o was
+// selected with, or o itself when it was not selected.
+func (o *{{$tableNameSingular}}) selected() *{{$tableNameSingular}} {
+  if o.readonly != nil {
+    return o.readonly
+  }
+
+  return o
+}
+
+// updatedChangeset records a bulk update setting cols on the row of o.
+// Columns set sensitive with SetSensitive are masked or left out.
+func (o *{{$tableNameSingular}}) updatedChangeset(cols M) *Changeset {
+  ch := o.newChangeset("UPDATE")
+  ro := o.selected()
+  for _, c := range {{$varNameSingular}}Columns {
+    v, ok := cols[c]
+    if !ok {
+      continue
+    }
+
+    sens := sensitivity("{{.Table.Name}}", c)
+    if sens == Exclude {
+      continue
+    }
+
+    chitem := &ChangeItem{Name: c, After: v}
+    switch c {
+    {{- range .Table.Columns}}
+    case "{{.Name}}":
+      chitem.Before = ro.{{titleCase .Name}}
+    {{- end}}
+    }
+    if sens == Redact {
+      chitem.redact()
+    }
+    ch.Changes = append(ch.Changes, chitem)
+  }
+
+  return ch
+}
+
+// WithChanges makes UpdateAll and DeleteAll select the rows they affect
+// beforehand, so that a Changeable executor gets a Changeset per row. Run
+// it in a transaction for the rows selected to be the ones changed.
+func (q {{$varNameSingular}}Query) WithChanges() {{$varNameSingular}}Query {
+  q.changes = true
+  return q
+}
+
+// affected selects the rows q is about to update or delete, when WithChanges
+// asked for their Changesets and the executor takes them.
+func (q {{$varNameSingular}}Query) affected(ctx context.Context) ({{$tableNameSingular}}Slice, error) {
+  if !q.changes {
+    return nil, nil
+  }
+  if _, ok := queries.GetExecutor(q.Query).(Changeable); !ok {
+    return nil, nil
+  }
+
+  sel := *q.Query
+  return {{$varNameSingular}}Query{Query: &sel}.AllContext(ctx)
+}
+
 func (o *{{$tableNameSingular}}) Operation() string {
   return o.operation
 }
 
 // Generated change history hook for models
 func init() {
-	chFunc := func(exec boil.Executor, s *{{$modelName}}) error {
+	chFunc := func(ctx context.Context, exec boil.Executor, s *{{$modelName}}) error {
     if s == nil || exec == nil {
       return nil
     }
 
 		ch, _ := s.Changes()
-		if changeable, ok := exec.(Changeable); ok {
-			changeable.AddChange(ch)
-		}
+		addChanges(ctx, exec, ch)
 
 		return nil
 	}
 
-  afterSel := func(exec boil.Executor, s *{{$modelName}}) error {
+  afterSel := func(ctx context.Context, exec boil.Executor, s *{{$modelName}}) error {
     if s == nil || exec == nil {
       return nil
     }
@@ -95,7 +196,7 @@ func init() {
     return nil
   }
 
-  beforeInsert := func(exec boil.Executor, s *{{$modelName}}) error {
+  beforeInsert := func(ctx context.Context, exec boil.Executor, s *{{$modelName}}) error {
     if s == nil || exec == nil {
       return nil
     }
@@ -104,7 +205,7 @@ func init() {
     return nil
   }
 
-  beforeUpdate := func(exec boil.Executor, s *{{$modelName}}) error {
+  beforeUpdate := func(ctx context.Context, exec boil.Executor, s *{{$modelName}}) error {
     if s == nil || exec == nil {
       return nil
     }
@@ -113,7 +214,7 @@ func init() {
     return nil
   }
 
-  beforeUpsert := func(exec boil.Executor, s *{{$modelName}}) error {
+  beforeUpsert := func(ctx context.Context, exec boil.Executor, s *{{$modelName}}) error {
     if s == nil || exec == nil {
       return nil
     }
@@ -122,12 +223,18 @@ func init() {
     return nil
   }
 
-  afterDelete := func(exec boil.Executor, s *{{$modelName}}) error {
+  afterDelete := func(ctx context.Context, exec boil.Executor, s *{{$modelName}}) error {
     if s == nil || exec == nil {
       return nil
     }
 
+    {{if $softDelete -}}
+    if s.operation != "SOFT_DELETE" {
+      s.operation = "DELETE"
+    }
+    {{- else -}}
     s.operation = "DELETE"
+    {{- end}}
     return nil
   }
 
@@ -0,0 +1,118 @@
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+// Apply{{$tableNameSingular}} replays ch, recorded on {{.Table.Name}}: an INSERT inserts the
+// row with its After values, an UPDATE or UPSERT sets its After values and
+// a DELETE deletes it.
+{{- if $softDelete}} A SOFT_DELETE sets deleted_at again.{{end}}
+func Apply{{$tableNameSingular}}(exec boil.Executor, ch *Changeset) error {
+	o, cols, err := {{$varNameSingular}}FromChangeset(ch, true)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Operation {
+	case "INSERT":
+		return o.reinsert(exec, cols)
+	case "UPDATE", "UPSERT"{{if $softDelete}}, "SOFT_DELETE"{{end}}:
+		return o.restore(exec, cols)
+	case "DELETE":
+		return o.{{if $softDelete}}HardDelete{{else}}Delete{{end}}(exec)
+	}
+
+	return errors.Errorf("{{.PkgName}}: unable to apply %s changes to {{.Table.Name}}", ch.Operation)
+}
+
+// Revert{{$tableNameSingular}} undoes ch, recorded on {{.Table.Name}}: an INSERT deletes the
+// row, an UPDATE restores its Before values and a DELETE inserts it again
+// with them. An UPSERT is undone as the insert or update it turned out to be.
+{{- if $softDelete}}
+// A SOFT_DELETE is undone by clearing deleted_at.
+{{- end}}
+func Revert{{$tableNameSingular}}(exec boil.Executor, ch *Changeset) error {
+	o, cols, err := {{$varNameSingular}}FromChangeset(ch, false)
+	if err != nil {
+		return err
+	}
+
+	switch ch.Operation {
+	case "INSERT":
+		return o.{{if $softDelete}}HardDelete{{else}}Delete{{end}}(exec)
+	case "UPSERT":
+		if ch.inserted() {
+			return o.{{if $softDelete}}HardDelete{{else}}Delete{{end}}(exec)
+		}
+		return o.restore(exec, cols)
+	case "UPDATE"{{if $softDelete}}, "SOFT_DELETE"{{end}}:
+		return o.restore(exec, cols)
+	case "DELETE":
+		return o.reinsert(exec, cols)
+	}
+
+	return errors.Errorf("{{.PkgName}}: unable to revert %s changes to {{.Table.Name}}", ch.Operation)
+}
+
+// {{$varNameSingular}}FromChangeset builds the row ch was recorded on, with its After values
+// or its Before ones, and lists the columns set from them. Redacted values
+// are not known, so their columns are left alone. Before values that were
+// not known when ch was recorded make it fail.
+func {{$varNameSingular}}FromChangeset(ch *Changeset, after bool) (*{{$tableNameSingular}}, []string, error) {
+	if ch == nil || ch.Table != "{{.Table.Name}}" {
+		return nil, nil, errors.New("{{.PkgName}}: changes were not recorded on {{.Table.Name}}")
+	}
+
+	o := &{{$tableNameSingular}}{}
+	cols := make([]string, 0, len(ch.Changes))
+	for _, item := range ch.Changes {
+		v := item.Before
+		if after {
+			v = item.After
+		} else if item.BeforeUnknown {
+			return nil, nil, errors.Errorf("{{.PkgName}}: unable to revert column %q of {{.Table.Name}}, its value before the change is unknown", item.Name)
+		}
+		if v == RedactedValue {
+			continue
+		}
+
+		if err := setColumn(o, {{$tableNameSingular}}FieldMapping, item.Name, v); err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, item.Name)
+	}
+
+	// The key the row has now, even where a change was to it
+	for c, v := range ch.PrimaryKey {
+		if err := setColumn(o, {{$tableNameSingular}}FieldMapping, c, v); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o, cols, nil
+}
+
+// reinsert inserts o with the columns listed in cols and its primary key.
+func (o *{{$tableNameSingular}}) reinsert(exec boil.Executor, cols []string) error {
+	return o.Insert(exec, strmangle.SetMerge(cols, {{$varNameSingular}}PrimaryKeyColumns)...)
+}
+
+// restore updates the columns of o listed in cols, leaving its primary key
+// alone.
+func (o *{{$tableNameSingular}}) restore(exec boil.Executor, cols []string) error {
+	cols = strmangle.SetComplement(cols, {{$varNameSingular}}PrimaryKeyColumns)
+	if len(cols) == 0 {
+		return nil
+	}
+
+	return o.Update(exec, cols...)
+}
+
+func init() {
+	changeAppliers["{{.Table.Name}}"] = struct {
+		apply, revert func(boil.Executor, *Changeset) error
+	}{Apply{{$tableNameSingular}}, Revert{{$tableNameSingular}}}
+}
@@ -0,0 +1,21 @@
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+var {{$varNameSingular}}Schema = SchemaTable{
+	Name: "{{.Table.Name}}",
+	Columns: []SchemaColumn{
+		{{range .Table.Columns -}}
+		{Name: "{{.Name}}", Type: "{{.Type}}", DBType: "{{.DBType}}", Nullable: {{.Nullable}}},
+		{{end -}}
+	},
+	PKey: []string{ {{- .Table.PKey.Columns | stringMap .StringFuncs.quoteWrap | join ", " -}} },
+	{{- if .Table.FKeys}}
+	FKeys: []SchemaForeignKey{
+		{{range .Table.FKeys -}}
+		{Name: "{{.Name}}", Column: "{{.Column}}", ForeignTable: "{{.ForeignTable}}", ForeignColumn: "{{.ForeignColumn}}"},
+		{{end -}}
+	},
+	{{- end}}
+}
+
+func init() {
+	SchemaTables["{{.Table.Name}}"] = {{$varNameSingular}}Schema
+}
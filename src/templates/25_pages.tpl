@@ -0,0 +1,98 @@
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+// SortBy sets the columns the pages of the query are sorted on, a "-" prefix
+// sorting one in descending order. The primary key breaks ties, and sorts
+// the pages on its own without SortBy. Do not combine it with qm.OrderBy.
+func (q {{$varNameSingular}}Query) SortBy(cols ...string) {{$varNameSingular}}Query {
+	q.keyset.sort = cols
+	return q
+}
+
+// PageSize sets the number of rows of the pages of the query.
+func (q {{$varNameSingular}}Query) PageSize(n int) {{$varNameSingular}}Query {
+	q.keyset.size = n
+	return q
+}
+
+// After makes the query select the page following the one cursor was
+// returned with, as Cursors.Next. An empty cursor selects the first page.
+func (q {{$varNameSingular}}Query) After(cursor string) {{$varNameSingular}}Query {
+	q.keyset.cursor = cursor
+	q.keyset.before = false
+	return q
+}
+
+// Before makes the query select the page preceding the one cursor was
+// returned with, as Cursors.Prev. An empty cursor selects the last page.
+func (q {{$varNameSingular}}Query) Before(cursor string) {{$varNameSingular}}Query {
+	q.keyset.cursor = cursor
+	q.keyset.before = true
+	return q
+}
+
+// PageP returns the page of {{$tableNameSingular}} records the query asks for, and panics on error.
+func (q {{$varNameSingular}}Query) PageP() ({{$tableNameSingular}}Slice, *Cursors) {
+	o, cursors, err := q.Page()
+	if err != nil {
+		panic(boil.WrapErr(err))
+	}
+
+	return o, cursors
+}
+
+// Page returns the page of {{$tableNameSingular}} records asked for with SortBy, PageSize,
+// After and Before, with the cursors of the pages around it. Rather than
+// skipping rows with an offset, it selects those sorted past the cursor, so
+// it is as fast on any page and rows changed in between are not skipped.
+// ErrInvalidCursor is returned for a cursor of another sort.
+func (q {{$varNameSingular}}Query) Page() ({{$tableNameSingular}}Slice, *Cursors, error) {
+	return q.PageContext(context.Background())
+}
+
+// PageContext is Page, with its query canceled with ctx.
+func (q {{$varNameSingular}}Query) PageContext(ctx context.Context) ({{$tableNameSingular}}Slice, *Cursors, error) {
+	k := q.keyset
+	cols, err := k.columns({{$tableNameSingular}}FieldMapping, {{$varNameSingular}}PrimaryKeyColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sel := *q.Query
+	if err := k.apply(&sel, &{{$tableNameSingular}}{}, {{$tableNameSingular}}FieldMapping, cols); err != nil {
+		return nil, nil, err
+	}
+
+	o, err := {{$varNameSingular}}Query{Query: &sel}.AllContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	more := k.size > 0 && len(o) > k.size
+	if more {
+		o = o[:k.size]
+	}
+	// Rows before the cursor were selected in reverse
+	if k.before {
+		for i, j := 0, len(o)-1; i < j; i, j = i+1, j-1 {
+			o[i], o[j] = o[j], o[i]
+		}
+	}
+
+	cursors := &Cursors{}
+	if len(o) == 0 {
+		return o, cursors, nil
+	}
+
+	if (more && !k.before) || (k.before && k.cursor != "") {
+		if cursors.Next, err = encodeCursor(o[len(o)-1], {{$tableNameSingular}}FieldMapping, cols); err != nil {
+			return nil, nil, err
+		}
+	}
+	if (more && k.before) || (!k.before && k.cursor != "") {
+		if cursors.Prev, err = encodeCursor(o[0], {{$tableNameSingular}}FieldMapping, cols); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return o, cursors, nil
+}
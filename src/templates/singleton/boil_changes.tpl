@@ -1,16 +1,233 @@
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vattle/sqlboiler/boil"
+)
+
 // Changeset used for auditing
 type Changeset struct {
-	Table     string        `json:"table"`
-	Changes   []*ChangeItem `json:"changes"`
-	Operation string        `json:"operation"`
+	Table      string                 `json:"table"`
+	PrimaryKey map[string]interface{} `json:"primary_key"`
+	Changes    []*ChangeItem          `json:"changes"`
+	Operation  string                 `json:"operation"`
+	ChangedAt  time.Time              `json:"changed_at"`
+	Actor      string                 `json:"actor,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
 }
 
 type ChangeItem struct {
 	Name   string      `json:"name"`
 	Before interface{} `json:"before"`
 	After  interface{} `json:"after"`
+	// BeforeUnknown is set when the value the column held before the change
+	// is not known, as for an update of an object that was not selected.
+	// Such a change cannot be reverted.
+	BeforeUnknown bool `json:"before_unknown,omitempty"`
+}
+
+// Sensitivity is how change tracking treats the values of a column.
+type Sensitivity int
+
+const (
+	// Tracked columns have their values recorded, the default
+	Tracked Sensitivity = iota
+	// Redact records changes to a column with its values masked
+	Redact
+	// Exclude leaves a column out of changes
+	Exclude
+)
+
+var (
+	sensitiveMut     sync.RWMutex
+	sensitiveColumns = map[string]map[string]Sensitivity{}
+)
+
+// SetSensitive sets how change tracking treats the values of column of
+// table. Whitelist still reports sensitive columns that changed, only the
+// Changesets made of them differ.
+func SetSensitive(table, column string, s Sensitivity) {
+	sensitiveMut.Lock()
+	defer sensitiveMut.Unlock()
+
+	if s == Tracked {
+		delete(sensitiveColumns[table], column)
+		return
+	}
+	if sensitiveColumns[table] == nil {
+		sensitiveColumns[table] = map[string]Sensitivity{}
+	}
+	sensitiveColumns[table][column] = s
+}
+
+// sensitivity returns how change tracking treats the values of column of
+// table, as set by SetSensitive.
+func sensitivity(table, column string) Sensitivity {
+	sensitiveMut.RLock()
+	defer sensitiveMut.RUnlock()
+
+	return sensitiveColumns[table][column]
+}
+
+// RedactedValue stands in for the values of columns set to Redact.
+const RedactedValue = "[REDACTED]"
+
+// redact masks the values of item, keeping a missing value missing so that
+// the change still reads as an insert, update or delete, and a NULL one
+// NULL.
+func (item *ChangeItem) redact() {
+	if !isNull(item.Before) {
+		item.Before = RedactedValue
+	}
+	if !isNull(item.After) {
+		item.After = RedactedValue
+	}
 }
 
 type Changeable interface {
 	AddChange(ch ...*Changeset)
 }
+
+// ContextExecutor is an executor that carries the context of the work it
+// does, such as the request being served. Changesets made through one are
+// stamped with the actor and request ID of that context.
+type ContextExecutor interface {
+	boil.Executor
+	Context() context.Context
+}
+
+type changeContextKey int
+
+const (
+	actorContextKey changeContextKey = iota
+	requestIDContextKey
+)
+
+// WithActor returns a copy of ctx naming the actor recorded on Changesets.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey, actor)
+}
+
+// ActorFrom returns the actor set on ctx by WithActor, if any.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey).(string)
+	return actor
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID recorded on
+// Changesets.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFrom returns the request ID set on ctx by WithRequestID, if any.
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
+// identify stamps ch with the actor and request ID of ctx, the context of
+// the write that made it. Those ctx lacks are taken from exec, when it is a
+// ContextExecutor.
+func (ch *Changeset) identify(ctx context.Context, exec boil.Executor) {
+	ch.Actor = ActorFrom(ctx)
+	ch.RequestID = RequestIDFrom(ctx)
+
+	ce, ok := exec.(ContextExecutor)
+	if !ok {
+		return
+	}
+	if ch.Actor == "" {
+		ch.Actor = ActorFrom(ce.Context())
+	}
+	if ch.RequestID == "" {
+		ch.RequestID = RequestIDFrom(ce.Context())
+	}
+}
+
+// addChanges identifies chs with ctx and adds them to exec, when exec is
+// Changeable.
+func addChanges(ctx context.Context, exec boil.Executor, chs ...*Changeset) {
+	changeable, ok := exec.(Changeable)
+	if !ok {
+		return
+	}
+
+	for _, ch := range chs {
+		ch.identify(ctx, exec)
+	}
+	changeable.AddChange(chs...)
+}
+
+// changeAppliers holds the Apply and Revert functions of each table, by
+// table name.
+var changeAppliers = map[string]struct {
+	apply, revert func(boil.Executor, *Changeset) error
+}{}
+
+// Apply replays ch on the table it was recorded on.
+func Apply(exec boil.Executor, ch *Changeset) error {
+	a, ok := changeAppliers[ch.Table]
+	if !ok {
+		return errors.Errorf("{{.PkgName}}: unable to apply changes to unknown table %q", ch.Table)
+	}
+
+	return a.apply(exec, ch)
+}
+
+// Revert undoes ch on the table it was recorded on.
+func Revert(exec boil.Executor, ch *Changeset) error {
+	a, ok := changeAppliers[ch.Table]
+	if !ok {
+		return errors.Errorf("{{.PkgName}}: unable to revert changes to unknown table %q", ch.Table)
+	}
+
+	return a.revert(exec, ch)
+}
+
+// inserted reports whether ch created its row, which is how an UPSERT
+// without before values is told apart from one that updated.
+func (ch *Changeset) inserted() bool {
+	for _, item := range ch.Changes {
+		if item.Before != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setColumn sets the field fields maps column to on o, a pointer to a model.
+// Values of another type than the field, such as ones decoded from JSON,
+// are converted through their JSON encoding.
+func setColumn(o interface{}, fields map[string]string, column string, v interface{}) error {
+	name, ok := fields[column]
+	if !ok {
+		return errors.Errorf("{{.PkgName}}: unknown column %q", column)
+	}
+
+	f := reflect.Indirect(reflect.ValueOf(o)).FieldByName(name)
+	if v == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+
+	if rv := reflect.ValueOf(v); rv.Type().AssignableTo(f.Type()) {
+		f.Set(rv)
+		return nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "{{.PkgName}}: unable to convert value of column %q", column)
+	}
+	if err := json.Unmarshal(b, f.Addr().Interface()); err != nil {
+		return errors.Wrapf(err, "{{.PkgName}}: unable to convert value of column %q", column)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+import (
+	"github.com/vattle/sqlboiler/boil"
+	"github.com/vattle/sqlboiler/queries"
+	"github.com/vattle/sqlboiler/queries/qm"
+)
+
+// softDeleted is which soft-deleted rows a query on a table with soft
+// deletes sees.
+type softDeleted int
+
+const (
+	// excludeDeleted leaves soft-deleted rows out, the default
+	excludeDeleted softDeleted = iota
+	// includeDeleted sees rows whether they were soft deleted or not
+	includeDeleted
+	// onlyDeleted sees soft-deleted rows only
+	onlyDeleted
+)
+
+// softDeleteExecutor is the executor of a query while newSoftDeleteQuery
+// applies its mods, carrying the softDeleted WithDeleted and OnlyDeleted set
+// on the query.
+type softDeleteExecutor struct {
+	boil.Executor
+	deleted softDeleted
+}
+
+// WithDeleted includes soft-deleted rows, which queries on tables with soft
+// deletes leave out by default.
+func WithDeleted() qm.QueryMod {
+	return setSoftDeleted(includeDeleted)
+}
+
+// OnlyDeleted restricts a query on a table with soft deletes to
+// soft-deleted rows.
+func OnlyDeleted() qm.QueryMod {
+	return setSoftDeleted(onlyDeleted)
+}
+
+func setSoftDeleted(deleted softDeleted) qm.QueryMod {
+	return func(q *queries.Query) {
+		if e, ok := queries.GetExecutor(q).(*softDeleteExecutor); ok {
+			e.deleted = deleted
+		}
+	}
+}
+
+// newSoftDeleteQuery is NewQuery for a table with soft deletes. It also
+// returns which soft-deleted rows mods asked for.
+func newSoftDeleteQuery(exec boil.Executor, mods ...qm.QueryMod) (*queries.Query, softDeleted) {
+	e := &softDeleteExecutor{Executor: exec}
+	q := NewQuery(e, mods...)
+	queries.SetExecutor(q, exec)
+
+	return q, e.deleted
+}
@@ -0,0 +1,84 @@
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $nullImport := false -}}
+{{- $timeImport := false -}}
+{{- $typesImport := false -}}
+{{- range .Table.Columns -}}
+	{{- if eq (printf "%.5s" .Type) "null." -}}
+		{{- $nullImport = true -}}
+	{{- else if eq .Type "time.Time" -}}
+		{{- $timeImport = true -}}
+	{{- else if eq (printf "%.6s" .Type) "types." -}}
+		{{- $typesImport = true -}}
+	{{- end -}}
+	{{- /* soft delete tests set deleted_at to a time */ -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $timeImport = true -}}
+	{{- end -}}
+{{- end -}}
+import (
+	"context"
+	{{- if $timeImport}}
+	"time"
+	{{- end}}
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pkg/errors"
+	{{- if $typesImport}}
+	"github.com/vattle/sqlboiler/types"
+	{{- end}}
+	{{- if $nullImport}}
+	"gopkg.in/nullbio/null.v6"
+	{{- end}}
+)
+
+// Every test file is given the same imports, which those of a table may not use
+var (
+	_ = bytes.MinRead
+	_ = boil.DebugMode
+	_ = strmangle.Placeholders
+)
+
+var {{$varNameSingular}}DBTypes = map[string]string{{"{"}}{{.Table.Columns | columnDBTypes | makeStringMap}}{{"}"}}
+
+// random{{$tableNameSingular}} returns a {{$tableNameSingular}} holding random, non-null values,
+// as if it was built to be inserted.
+func random{{$tableNameSingular}}(t testing.TB) *{{$tableNameSingular}} {
+	var cols struct {
+		{{range .Table.Columns -}}
+		{{titleCase .Name}} {{.Type}}
+		{{end -}}
+	}
+	if err := randomize.Struct(seed, &cols, {{$varNameSingular}}DBTypes, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return &{{$tableNameSingular}}{
+		{{range .Table.Columns -}}
+		{{titleCase .Name}}: cols.{{titleCase .Name}},
+		{{end -}}
+	}
+}
+
+// selected{{$tableNameSingular}} returns a random {{$tableNameSingular}} as if it was selected.
+func selected{{$tableNameSingular}}(t testing.TB) *{{$tableNameSingular}} {
+	o := random{{$tableNameSingular}}(t)
+	o.readonly = &{{$tableNameSingular}}{}
+	*o.readonly = *o
+
+	return o
+}
+
+// check{{$tableNameSingular}} fails t unless the columns of got hold the values of want.
+func check{{$tableNameSingular}}(t *testing.T, got, want *{{$tableNameSingular}}) {
+	{{range .Table.Columns -}}
+	if !reflect.DeepEqual(got.{{titleCase .Name}}, want.{{titleCase .Name}}) {
+		t.Errorf("{{.Name}}: want %v, got %v", want.{{titleCase .Name}}, got.{{titleCase .Name}})
+	}
+	{{end -}}
+}
+
+// {{$varNameSingular}}Row returns the row of o, for sqlmock to return.
+func {{$varNameSingular}}Row(t *testing.T, o *{{$tableNameSingular}}) *sqlmock.Rows {
+	return sqlmock.NewRows({{$varNameSingular}}Columns).AddRow(mockRow(t, {{range $i, $c := .Table.Columns}}{{if $i}}, {{end}}o.{{titleCase $c.Name}}{{end}})...)
+}
@@ -0,0 +1,44 @@
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $colNames := .Table.Columns | columnNames}}
+func Test{{$tableNameSingular}}Insert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec(exact("INSERT INTO {{$schemaTable}} ({{.LQ}}{{$colNames | join (printf "%s,%s" .RQ .LQ)}}{{.RQ}}) VALUES ({{range $i, $c := $colNames}}{{if $i}},{{end}}?{{end}})")).
+		WithArgs({{range $i, $c := .Table.Columns}}{{if $i}}, {{end}}o.{{titleCase $c.Name}}{{end}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Insert(db, {{$varNameSingular}}Columns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- $defaults := .Table.Columns | filterColumnsByDefault true | columnNames -}}
+{{- if and .UseLastInsertID .Table.CanLastInsertID (eq (join "," $defaults) (join "," .Table.PKey.Columns))}}
+{{- $noDefaults := .Table.Columns | filterColumnsByDefault false -}}
+{{- $pkey := index .Table.PKey.Columns 0 | titleCase}}
+
+func Test{{$tableNameSingular}}InsertLastInsertID(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	o.{{$pkey}} = 0
+	mock.ExpectExec(exact("INSERT INTO {{$schemaTable}} ({{.LQ}}{{$noDefaults | columnNames | join (printf "%s,%s" .RQ .LQ)}}{{.RQ}}) VALUES ({{range $i, $c := $noDefaults}}{{if $i}},{{end}}?{{end}})")).
+		WithArgs({{range $i, $c := $noDefaults}}{{if $i}}, {{end}}o.{{titleCase $c.Name}}{{end}}).
+		WillReturnResult(sqlmock.NewResult(42, 1))
+
+	if err := o.Insert(db); err != nil {
+		t.Fatal(err)
+	}
+	if o.{{$pkey}} != 42 {
+		t.Errorf("want {{index .Table.PKey.Columns 0}} 42 from the last insert ID, got %v", o.{{$pkey}})
+	}
+
+	expectationsMet(t, mock)
+}
+{{- end}}
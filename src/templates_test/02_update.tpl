@@ -0,0 +1,106 @@
+{{- $dot := . -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $lockColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if or (and (eq .Name "version") (not .Nullable)) (eq .Name "updated_at") -}}
+		{{- $lockColumn = .Name -}}
+	{{- end -}}
+{{- end -}}
+{{- $enumColumns := .Table.Columns | filterColumnsByEnum | columnNames -}}
+{{- $changeColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (not $changeColumn) (not (setInclude .Name $dot.Table.PKey.Columns)) (not (setInclude .Name $enumColumns)) -}}
+		{{- $changeColumn = .Name -}}
+	{{- end -}}
+{{- end -}}
+{{- if not $lockColumn}}
+{{- $where := whereClause .LQ .RQ 0 .Table.PKey.Columns -}}
+{{- $set := "" -}}
+{{- $setArgs := "" -}}
+{{- range .Table.Columns -}}
+	{{- if not (setInclude .Name $dot.Table.PKey.Columns) -}}
+		{{- if $set -}}
+			{{- $set = printf "%s," $set -}}
+			{{- $setArgs = printf "%s, " $setArgs -}}
+		{{- end -}}
+		{{- $set = printf "%s%s%s%s=?" $set $dot.LQ .Name $dot.RQ -}}
+		{{- $setArgs = printf "%so.%s" $setArgs (titleCase .Name) -}}
+	{{- end -}}
+{{- end}}
+
+func Test{{$tableNameSingular}}Update(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec(exact("UPDATE {{$schemaTable}} SET {{$set}} WHERE {{$where}}")).
+		WithArgs({{$setArgs}}{{range .Table.PKey.Columns}}, o.{{titleCase .}}{{end}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- if $changeColumn}}
+{{- $changeField := titleCase $changeColumn}}
+
+func Test{{$tableNameSingular}}UpdateSelected(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$tableNameSingular}}(t).{{$changeField}}
+	}
+
+	mock.ExpectExec(exact("UPDATE {{$schemaTable}} SET {{.LQ}}{{$changeColumn}}{{.RQ}}=? WHERE {{$where}} AND {{.LQ}}{{$changeColumn}}{{.RQ}}=?")).
+		WithArgs(o.{{$changeField}}{{range .Table.PKey.Columns}}, o.{{titleCase .}}{{end}}, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "UPDATE", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{
+		{Name: "{{$changeColumn}}", Before: before, After: o.{{$changeField}}},
+	})
+
+	// The row now holds the new values, so o is unchanged again
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}UpdateStale(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := selected{{$tableNameSingular}}(t)
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$tableNameSingular}}(t).{{$changeField}}
+	}
+
+	mock.ExpectExec(exact("UPDATE {{$schemaTable}} SET {{.LQ}}{{$changeColumn}}{{.RQ}}=? WHERE {{$where}} AND {{.LQ}}{{$changeColumn}}{{.RQ}}=?")).
+		WithArgs(o.{{$changeField}}{{range .Table.PKey.Columns}}, o.{{titleCase .}}{{end}}, before).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := o.Update(db); err != ErrStaleObject {
+		t.Errorf("want ErrStaleObject, got %v", err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- end}}
+{{- end}}
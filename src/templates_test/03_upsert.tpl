@@ -0,0 +1,36 @@
+{{- if eq .DriverName "mysql" -}}
+{{- $dot := . -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $colNames := .Table.Columns | columnNames -}}
+{{- $insert := "" -}}
+{{- $update := "" -}}
+{{- range .Table.Columns -}}
+	{{- if $insert}}{{$insert = printf "%s, " $insert}}{{end -}}
+	{{- $insert = printf "%s%s" $insert ($dot.Quotes .Name) -}}
+	{{- if not (setInclude .Name $dot.Table.PKey.Columns) -}}
+		{{- if $update}}{{$update = printf "%s," $update}}{{end -}}
+		{{- $update = printf "%s%s = VALUES(%s)" $update ($dot.Quotes .Name) ($dot.Quotes .Name) -}}
+	{{- end -}}
+{{- end}}
+
+func Test{{$tableNameSingular}}Upsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec(exact("INSERT INTO {{.Table.Name}} ({{$insert}}) VALUES ({{range $i, $c := $colNames}}{{if $i}},{{end}}?{{end}}) ON DUPLICATE KEY UPDATE {{$update}}")).
+		WithArgs(
+			{{- range $i, $c := .Table.Columns -}}
+				{{- if $i}}, {{end -}}
+				{{- if and (not $dot.NoAutoTimestamps) (eq $c.Name "updated_at")}}sqlmock.AnyArg(){{else}}o.{{titleCase $c.Name}}{{end -}}
+			{{- end}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Upsert(db, nil, {{$varNameSingular}}Columns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- end}}
@@ -0,0 +1,196 @@
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $tableNamePlural := .Table.Name | plural | titleCase -}}
+{{- $pkArgs := .Table.PKey.Columns | stringMap .StringFuncs.titleCase | join ", o." -}}
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+{{- if $softDelete}}
+
+func Test{{$tableNameSingular}}Delete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	o.DeletedAt.Valid = false
+	mock.ExpectExec(exact("UPDATE {{.Table.Name | .SchemaTable}} SET {{"deleted_at" | .Quotes}}=? WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}} AND {{"deleted_at" | .Quotes}} IS NULL")).
+		WithArgs(sqlmock.AnyArg(), o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(db); err != nil {
+		t.Fatal(err)
+	}
+	if !o.DeletedAt.Valid {
+		t.Error("want deleted_at set on a soft deleted {{.Table.Name | singular}}")
+	}
+	if ns := o.DeletedAt.Time.Nanosecond(); ns != 0 {
+		t.Errorf("want deleted_at in whole seconds, got %dns more", ns)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}DeleteDeleted(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := random{{$tableNameSingular}}(t)
+	o.DeletedAt = null.TimeFrom(time.Now().Add(-time.Hour))
+	deletedAt := o.DeletedAt
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+	if o.DeletedAt != deletedAt {
+		t.Errorf("want deleted_at %v kept, got %v", deletedAt, o.DeletedAt)
+	}
+	if len(rec.changes) != 0 {
+		t.Errorf("want no changeset deleting a deleted {{.Table.Name | singular}}, got %d", len(rec.changes))
+	}
+	if o.operation != "" || o.whitelist != nil {
+		t.Errorf("want a deleted {{.Table.Name | singular}} left as it was, got operation %q on %v", o.operation, o.whitelist)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNamePlural}}SliceDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	o.DeletedAt.Valid = false
+	deleted := random{{$tableNameSingular}}(t)
+	deleted.DeletedAt = null.TimeFrom(time.Now().Add(-time.Hour))
+	deletedAt := deleted.DeletedAt
+	mock.ExpectExec(`^UPDATE .+ SET .deleted_at.=\? WHERE \(.+\) IN \(.+\) AND .deleted_at. IS NULL$`).
+		WithArgs(sqlmock.AnyArg(), o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := ({{$tableNameSingular}}Slice{o, deleted}).DeleteAll(db); err != nil {
+		t.Fatal(err)
+	}
+	if !o.DeletedAt.Valid {
+		t.Error("want deleted_at set on a soft deleted {{.Table.Name | singular}}")
+	}
+	if deleted.DeletedAt != deletedAt {
+		t.Errorf("want deleted_at %v kept, got %v", deletedAt, deleted.DeletedAt)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNamePlural}}QueryDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectExec(exact("UPDATE {{.Table.Name | .SchemaTable}} SET ({{"deleted_at" | .Quotes}}) = (?) WHERE ({{.Table.Name | .SchemaTable}}.{{"deleted_at" | .Quotes}} IS NULL);")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := {{$tableNamePlural}}(db).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rows deleted already are left alone, even when the query sees them
+	mock.ExpectExec(exact("UPDATE {{.Table.Name | .SchemaTable}} SET ({{"deleted_at" | .Quotes}}) = (?) WHERE ({{.Table.Name | .SchemaTable}}.{{"deleted_at" | .Quotes}} IS NULL);")).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := {{$tableNamePlural}}(db, WithDeleted()).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}HardDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec(exact("DELETE FROM {{.Table.Name | .SchemaTable}} WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.HardDelete(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}Restore(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	o.DeletedAt.Valid = true
+	mock.ExpectExec(exact("UPDATE {{.Table.Name | .SchemaTable}} SET {{"deleted_at" | .Quotes}}=? WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(nil, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Restore(db); err != nil {
+		t.Fatal(err)
+	}
+	if o.DeletedAt.Valid {
+		t.Error("want deleted_at cleared on a restored {{.Table.Name | singular}}")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNamePlural}}ExcludeDeleted(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM {{.Table.Name | .SchemaTable}} WHERE ({{.Table.Name | .SchemaTable}}.{{"deleted_at" | .Quotes}} IS NULL);")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	if _, err := {{$tableNamePlural}}(db).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNamePlural}}WithDeleted(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM {{.Table.Name | .SchemaTable}};")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM {{.Table.Name | .SchemaTable}} WHERE ({{.Table.Name | .SchemaTable}}.{{"deleted_at" | .Quotes}} IS NOT NULL);")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	if _, err := {{$tableNamePlural}}(db, WithDeleted()).Count(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := {{$tableNamePlural}}(db, OnlyDeleted()).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- else}}
+
+func Test{{$tableNameSingular}}Delete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec(exact("DELETE FROM {{.Table.Name | .SchemaTable}} WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- end}}
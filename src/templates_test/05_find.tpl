@@ -0,0 +1,65 @@
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $tableNamePlural := .Table.Name | plural | titleCase -}}
+{{- $pkArgs := .Table.PKey.Columns | stringMap .StringFuncs.titleCase | join ", o."}}
+
+func TestFind{{$tableNameSingular}}(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectQuery(exact("select * from {{.Table.Name | .SchemaTable}} where {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{if $softDelete}} and {{"deleted_at" | .Quotes}} is null{{end}}")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnRows({{$varNameSingular}}Row(t, o))
+
+	found, err := Find{{$tableNameSingular}}(db, o.{{$pkArgs}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	check{{$tableNameSingular}}(t, found, o)
+	if len(found.Whitelist()) != 0 {
+		t.Errorf("want a found {{.Table.Name | singular}} unchanged, got changes to %v", found.Whitelist())
+	}
+}
+
+func TestFind{{$tableNameSingular}}Canceled(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	o := random{{$tableNameSingular}}(t)
+	if _, err := Find{{$tableNameSingular}}Context(ctx, db, o.{{$pkArgs}}); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNamePlural}}Canceled(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := {{$tableNamePlural}}(db).AllContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+	// An executor that takes no context is still checked before the query
+	rec := &changeRecorder{Executor: db}
+	if err := {{$tableNamePlural}}(rec).DeleteAllContext(ctx); errors.Cause(err) != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	expectationsMet(t, mock)
+}
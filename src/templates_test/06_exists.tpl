@@ -0,0 +1,28 @@
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $pkArgs := .Table.PKey.Columns | stringMap .StringFuncs.titleCase | join ", o."}}
+
+func Test{{$tableNameSingular}}Exists(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectQuery(exact("select exists(select 1 from {{.Table.Name | .SchemaTable}} where {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}{{if $softDelete}} and {{"deleted_at" | .Quotes}} is null{{end}} limit 1)")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := {{$tableNameSingular}}Exists(db, o.{{$pkArgs}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Error("want the {{.Table.Name | singular}} to exist")
+	}
+
+	expectationsMet(t, mock)
+}
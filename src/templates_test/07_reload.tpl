@@ -0,0 +1,33 @@
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $pkArgs := .Table.PKey.Columns | stringMap .StringFuncs.titleCase | join ", o."}}
+
+func Test{{$tableNameSingular}}Reload(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	row := random{{$tableNameSingular}}(t)
+	{{range .Table.PKey.Columns -}}
+	row.{{titleCase .}} = o.{{titleCase .}}
+	{{end -}}
+	{{if $softDelete -}}
+	// A soft-deleted row is reloaded as well
+	{{end -}}
+	mock.ExpectQuery(exact("select * from {{.Table.Name | .SchemaTable}} where {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnRows({{$varNameSingular}}Row(t, row))
+
+	if err := o.Reload(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	check{{$tableNameSingular}}(t, o, row)
+}
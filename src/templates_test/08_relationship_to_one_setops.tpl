@@ -0,0 +1,100 @@
+{{- if .Table.IsJoinTable -}}
+{{- else -}}
+	{{- $dot := . -}}
+	{{- $lockColumn := "" -}}
+	{{- range .Table.Columns -}}
+		{{- if or (and (eq .Name "version") (not .Nullable)) (eq .Name "updated_at") -}}
+			{{- $lockColumn = .Name -}}
+		{{- end -}}
+	{{- end -}}
+	{{- $where := whereClause .LQ .RQ 0 .Table.PKey.Columns -}}
+	{{- $enumColumns := .Table.Columns | filterColumnsByEnum | columnNames -}}
+	{{- $pkArgs := .Table.PKey.Columns | stringMap .StringFuncs.titleCase | join ", o." -}}
+	{{- range .Table.FKeys -}}
+		{{- $txt := txtsFromFKey $dot.Tables $dot.Table . -}}
+		{{- $varNameSingular := .Table | singular | camelCase}}
+
+func Test{{$txt.LocalTable.NameGo}}Set{{$txt.Function.Name}}(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$txt.LocalTable.NameGo}}(t)
+	related := random{{$txt.ForeignTable.NameGo}}(t)
+	mock.ExpectExec(exact("UPDATE {{.Table | $dot.SchemaTable}} SET {{$dot.Quotes .Column}}=? WHERE {{$where}}")).
+		WithArgs(related.{{$txt.ForeignTable.ColumnNameGo}}, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Set{{$txt.Function.Name}}(db, false, related); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if o.R.{{$txt.Function.Name}} != related {
+		t.Error("want o.R.{{$txt.Function.Name}} set to related")
+	}
+}
+		{{- $fkColumn := .Column -}}
+		{{- $changeColumn := "" -}}
+		{{- range $dot.Table.Columns -}}
+			{{- if and (not $changeColumn) (ne .Name $fkColumn) (not (setInclude .Name $dot.Table.PKey.Columns)) (not (setInclude .Name $enumColumns)) -}}
+				{{- $changeColumn = .Name -}}
+			{{- end -}}
+		{{- end -}}
+		{{- if and $changeColumn (not $lockColumn)}}
+		{{- $changeField := titleCase $changeColumn}}
+
+func Test{{$txt.LocalTable.NameGo}}Set{{$txt.Function.Name}}ThenUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := selected{{$txt.LocalTable.NameGo}}(t)
+	related := random{{$txt.ForeignTable.NameGo}}(t)
+	mock.ExpectExec(exact("UPDATE {{.Table | $dot.SchemaTable}} SET {{$dot.Quotes .Column}}=? WHERE {{$where}}")).
+		WithArgs(related.{{$txt.ForeignTable.ColumnNameGo}}, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Set{{$txt.Function.Name}}(db, false, related); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new {{.Column}} is the row's, so it is neither updated nor checked
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$txt.LocalTable.NameGo}}(t).{{$changeField}}
+	}
+	mock.ExpectExec(exact("UPDATE {{.Table | $dot.SchemaTable}} SET {{$dot.Quotes $changeColumn}}=? WHERE {{$where}} AND {{$dot.Quotes $changeColumn}}=?")).
+		WithArgs(o.{{$changeField}}, o.{{$pkArgs}}, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Update(db); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+		{{- end}}
+		{{- if and .Nullable (not $lockColumn)}}
+
+func Test{{$txt.LocalTable.NameGo}}Remove{{$txt.Function.Name}}(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$txt.LocalTable.NameGo}}(t)
+	related := random{{$txt.ForeignTable.NameGo}}(t)
+	o.R = &{{$varNameSingular}}R{ {{- $txt.Function.Name}}: related}
+	mock.ExpectExec(exact("UPDATE {{.Table | $dot.SchemaTable}} SET {{$dot.Quotes .Column}}=? WHERE {{$where}}")).
+		WithArgs(nil, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Remove{{$txt.Function.Name}}(db, related); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if o.R.{{$txt.Function.Name}} != nil {
+		t.Error("want o.R.{{$txt.Function.Name}} cleared")
+	}
+}
+		{{- end -}}
+	{{- end -}}
+{{- end -}}
@@ -0,0 +1,109 @@
+{{- if .Table.IsJoinTable -}}
+{{- else -}}
+	{{- $dot := . -}}
+	{{- $table := .Table -}}
+	{{- range .Table.ToManyRelationships -}}
+		{{- if not .ToJoinTable -}}
+		{{- $txt := txtsFromToMany $dot.Tables $table . -}}
+		{{- $foreignTable := getTable $dot.Tables .ForeignTable -}}
+		{{- $lockColumn := "" -}}
+		{{- range $foreignTable.Columns -}}
+			{{- if or (and (eq .Name "version") (not .Nullable)) (eq .Name "updated_at") -}}
+				{{- $lockColumn = .Name -}}
+			{{- end -}}
+		{{- end -}}
+		{{- if not $lockColumn -}}
+		{{- $update := printf "UPDATE %s SET %s=? WHERE %s" (.ForeignTable | $dot.SchemaTable) ($dot.Quotes .ForeignColumn) (whereClause $dot.LQ $dot.RQ 0 $foreignTable.PKey.Columns) -}}
+		{{- $relPKArgs := $foreignTable.PKey.Columns | stringMap $dot.StringFuncs.titleCase | join ", rel."}}
+
+func Test{{$txt.LocalTable.NameGo}}Add{{$txt.Function.Name}}(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$txt.LocalTable.NameGo}}(t)
+	rel := random{{$txt.ForeignTable.NameGo}}(t)
+	mock.ExpectExec(exact("{{$update}}")).
+		WithArgs(o.{{$txt.LocalTable.ColumnNameGo}}, rel.{{$relPKArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Add{{$txt.Function.Name}}(db, false, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(o.R.{{$txt.Function.Name}}) != 1 || o.R.{{$txt.Function.Name}}[0] != rel {
+		t.Error("want rel added to o.R.{{$txt.Function.Name}}")
+	}
+}
+			{{- if .ForeignColumnNullable}}
+
+func Test{{$txt.LocalTable.NameGo}}Set{{$txt.Function.Name}}(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$txt.LocalTable.NameGo}}(t)
+	rel := random{{$txt.ForeignTable.NameGo}}(t)
+	mock.ExpectExec(exact("update {{.ForeignTable | $dot.SchemaTable}} set {{$dot.Quotes .ForeignColumn}} = null where {{$dot.Quotes .ForeignColumn}} = ?")).
+		WithArgs(o.{{$txt.LocalTable.ColumnNameGo}}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(exact("{{$update}}")).
+		WithArgs(o.{{$txt.LocalTable.ColumnNameGo}}, rel.{{$relPKArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Set{{$txt.Function.Name}}(db, false, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$txt.LocalTable.NameGo}}Set{{$txt.Function.Name}}Selected(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	// rel already belongs to o, so the update adding it back checks that the
+	// row was cleared
+	o := random{{$txt.LocalTable.NameGo}}(t)
+	rel := random{{$txt.ForeignTable.NameGo}}(t)
+	rel.{{$txt.Function.ForeignAssignment}} = o.{{$txt.Function.LocalAssignment}}
+	rel.{{$txt.ForeignTable.ColumnNameGo}}.Valid = true
+	rel.readonly = &{{$txt.ForeignTable.NameGo}}{}
+	*rel.readonly = *rel
+	mock.ExpectExec(exact("update {{.ForeignTable | $dot.SchemaTable}} set {{$dot.Quotes .ForeignColumn}} = null where {{$dot.Quotes .ForeignColumn}} = ?")).
+		WithArgs(o.{{$txt.LocalTable.ColumnNameGo}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(exact("{{$update}} AND {{$dot.Quotes .ForeignColumn}} IS NULL")).
+		WithArgs(o.{{$txt.LocalTable.ColumnNameGo}}, rel.{{$relPKArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Set{{$txt.Function.Name}}(db, false, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$txt.LocalTable.NameGo}}Remove{{$txt.Function.Name}}(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$txt.LocalTable.NameGo}}(t)
+	rel := random{{$txt.ForeignTable.NameGo}}(t)
+	mock.ExpectExec(exact("{{$update}}")).
+		WithArgs(nil, rel.{{$relPKArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Remove{{$txt.Function.Name}}(db, rel); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if rel.{{$txt.ForeignTable.ColumnNameGo}}.Valid {
+		t.Error("want rel.{{$txt.ForeignTable.ColumnNameGo}} set to null")
+	}
+}
+			{{- end -}}
+		{{- end -}}
+		{{- end -}}
+	{{- end -}}
+{{- end -}}
@@ -0,0 +1,352 @@
+{{- $dot := . -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $tableNamePlural := .Table.Name | plural | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $enumColumns := .Table.Columns | filterColumnsByEnum | columnNames -}}
+{{- $softDelete := false -}}
+{{- $lockColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+	{{- if or (and (eq .Name "version") (not .Nullable)) (eq .Name "updated_at") -}}
+		{{- $lockColumn = .Name -}}
+	{{- end -}}
+{{- end -}}
+{{- $changeColumn := "" -}}
+{{- $changeNullable := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (not $changeColumn) (not (setInclude .Name $dot.Table.PKey.Columns)) (not (setInclude .Name $enumColumns)) -}}
+		{{- $changeColumn = .Name -}}
+		{{- $changeNullable = .Nullable -}}
+	{{- end -}}
+{{- end}}
+
+func Test{{$tableNameSingular}}Whitelist(t *testing.T) {
+	o := random{{$tableNameSingular}}(t)
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, {{$varNameSingular}}Columns) {
+		t.Errorf("want every column of an unselected {{.Table.Name | singular}}, got %v", wl)
+	}
+
+	o = selected{{$tableNameSingular}}(t)
+	if wl := o.Whitelist(); len(wl) != 0 {
+		t.Errorf("want no column of an unchanged {{.Table.Name | singular}}, got %v", wl)
+	}
+	{{- if $changeColumn}}
+	{{- $changeField := titleCase $changeColumn}}
+
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$tableNameSingular}}(t).{{$changeField}}
+	}
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, []string{"{{$changeColumn}}"}) {
+		t.Errorf("want [{{$changeColumn}}], got %v", wl)
+	}
+	{{- end}}
+}
+
+func Test{{$tableNameSingular}}ChangesInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Insert(rec, {{$varNameSingular}}Columns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "INSERT", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{
+		{{range .Table.Columns -}}
+		{Name: "{{.Name}}", After: o.{{titleCase .Name}}},
+		{{end -}}
+	})
+}
+
+func Test{{$tableNameSingular}}ChangesIdentified(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// The context of the write names the actor, though rec carries none
+	ctx := WithRequestID(WithActor(context.Background(), "alice"), "req-1")
+	if err := o.InsertContext(ctx, rec, {{$varNameSingular}}Columns...); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(rec.changes) != 1 {
+		t.Fatalf("want 1 changeset, got %d", len(rec.changes))
+	}
+	if ch := rec.changes[0]; ch.Actor != "alice" || ch.RequestID != "req-1" {
+		t.Errorf("want changeset by alice in req-1, got by %q in %q", ch.Actor, ch.RequestID)
+	}
+}
+
+func Test{{$tableNameSingular}}ChangesDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	{{- if $softDelete}}
+	o.DeletedAt = null.Time{}
+	*o.readonly = *o
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// A soft deleted row is recorded with the deleted_at it was given
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "SOFT_DELETE", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{
+		{Name: "deleted_at", Before: null.Time{}, After: o.DeletedAt},
+	})
+}
+
+func Test{{$tableNameSingular}}ChangesHardDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	{{- end}}
+	{{- if $changeColumn}}
+	o.{{titleCase $changeColumn}} = random{{$tableNameSingular}}(t).{{titleCase $changeColumn}}
+	{{- end}}
+	mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := o.{{if $softDelete}}HardDelete{{else}}Delete{{end}}(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// A deleted row is recorded with the values it was selected with
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "DELETE", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{
+		{{range .Table.Columns -}}
+		{Name: "{{.Name}}", Before: o.readonly.{{titleCase .Name}}},
+		{{end -}}
+	})
+}
+{{- if and $changeColumn (not $lockColumn)}}
+{{- $changeField := titleCase $changeColumn}}
+
+func Test{{$tableNameSingular}}ChangesRedacted(t *testing.T) {
+	SetSensitive("{{.Table.Name}}", "{{$changeColumn}}", Redact)
+	defer SetSensitive("{{.Table.Name}}", "{{$changeColumn}}", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$tableNameSingular}}(t).{{$changeField}}
+	}
+
+	// A redacted column is still seen to change, with its values masked
+	if wl := o.Whitelist(); !reflect.DeepEqual(wl, []string{"{{$changeColumn}}"}) {
+		t.Errorf("want [{{$changeColumn}}], got %v", wl)
+	}
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "UPDATE", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{
+		{Name: "{{$changeColumn}}", Before: RedactedValue, After: RedactedValue},
+	})
+}
+{{- if $changeNullable}}
+
+func Test{{$tableNameSingular}}ChangesRedactedNull(t *testing.T) {
+	SetSensitive("{{.Table.Name}}", "{{$changeColumn}}", Redact)
+	defer SetSensitive("{{.Table.Name}}", "{{$changeColumn}}", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	for !o.{{$changeField}}.Valid {
+		o = selected{{$tableNameSingular}}(t)
+	}
+	before := o.{{$changeField}}
+	o.{{$changeField}}.Valid = false
+
+	// Setting a redacted column to NULL reads as such, NULL is no secret
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "{{$changeColumn}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "UPDATE", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{
+		{Name: "{{$changeColumn}}", Before: RedactedValue, After: o.{{$changeField}}},
+	})
+
+	// and reverting it leaves the masked value alone
+	if _, cols, err := {{$varNameSingular}}FromChangeset(rec.changes[0], true); err != nil || len(cols) != 1 {
+		t.Errorf("want the NULL of {{$changeColumn}} applied, got %v, %v", cols, err)
+	}
+	if _, cols, err := {{$varNameSingular}}FromChangeset(rec.changes[0], false); err != nil || len(cols) != 0 {
+		t.Errorf("want %v left alone, got %v, %v", before, cols, err)
+	}
+}
+{{- end}}
+
+func Test{{$tableNameSingular}}ChangesExcluded(t *testing.T) {
+	SetSensitive("{{.Table.Name}}", "{{$changeColumn}}", Exclude)
+	defer SetSensitive("{{.Table.Name}}", "{{$changeColumn}}", Tracked)
+
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$tableNameSingular}}(t).{{$changeField}}
+	}
+
+	// An excluded column is still written, and left out of the changeset
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	checkChanges(t, rec, "{{.Table.Name}}", "UPDATE", map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}, []*ChangeItem{})
+}
+{{- end}}
+
+
+func Test{{$tableNamePlural}}ChangesDeleteAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	a, b := random{{$tableNameSingular}}(t), random{{$tableNameSingular}}(t)
+	{{- if $softDelete}}
+	a.DeletedAt, b.DeletedAt = null.Time{}, null.Time{}
+	{{- end}}
+	rows := {{$varNameSingular}}Row(t, a)
+	rows.AddRow(mockRow(t, {{range $i, $c := .Table.Columns}}{{if $i}}, {{end}}b.{{titleCase $c.Name}}{{end}})...)
+	mock.ExpectQuery("^SELECT").WillReturnRows(rows)
+	mock.ExpectExec("^{{if $softDelete}}UPDATE{{else}}DELETE FROM{{end}}").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := {{$tableNamePlural}}(rec).WithChanges().DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every row deleted is recorded with the values it was selected with
+	expectationsMet(t, mock)
+	if len(rec.changes) != 2 {
+		t.Fatalf("want 2 changesets, got %d", len(rec.changes))
+	}
+	for i, o := range []*{{$tableNameSingular}}{a, b} {
+		pk := map[string]interface{}{
+			{{range .Table.PKey.Columns -}}
+			"{{.}}": o.{{titleCase .}},
+			{{end -}}
+		}
+		{{- if $softDelete}}
+		ch := rec.changes[i]
+		if len(ch.Changes) != 1 {
+			t.Fatalf("want 1 change, got %d", len(ch.Changes))
+		}
+		deletedAt, _ := ch.Changes[0].After.(null.Time)
+		checkChangeset(t, ch, "{{.Table.Name}}", "SOFT_DELETE", pk, []*ChangeItem{
+			{Name: "deleted_at", Before: null.Time{}, After: deletedAt},
+		})
+		if !deletedAt.Valid {
+			t.Errorf("want deleted_at set, got %v", ch.Changes[0].After)
+		}
+		{{- else}}
+		checkChangeset(t, rec.changes[i], "{{.Table.Name}}", "DELETE", pk, []*ChangeItem{
+			{{range .Table.Columns -}}
+			{Name: "{{.Name}}", Before: o.{{titleCase .Name}}},
+			{{end -}}
+		})
+		{{- end}}
+	}
+
+	// Without WithChanges no row is selected, nor recorded
+	rec.changes = nil
+	mock.ExpectExec("^{{if $softDelete}}UPDATE{{else}}DELETE FROM{{end}}").WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := {{$tableNamePlural}}(rec).DeleteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+	if len(rec.changes) != 0 {
+		t.Errorf("want no changeset, got %d", len(rec.changes))
+	}
+}
+{{- if $changeColumn}}
+{{- $changeField := titleCase $changeColumn}}
+
+func Test{{$tableNamePlural}}ChangesUpdateAll(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	a, b := random{{$tableNameSingular}}(t), random{{$tableNameSingular}}(t)
+	after := random{{$tableNameSingular}}(t).{{$changeField}}
+	rows := {{$varNameSingular}}Row(t, a)
+	rows.AddRow(mockRow(t, {{range $i, $c := .Table.Columns}}{{if $i}}, {{end}}b.{{titleCase $c.Name}}{{end}})...)
+	mock.ExpectQuery("^SELECT").WillReturnRows(rows)
+	mock.ExpectExec("^UPDATE").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := {{$tableNamePlural}}(rec).WithChanges().UpdateAll(M{"{{$changeColumn}}": after}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every row updated is recorded, from the value it was selected with
+	expectationsMet(t, mock)
+	if len(rec.changes) != 2 {
+		t.Fatalf("want 2 changesets, got %d", len(rec.changes))
+	}
+	for i, o := range []*{{$tableNameSingular}}{a, b} {
+		checkChangeset(t, rec.changes[i], "{{.Table.Name}}", "UPDATE", map[string]interface{}{
+			{{range .Table.PKey.Columns -}}
+			"{{.}}": o.{{titleCase .}},
+			{{end -}}
+		}, []*ChangeItem{
+			{Name: "{{$changeColumn}}", Before: o.{{$changeField}}, After: after},
+		})
+	}
+}
+{{- end}}
@@ -0,0 +1,58 @@
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $tableNamePlural := .Table.Name | plural | titleCase -}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $deleted := "" -}}
+{{- $deletedBeforeIn := "" -}}
+{{- if $softDelete -}}
+	{{- $deleted = printf " AND (%s.%s IS NULL)" $schemaTable ("deleted_at" | .Quotes) -}}
+	{{- $deletedBeforeIn = printf "(%s.%s IS NULL) AND " $schemaTable ("deleted_at" | .Quotes) -}}
+{{- end -}}
+{{- $pkColumn := index .Table.PKey.Columns 0 -}}
+{{- $nullColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (not $nullColumn) .Nullable -}}
+		{{- $nullColumn = .Name -}}
+	{{- end -}}
+{{- end}}
+
+func Test{{$tableNamePlural}}Where(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	count := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"count"}).AddRow(1)
+	}
+
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM {{$schemaTable}} WHERE ({{$pkColumn | .Quotes}} = ?){{$deleted}};")).
+		WithArgs(o.{{titleCase $pkColumn}}).
+		WillReturnRows(count())
+	if _, err := {{$tableNamePlural}}(db, {{$tableNameSingular}}Where.{{titleCase $pkColumn}}.EQ(o.{{titleCase $pkColumn}})).Count(); err != nil {
+		t.Fatal(err)
+	}
+
+	// IN clauses are built after the other where clauses
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM {{$schemaTable}} WHERE {{$deletedBeforeIn}}{{$pkColumn | .Quotes}} IN (?,?);")).
+		WithArgs(o.{{titleCase $pkColumn}}, o.{{titleCase $pkColumn}}).
+		WillReturnRows(count())
+	if _, err := {{$tableNamePlural}}(db, {{$tableNameSingular}}Where.{{titleCase $pkColumn}}.IN(o.{{titleCase $pkColumn}}, o.{{titleCase $pkColumn}})).Count(); err != nil {
+		t.Fatal(err)
+	}
+	{{- if $nullColumn}}
+
+	o.{{titleCase $nullColumn}}.Valid = false
+	mock.ExpectQuery(exact("SELECT COUNT(*) FROM {{$schemaTable}} WHERE ({{$nullColumn | .Quotes}} IS NULL){{$deleted}};")).
+		WillReturnRows(count())
+	if _, err := {{$tableNamePlural}}(db, {{$tableNameSingular}}Where.{{titleCase $nullColumn}}.EQ(o.{{titleCase $nullColumn}})).Count(); err != nil {
+		t.Fatal(err)
+	}
+	{{- end}}
+
+	expectationsMet(t, mock)
+}
@@ -0,0 +1,52 @@
+{{- $softDelete := false -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+{{- end -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $tableNamePlural := .Table.Name | plural | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $deleted := "" -}}
+{{- if $softDelete -}}
+	{{- $deleted = printf "(%s.%s IS NULL) AND " $schemaTable ("deleted_at" | .Quotes) -}}
+{{- end -}}
+{{- $pkColumn := index .Table.PKey.Columns 0}}
+
+func Test{{$tableNamePlural}}Page(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	rows := {{$varNameSingular}}Row(t, o)
+	rows.AddRow(mockRow(t, {{range $i, $c := .Table.Columns}}{{if $i}}, {{end}}o.{{titleCase $c.Name}}{{end}})...)
+
+	// A row more than the page tells there is a next one
+	mock.ExpectQuery(exact("SELECT * FROM {{$schemaTable}}{{if $softDelete}} WHERE ({{$schemaTable}}.{{"deleted_at" | .Quotes}} IS NULL){{end}} ORDER BY {{$pkColumn | .Quotes}} ASC LIMIT 2;")).
+		WillReturnRows(rows)
+	page, cursors, err := {{$tableNamePlural}}(db).PageSize(1).Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || cursors.Next == "" || cursors.Prev != "" {
+		t.Fatalf("want the first of several pages, got %d rows and cursors %+v", len(page), cursors)
+	}
+
+	mock.ExpectQuery(exact("SELECT * FROM {{$schemaTable}} WHERE {{$deleted}}(({{$pkColumn | .Quotes}} > ?)) ORDER BY {{$pkColumn | .Quotes}} ASC LIMIT 2;")).
+		WithArgs(o.{{titleCase $pkColumn}}).
+		WillReturnRows({{$varNameSingular}}Row(t, o))
+	page, cursors, err = {{$tableNamePlural}}(db).PageSize(1).After(cursors.Next).Page()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || cursors.Next != "" || cursors.Prev == "" {
+		t.Fatalf("want the last of several pages, got %d rows and cursors %+v", len(page), cursors)
+	}
+
+	if _, _, err := {{$tableNamePlural}}(db).After("not a cursor").Page(); errors.Cause(err) != ErrInvalidCursor {
+		t.Errorf("want %v, got %v", ErrInvalidCursor, err)
+	}
+
+	expectationsMet(t, mock)
+}
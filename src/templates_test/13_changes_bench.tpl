@@ -0,0 +1,111 @@
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $modColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (not $modColumn) (not (setInclude .Name $.Table.PKey.Columns)) -}}
+		{{- $modColumn = .Name -}}
+	{{- end -}}
+{{- end -}}
+{{- if $modColumn}}
+
+// reflect{{$tableNameSingular}}Whitelist and reflect{{$tableNameSingular}}Changes are the reflection based
+// versions of Whitelist and Changes that the generated comparisons replaced,
+// kept to benchmark against.
+func reflect{{$tableNameSingular}}Whitelist(o *{{$tableNameSingular}}) (wl []string) {
+	if len(o.whitelist) > 0 {
+		return o.whitelist
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(o.readonly))
+	vnew := reflect.Indirect(reflect.ValueOf(o))
+
+	for _, c := range {{$varNameSingular}}Columns {
+		if f, ok := {{$tableNameSingular}}FieldMapping[c]; ok {
+			var before, after interface{}
+			if v.IsValid() {
+				before = v.FieldByName(f).Interface()
+			}
+
+			if vnew.IsValid() {
+				after = vnew.FieldByName(f).Interface()
+			}
+			if !reflect.DeepEqual(before, after) || o.operation == "DELETE" {
+				wl = append(wl, c)
+			}
+		}
+	}
+
+	return
+}
+
+func reflect{{$tableNameSingular}}Changes(o *{{$tableNameSingular}}) *Changeset {
+	ch := &Changeset{Table: "{{.Table.Name}}", Changes: []*ChangeItem{}, Operation: o.Operation()}
+	v := reflect.Indirect(reflect.ValueOf(o.readonly))
+	vnew := reflect.Indirect(reflect.ValueOf(o))
+
+	for _, c := range reflect{{$tableNameSingular}}Whitelist(o) {
+		if f, ok := {{$tableNameSingular}}FieldMapping[c]; ok {
+			var before, after interface{}
+			if v.IsValid() {
+				before = v.FieldByName(f).Interface()
+			}
+
+			if vnew.IsValid() {
+				after = vnew.FieldByName(f).Interface()
+			}
+
+			chitem := &ChangeItem{Name: c, Before: before}
+			if o.operation != "DELETE" {
+				chitem.After = after
+			}
+
+			if !reflect.DeepEqual(chitem.Before, chitem.After) {
+				ch.Changes = append(ch.Changes, chitem)
+			}
+		}
+	}
+
+	return ch
+}
+
+// modified{{$tableNameSingular}} returns a selected {{$tableNameSingular}} with its {{$modColumn}} changed.
+func modified{{$tableNameSingular}}(b *testing.B) *{{$tableNameSingular}} {
+	o := selected{{$tableNameSingular}}(b)
+	o.operation = "UPDATE"
+	o.{{titleCase $modColumn}} = random{{$tableNameSingular}}(b).{{titleCase $modColumn}}
+
+	return o
+}
+
+func Benchmark{{$tableNameSingular}}Whitelist(b *testing.B) {
+	o := modified{{$tableNameSingular}}(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Whitelist()
+	}
+}
+
+func Benchmark{{$tableNameSingular}}WhitelistReflect(b *testing.B) {
+	o := modified{{$tableNameSingular}}(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reflect{{$tableNameSingular}}Whitelist(o)
+	}
+}
+
+func Benchmark{{$tableNameSingular}}Changes(b *testing.B) {
+	o := modified{{$tableNameSingular}}(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Changes()
+	}
+}
+
+func Benchmark{{$tableNameSingular}}ChangesReflect(b *testing.B) {
+	o := modified{{$tableNameSingular}}(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reflect{{$tableNameSingular}}Changes(o)
+	}
+}
+{{- end}}
@@ -0,0 +1,195 @@
+{{- $dot := . -}}
+{{- $tableNameSingular := .Table.Name | singular | titleCase -}}
+{{- $varNameSingular := .Table.Name | singular | camelCase -}}
+{{- $schemaTable := .Table.Name | .SchemaTable -}}
+{{- $pkArgs := .Table.PKey.Columns | stringMap .StringFuncs.titleCase | join ", o." -}}
+{{- $enumColumns := .Table.Columns | filterColumnsByEnum | columnNames -}}
+{{- $softDelete := false -}}
+{{- $lockColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (eq .Name "deleted_at") .Nullable -}}
+		{{- $softDelete = true -}}
+	{{- end -}}
+	{{- if or (and (eq .Name "version") (not .Nullable)) (eq .Name "updated_at") -}}
+		{{- $lockColumn = .Name -}}
+	{{- end -}}
+{{- end -}}
+{{- $changeColumn := "" -}}
+{{- range .Table.Columns -}}
+	{{- if and (not $changeColumn) (not (setInclude .Name $dot.Table.PKey.Columns)) (not (setInclude .Name $enumColumns)) (ne .Name "deleted_at") -}}
+		{{- $changeColumn = .Name -}}
+	{{- end -}}
+{{- end}}
+
+func Test{{$tableNameSingular}}ApplyInsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Insert(rec, {{$varNameSingular}}Columns...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaying the insert inserts the row again, undoing it deletes the row
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(exact("DELETE FROM {{$schemaTable}} WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Apply(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := Revert{{$tableNameSingular}}(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}RevertDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	mock.ExpectExec("DELETE FROM").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.{{if $softDelete}}HardDelete{{else}}Delete{{end}}(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("INSERT INTO").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- if and $changeColumn (not $lockColumn)}}
+{{- $changeField := titleCase $changeColumn}}
+
+func Test{{$tableNameSingular}}RevertUpdate(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	before := o.{{$changeField}}
+	for reflect.DeepEqual(o.{{$changeField}}, before) {
+		o.{{$changeField}} = random{{$tableNameSingular}}(t).{{$changeField}}
+	}
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "{{$changeColumn}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undoing the update sets the column back to the value it was selected with
+	mock.ExpectExec(exact("UPDATE {{$schemaTable}} SET {{$changeColumn | .Quotes}}=? WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(before, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}RevertUpdateUnknown(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	// An object that was not selected does not know the values it replaced
+	o := random{{$tableNameSingular}}(t)
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Update(rec, "{{$changeColumn}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := rec.changes[0]
+	if len(ch.Changes) != 1 || !ch.Changes[0].BeforeUnknown {
+		t.Fatalf("want the before value of {{$changeColumn}} unknown, got %+v", ch.Changes)
+	}
+	if err := Revert(db, ch); err == nil {
+		t.Error("want an error reverting an update with unknown before values")
+	}
+
+	expectationsMet(t, mock)
+}
+
+func Test{{$tableNameSingular}}RevertUpsert(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+
+	o := random{{$tableNameSingular}}(t)
+	before := random{{$tableNameSingular}}(t)
+	pk := map[string]interface{}{
+		{{range .Table.PKey.Columns -}}
+		"{{.}}": o.{{titleCase .}},
+		{{end -}}
+	}
+
+	// Without before values the upsert inserted the row, and is undone by
+	// deleting it
+	inserted := &Changeset{Table: "{{.Table.Name}}", PrimaryKey: pk, Operation: "UPSERT", Changes: []*ChangeItem{
+		{Name: "{{$changeColumn}}", After: o.{{$changeField}}},
+	}}
+	mock.ExpectExec(exact("DELETE FROM {{$schemaTable}} WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, inserted); err != nil {
+		t.Fatal(err)
+	}
+
+	// With them it updated the row, and is undone by restoring them
+	updated := &Changeset{Table: "{{.Table.Name}}", PrimaryKey: pk, Operation: "UPSERT", Changes: []*ChangeItem{
+		{Name: "{{$changeColumn}}", Before: before.{{$changeField}}, After: o.{{$changeField}}},
+	}}
+	mock.ExpectExec(exact("UPDATE {{$schemaTable}} SET {{$changeColumn | .Quotes}}=? WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(before.{{$changeField}}, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, updated); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- end}}
+{{- if $softDelete}}
+
+func Test{{$tableNameSingular}}RevertSoftDelete(t *testing.T) {
+	db, mock := mockDB(t)
+	defer db.Close()
+	rec := &changeRecorder{Executor: db}
+
+	o := selected{{$tableNameSingular}}(t)
+	o.DeletedAt = null.Time{}
+	*o.readonly = *o
+	mock.ExpectExec("UPDATE").WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := o.Delete(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	// Undoing a soft delete clears deleted_at
+	mock.ExpectExec(exact("UPDATE {{$schemaTable}} SET {{"deleted_at" | .Quotes}}=? WHERE {{whereClause .LQ .RQ 0 .Table.PKey.Columns}}")).
+		WithArgs(nil, o.{{$pkArgs}}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := Revert(db, rec.changes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	expectationsMet(t, mock)
+}
+{{- end}}
+
+func Test{{$tableNameSingular}}RevertOtherTable(t *testing.T) {
+	if err := Revert{{$tableNameSingular}}(nil, &Changeset{Table: "not_{{.Table.Name}}"}); err == nil {
+		t.Error("want an error reverting changes of another table")
+	}
+}
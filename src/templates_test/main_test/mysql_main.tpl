@@ -0,0 +1,33 @@
+import (
+	"flag"
+	"testing"
+
+	"github.com/vattle/sqlboiler/boil"
+)
+
+// The imports of a TestMain are those of tests run against a live MySQL
+// database, which these tests don't need
+var (
+	_ = bytes.MinRead
+	_ = sql.ErrNoRows
+	_ = fmt.Sprint
+	_ = io.EOF
+	_ = ioutil.Discard
+	_ = exec.Command
+	_ = strings.TrimSpace
+	_ = errors.New
+	_ = viper.New
+	_ = drivers.NewMySQLDriver
+	_ = randomize.Struct
+)
+
+var flagDebugMode = flag.Bool("test.sqldebug", false, "Turns on debug mode for SQL statements")
+
+// TestMain runs the tests of the models against sqlmock, so they need no
+// database. Run them with -test.sqldebug to print the statements they build.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	boil.DebugMode = *flagDebugMode
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,92 @@
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/vattle/sqlboiler/boil"
+	"github.com/vattle/sqlboiler/randomize"
+)
+
+// seed makes the values of random objects unique across the tests.
+var seed = randomize.NewSeed()
+
+// mockDB returns a database whose statements are checked against the
+// expectations set on the returned mock.
+func mockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, mock
+}
+
+// exact matches query, and only query.
+func exact(query string) string {
+	return "^" + regexp.QuoteMeta(query) + "$"
+}
+
+// expectationsMet fails t unless every expected statement was run.
+func expectationsMet(t *testing.T, mock sqlmock.Sqlmock) {
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// mockRow converts the column values of a model to a row of sqlmock.Rows,
+// the way database/sql converts the arguments of a statement.
+func mockRow(t *testing.T, vs ...interface{}) []driver.Value {
+	row := make([]driver.Value, len(vs))
+	for i, v := range vs {
+		dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row[i] = dv
+	}
+
+	return row
+}
+
+// changeRecorder is a Changeable executor keeping the Changesets of the
+// models written through it.
+type changeRecorder struct {
+	boil.Executor
+	changes []*Changeset
+}
+
+func (r *changeRecorder) AddChange(chs ...*Changeset) {
+	r.changes = append(r.changes, chs...)
+}
+
+// checkChanges fails t unless rec recorded a single Changeset of op on the
+// row of table with primary key pk, holding the changes want.
+func checkChanges(t *testing.T, rec *changeRecorder, table, op string, pk map[string]interface{}, want []*ChangeItem) {
+	if len(rec.changes) != 1 {
+		t.Fatalf("want 1 changeset, got %d", len(rec.changes))
+	}
+
+	checkChangeset(t, rec.changes[0], table, op, pk, want)
+}
+
+// checkChangeset fails t unless ch is a Changeset of op on the row of table
+// with primary key pk, holding the changes want.
+func checkChangeset(t *testing.T, ch *Changeset, table, op string, pk map[string]interface{}, want []*ChangeItem) {
+	if ch.Table != table || ch.Operation != op {
+		t.Errorf("want changeset of %s on %s, got %s on %s", op, table, ch.Operation, ch.Table)
+	}
+	if !reflect.DeepEqual(ch.PrimaryKey, pk) {
+		t.Errorf("want primary key %v, got %v", pk, ch.PrimaryKey)
+	}
+	if len(ch.Changes) != len(want) {
+		t.Fatalf("want %d changes, got %d", len(want), len(ch.Changes))
+	}
+	for i, item := range ch.Changes {
+		if !reflect.DeepEqual(item, want[i]) {
+			t.Errorf("change %d: want %+v, got %+v", i, *want[i], *item)
+		}
+	}
+}